@@ -21,8 +21,6 @@ import (
 	"unsafe"
 	"os"
 
-	"suse.com/virtx/pkg/model"
-
 	"github.com/spf13/cobra"
 )
 
@@ -30,11 +28,18 @@ var cmd = &cobra.Command{
 	Use:   "virtx",
 	Short: "manage VirtX VMs and Hosts",
 	Long:  "manage VirtX VMs and Hosts by connecting to a VIRTX_API_SERVER",
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return cmd_init()
+	},
 }
 
 func init() {
-	cmd.PersistentFlags().StringVarP(&virtx.api_server, "api-server", "A", os.Getenv("VIRTX_API_SERVER"), "The VIRTX_API_SERVER to use. Defaults to the env variable.")
+	cmd.PersistentFlags().StringVarP(&virtx.api_server, "api-server", "A", os.Getenv("VIRTX_API_SERVER"), "The VIRTX_API_SERVER to use, or a comma separated list of alternatives. Defaults to the env variable.")
 	cmd.PersistentFlags().BoolVarP(&virtx.debug, "debug", "D", false, "produce more verbose debug output")
+	cmd.PersistentFlags().StringVar(&virtx.token, "token", os.Getenv("VIRTX_TOKEN"), "The bearer token to authenticate with. Defaults to the env variable VIRTX_TOKEN.")
+	cmd.PersistentFlags().StringVar(&virtx.tls_ca, "tls-ca", os.Getenv("VIRTX_TLS_CA"), "PEM CA bundle to verify the API server. Defaults to the env variable VIRTX_TLS_CA.")
+	cmd.PersistentFlags().StringVar(&virtx.tls_cert, "tls-cert", os.Getenv("VIRTX_TLS_CERT"), "PEM client certificate. Defaults to the env variable VIRTX_TLS_CERT.")
+	cmd.PersistentFlags().StringVar(&virtx.tls_key, "tls-key", os.Getenv("VIRTX_TLS_KEY"), "PEM client private key. Defaults to the env variable VIRTX_TLS_KEY.")
 	var cmd_list = &cobra.Command{
 		Use:   "list",
 		Short: "List resources and display them in table format",
@@ -44,13 +49,7 @@ func init() {
 		Short: "List hosts in the cluster",
 		Long:  "List all hosts in the cluster, or optionally applying filters (AND)",
 		Run: func(cmd *cobra.Command, args []string) {
-			if (virtx.ok) {
-				if (virtx.result != nil) {
-					host_list(virtx.result.(*openapi.HostList))
-				}
-			} else {
-				host_list_req()
-			}
+			host_list_req()
 		},
 	}
 	cmd_list_host.Flags().StringVarP(&virtx.host_list_options.Filter.Name, "name", "n", "", "Filter by Host Name")
@@ -59,19 +58,18 @@ func init() {
 	cmd_list_host.Flags().Int16VarP((*int16)(unsafe.Pointer(&virtx.host_list_options.Filter.Cstate)), "state", "s", 0, "Filter by Cluster State")
 	cmd_list_host.Flags().Int32VarP(&virtx.host_list_options.Filter.Memoryavailable, "memory", "m", 0, "Filter by available normal memory")
 	cmd_list_host.Flags().Int32VarP(&virtx.host_list_options.Filter.Hpavailable, "hp", "H", 0, "Filter by available HugePages memory")
+	cmd_list_host.Flags().StringVarP(&virtx.host_list_options.Sort, "sort", "o", "", "Sort by name, ts, memoryavailable or uuid. Prefix with - for descending order")
+	cmd_list_host.Flags().Int16VarP(&virtx.host_list_options.Page.Size, "page-size", "z", 0, "Number of hosts per page (0 = all)")
+	cmd_list_host.Flags().Int16VarP(&virtx.host_list_options.Page.Index, "page", "p", 0, "Page index, starting from 0")
+	cmd_list_host.Flags().StringVarP(&virtx.host_list_options.Continue, "continue", "c", "", "Continuation token to get the next page")
+	cmd_list_host.Flags().StringSliceVarP(&virtx.host_list_options.Select, "select", "S", nil, "Comma separated list of fields to get (f.e. name,memoryavailable), the others are shown empty")
 
 	var cmd_list_vm = &cobra.Command{
 		Use:   "vm",
 		Short: "List VMs in the cluster",
 		Long:  "List all VMs in the cluster, or optionally applying filters (AND)",
 		Run: func(cmd *cobra.Command, args []string) {
-			if (virtx.ok) {
-				if (virtx.result != nil) {
-					vm_list(virtx.result.(*openapi.VmList))
-				}
-			} else {
-				vm_list_req()
-			}
+			vm_list_req()
 		},
 	}
 	cmd_list_vm.Flags().StringVarP(&virtx.vm_list_options.Filter.Name, "name", "n", "", "Filter by VM Name")
@@ -80,6 +78,44 @@ func init() {
 	cmd_list_vm.Flags().Int16VarP(&virtx.vm_list_options.Filter.Vlanid,	"vlanid", "v", 0, "Filter by VM Vlanid")
 	cmd_list_vm.Flags().StringVarP(&virtx.vm_list_options.Filter.Custom.Name, "custom-name", "N", "", "Filter by VM Custom Field Name")
 	cmd_list_vm.Flags().StringVarP(&virtx.vm_list_options.Filter.Custom.Value, "custom-value", "V", "", "Filter by VM Custom Field Value")
+	cmd_list_vm.Flags().StringVarP(&virtx.vm_list_options.Filter.Address, "address", "a", "", "Filter by IP address or CIDR prefix reported by the guest agent")
+	cmd_list_vm.Flags().StringVarP(&virtx.vm_list_options.Sort, "sort", "o", "", "Sort by name, host, runstate, ts or uuid. Prefix with - for descending order")
+	cmd_list_vm.Flags().Int16VarP(&virtx.vm_list_options.Page.Size, "page-size", "z", 0, "Number of VMs per page (0 = all)")
+	cmd_list_vm.Flags().Int16VarP(&virtx.vm_list_options.Page.Index, "page", "p", 0, "Page index, starting from 0")
+	cmd_list_vm.Flags().StringVarP(&virtx.vm_list_options.Continue, "continue", "c", "", "Continuation token to get the next page")
+	cmd_list_vm.Flags().StringSliceVarP(&virtx.vm_list_options.Select, "select", "S", nil, "Comma separated list of fields to get (f.e. name,runstate), the others are shown empty")
+	var cmd_list_task = &cobra.Command{
+		Use:   "task",
+		Short: "List tasks in the cluster",
+		Long:  "List all recent tasks in the cluster, or only the tasks of a VM",
+		Run: func(cmd *cobra.Command, args []string) {
+			vm, _ := cmd.Flags().GetString("vm")
+			task_list_req(vm)
+		},
+	}
+	cmd_list_task.Flags().StringP("vm", "v", "", "List only the tasks of the VM with this UUID")
+	var cmd_list_snapshot = &cobra.Command{
+		Use:   "snapshot",
+		Short: "List the snapshots of the resource",
+	}
+	var cmd_list_snapshot_vm = &cobra.Command{
+		Use:   "vm UUID",
+		Short: "List the VM snapshots",
+		Long:  "List the disk snapshots of the VM identified by UUID, from the oldest to the latest",
+		Args:  cobra.ExactArgs(1), /* UUID */
+		Run: func(cmd *cobra.Command, args []string) {
+			vm_snapshot_list_req(args[0])
+		},
+	}
+	var cmd_list_template = &cobra.Command{
+		Use:   "template",
+		Short: "List the template images",
+		Long:  "List the read-only template images which linked disks can use as base",
+		Args:  cobra.ExactArgs(0),
+		Run: func(cmd *cobra.Command, args []string) {
+			template_list_req()
+		},
+	}
 	var cmd_get = &cobra.Command{
 		Use:   "get",
 		Short: "Fetch and display all details about a resource",
@@ -90,13 +126,7 @@ func init() {
 		Long:  "Show all details about the specified host, identified by UUID",
 		Args:  cobra.ExactArgs(1), /* UUID */
 		Run: func(cmd *cobra.Command, args []string) {
-			if (virtx.ok) {
-				if (virtx.result != nil) {
-					host_get(virtx.result.(*openapi.Host))
-				}
-			} else {
-				host_get_req(args[0])
-			}
+			host_get_req(args[0])
 		},
 	}
 	cmd_get_host.Flags().BoolVarP(&virtx.stat_cpu, "stat-cpu", "C", false, "Show cpu statistics")
@@ -107,13 +137,7 @@ func init() {
 		Long:  "Fetch and show all details about the specified VM, identified by UUID",
 		Args:  cobra.ExactArgs(1), /* UUID */
 		Run: func(cmd *cobra.Command, args []string) {
-			if (virtx.ok) {
-				if (virtx.result != nil) {
-					vm_get(virtx.result.(*openapi.Vm))
-				}
-			} else {
-				vm_get_req(args[0])
-			}
+			vm_get_req(args[0])
 		},
 	}
 	cmd_get_vm.Flags().BoolVarP(&virtx.disk, "disk", "k", false, "Show VM disks")
@@ -122,6 +146,8 @@ func init() {
 	cmd_get_vm.Flags().BoolVarP(&virtx.stat_net, "stat-net", "N", false, "Show network statistics")
 	cmd_get_vm.Flags().BoolVarP(&virtx.stat_cpu, "stat-cpu", "C", false, "Show cpu statistics")
 	cmd_get_vm.Flags().BoolVarP(&virtx.stat_mem, "stat-mem", "M", false, "Show memory statistics")
+	cmd_get_vm.Flags().BoolVarP(&virtx.etag, "etag", "e", false, "Show the ETag of the VM definition, for --if-match")
+	cmd_get_vm.Flags().BoolVarP(&virtx.guest, "guest", "g", false, "Show the hostname, OS and addresses reported by the guest agent")
 
 	var cmd_get_runstate = &cobra.Command{
 		Use:   "runstate",
@@ -133,13 +159,7 @@ func init() {
 		Long:  "Show the runstate of the specified VM, identified by UUID",
 		Args:  cobra.ExactArgs(1), /* UUID */
 		Run: func(cmd *cobra.Command, args []string) {
-			if (virtx.ok) {
-				if (virtx.result != nil) {
-					vm_runstate_get(virtx.result.(*openapi.Vmruninfo))
-				}
-			} else {
-				vm_runstate_get_req(args[0])
-			}
+			vm_runstate_get_req(args[0])
 		},
 	}
 	var cmd_get_migrate = &cobra.Command{
@@ -152,13 +172,31 @@ func init() {
 		Long:  "Show the migration status of the specified VM, identified by UUID",
 		Args:  cobra.ExactArgs(1), /* UUID */
 		Run: func(cmd *cobra.Command, args []string) {
-			if (virtx.ok) {
-				if (virtx.result != nil) {
-					vm_migrate_get(virtx.result.(*openapi.MigrationInfo))
-				}
-			} else {
-				vm_migrate_get_req(args[0])
-			}
+			vm_migrate_get_req(args[0])
+		},
+	}
+	var cmd_get_screenshot = &cobra.Command{
+		Use:   "screenshot",
+		Short: "Save a screenshot of the resource",
+	}
+	var cmd_get_screenshot_vm = &cobra.Command{
+		Use:   "vm UUID -o FILE",
+		Short: "Save a screenshot of the VM",
+		Long:  "Save a PNG image of the primary display of the specified running VM, identified by UUID",
+		Args:  cobra.ExactArgs(1), /* UUID */
+		Run: func(cmd *cobra.Command, args []string) {
+			vm_screenshot_get_req(args[0])
+		},
+	}
+	cmd_get_screenshot_vm.Flags().StringVarP(&virtx.output, "output", "o", "", "PNG file to write, - for stdout")
+	cmd_get_screenshot_vm.MarkFlagRequired("output")
+	var cmd_get_task = &cobra.Command{
+		Use:   "task UUID",
+		Short: "Show the status of a task",
+		Long:  "Show the status and progress of the task identified by UUID",
+		Args:  cobra.ExactArgs(1), /* UUID */
+		Run: func(cmd *cobra.Command, args []string) {
+			task_get_req(args[0])
 		},
 	}
 	var cmd_create = &cobra.Command{
@@ -171,16 +209,51 @@ func init() {
 		Long:  "Create a new VM from a JSON description in FILENAME",
 		Args:  cobra.ExactArgs(1), /* FILENAME */
 		Run: func(cmd *cobra.Command, args []string) {
-			if (virtx.ok) {
-				if (virtx.result != nil) {
-					vm_create(virtx.result.(*string))
-				}
-			} else {
-				vm_create_req(args[0])
-			}
+			vm_create_req(args[0])
 		},
 	}
 	cmd_create_vm.Flags().StringVarP(&virtx.vm_create_options.Host, "host", "h", "", "Create VM on the specified host")
+	var cmd_create_snapshot = &cobra.Command{
+		Use:   "snapshot",
+		Short: "Create a snapshot of the resource",
+	}
+	var cmd_create_snapshot_vm = &cobra.Command{
+		Use:   "vm UUID NAME",
+		Short: "Create a VM snapshot",
+		Long:  "Create an external snapshot NAME of the managed disks of the VM identified by UUID, also while running",
+		Args:  cobra.ExactArgs(2), /* UUID NAME */
+		Run: func(cmd *cobra.Command, args []string) {
+			vm_snapshot_create_req(args[0], args[1])
+		},
+	}
+	cmd_create_snapshot_vm.Flags().StringVarP(&virtx.snapshot_create_options.Description, "description", "d", "", "Description of the snapshot")
+	var cmd_create_template = &cobra.Command{
+		Use:   "template",
+		Short: "Create a template from the resource",
+	}
+	var cmd_create_template_vm = &cobra.Command{
+		Use:   "vm UUID NAME",
+		Short: "Create a template from a VM",
+		Long:  "Create the template image NAME as a copy of the OS disk of the powered off VM identified by UUID",
+		Args:  cobra.ExactArgs(2), /* UUID NAME */
+		Run: func(cmd *cobra.Command, args []string) {
+			vm_template_create_req(args[0], args[1])
+		},
+	}
+	var cmd_clone = &cobra.Command{
+		Use:   "clone",
+		Short: "Create a new resource as a copy of an existing one",
+	}
+	var cmd_clone_vm = &cobra.Command{
+		Use:   "vm UUID NAME",
+		Short: "Clone a VM",
+		Long:  "Create a new VM named NAME as a copy of the powered off VM identified by UUID, copying its managed disks",
+		Args:  cobra.ExactArgs(2), /* UUID NAME */
+		Run: func(cmd *cobra.Command, args []string) {
+			vm_clone_req(args[0], args[1])
+		},
+	}
+	cmd_clone_vm.Flags().StringVarP(&virtx.vm_clone_options.Host, "host", "h", "", "Define the new VM on the specified host")
 	var cmd_update = &cobra.Command{
 		Use:   "update",
 		Short: "Update a resource",
@@ -191,14 +264,105 @@ func init() {
 		Long:  "Update a VM identified by UUID by redefining it from FILENAME. Changes its UUID.",
 		Args:  cobra.ExactArgs(2), /* UUID and FILENAME */
 		Run: func(cmd *cobra.Command, args []string) {
-			if (virtx.ok) {
-				vm_update()
-			} else {
-				vm_update_req(args[0], args[1])
-			}
+			vm_update_req(args[0], args[1])
 		},
 	}
 	cmd_update_vm.Flags().BoolVarP(&virtx.vm_update_options.Deletestorage, "storage", "s", false, "Delete unused storage (NOT IMPLEMENTED)")
+	cmd_update_vm.Flags().StringVarP(&virtx.if_match, "if-match", "m", "", "Only update if the VM definition still matches this ETag")
+
+	var cmd_patch = &cobra.Command{
+		Use:   "patch",
+		Short: "Change part of a resource",
+	}
+	var cmd_patch_vm = &cobra.Command{
+		Use:   "vm UUID FILENAME",
+		Short: "Change part of a VM",
+		Long:  "Change part of the definition of a VM identified by UUID with the JSON merge patch (RFC 7396) in FILENAME, f.e. {\"memory\":{\"total\":4096}}",
+		Args:  cobra.ExactArgs(2), /* UUID and FILENAME */
+		Run: func(cmd *cobra.Command, args []string) {
+			vm_patch_req(args[0], args[1])
+		},
+	}
+	cmd_patch_vm.Flags().BoolVarP(&virtx.json_patch, "json-patch", "j", false, "FILENAME contains a JSON patch (RFC 6902), f.e. to add or remove disks and nets")
+	cmd_patch_vm.Flags().StringVarP(&virtx.if_match, "if-match", "m", "", "Only patch if the VM definition still matches this ETag")
+
+	var cmd_attach = &cobra.Command{
+		Use:   "attach",
+		Short: "Attach a device to a resource",
+	}
+	var cmd_attach_disk = &cobra.Command{
+		Use:   "disk",
+		Short: "Attach a disk",
+	}
+	var cmd_attach_disk_vm = &cobra.Command{
+		Use:   "vm UUID FILENAME",
+		Short: "Attach a disk to a VM",
+		Long:  "Attach the disk described in JSON in FILENAME to the VM identified by UUID, also while running (virtio buses only)",
+		Args:  cobra.ExactArgs(2), /* UUID and FILENAME */
+		Run: func(cmd *cobra.Command, args []string) {
+			vm_disk_attach_req(args[0], args[1])
+		},
+	}
+	var cmd_attach_net = &cobra.Command{
+		Use:   "net",
+		Short: "Attach a net",
+	}
+	var cmd_attach_net_vm = &cobra.Command{
+		Use:   "vm UUID FILENAME",
+		Short: "Attach a net to a VM",
+		Long:  "Attach the net described in JSON in FILENAME to the VM identified by UUID, also while running. The MAC address of the new net is the message of the task",
+		Args:  cobra.ExactArgs(2), /* UUID and FILENAME */
+		Run: func(cmd *cobra.Command, args []string) {
+			vm_net_attach_req(args[0], args[1])
+		},
+	}
+	var cmd_detach = &cobra.Command{
+		Use:   "detach",
+		Short: "Detach a device from a resource",
+	}
+	var cmd_detach_disk = &cobra.Command{
+		Use:   "disk",
+		Short: "Detach a disk",
+	}
+	var cmd_detach_disk_vm = &cobra.Command{
+		Use:   "vm UUID PATH",
+		Short: "Detach a disk from a VM",
+		Long:  "Detach the disk PATH from the VM identified by UUID, also while running",
+		Args:  cobra.ExactArgs(2), /* UUID and PATH */
+		Run: func(cmd *cobra.Command, args []string) {
+			vm_disk_detach_req(args[0], args[1])
+		},
+	}
+	var cmd_detach_net = &cobra.Command{
+		Use:   "net",
+		Short: "Detach a net",
+	}
+	var cmd_detach_net_vm = &cobra.Command{
+		Use:   "vm UUID MAC",
+		Short: "Detach a net from a VM",
+		Long:  "Detach the net with address MAC from the VM identified by UUID, also while running",
+		Args:  cobra.ExactArgs(2), /* UUID and MAC */
+		Run: func(cmd *cobra.Command, args []string) {
+			vm_net_detach_req(args[0], args[1])
+		},
+	}
+	cmd_detach_disk_vm.Flags().BoolVarP(&virtx.disk_detach_options.Deletestorage, "storage", "s", false, "also delete managed storage")
+
+	var cmd_resize = &cobra.Command{
+		Use:   "resize",
+		Short: "Add resources to a resource",
+	}
+	var cmd_resize_vm = &cobra.Command{
+		Use:   "vm UUID",
+		Short: "Hot-add vCPUs and memory to a VM",
+		Long:  "Set the number of sockets and the total memory of the VM identified by UUID, also while running, up to cpudef.maxsockets and memory.max",
+		Args:  cobra.ExactArgs(1), /* UUID */
+		Run: func(cmd *cobra.Command, args []string) {
+			vm_resize_req(args[0])
+		},
+	}
+	cmd_resize_vm.Flags().Int16VarP(&virtx.vm_resize_options.Sockets, "sockets", "s", 0, "The new number of sockets (0 = unchanged)")
+	cmd_resize_vm.Flags().Int32VarP(&virtx.vm_resize_options.Memory, "memory", "m", 0, "The new total memory in MiB (0 = unchanged)")
 
 	var cmd_delete = &cobra.Command{
 		Use:   "delete",
@@ -210,14 +374,41 @@ func init() {
 		Long:  "Delete a VM identified by UUID permanently (use with care)",
 		Args:  cobra.ExactArgs(1), /* UUID */
 		Run: func(cmd *cobra.Command, args []string) {
-			if (virtx.ok) {
-				vm_delete()
-			} else {
-				vm_delete_req(args[0])
-			}
+			vm_delete_req(args[0])
 		},
 	}
 	cmd_delete_vm.Flags().BoolVarP(&virtx.vm_delete_options.Deletestorage, "storage", "s", false, "also delete managed storage")
+	cmd_delete_vm.Flags().StringVarP(&virtx.if_match, "if-match", "m", "", "Only delete if the VM definition still matches this ETag")
+	var cmd_delete_snapshot = &cobra.Command{
+		Use:   "snapshot",
+		Short: "Delete a snapshot of the resource",
+	}
+	var cmd_delete_snapshot_vm = &cobra.Command{
+		Use:   "vm UUID NAME",
+		Short: "Delete a VM snapshot",
+		Long:  "Delete the snapshot NAME of the VM identified by UUID, committing it into its parent. The current disk state is kept",
+		Args:  cobra.ExactArgs(2), /* UUID NAME */
+		Run: func(cmd *cobra.Command, args []string) {
+			vm_snapshot_delete_req(args[0], args[1])
+		},
+	}
+	var cmd_revert = &cobra.Command{
+		Use:   "revert",
+		Short: "Revert a resource to a previous state",
+	}
+	var cmd_revert_snapshot = &cobra.Command{
+		Use:   "snapshot",
+		Short: "Revert the resource to a snapshot",
+	}
+	var cmd_revert_snapshot_vm = &cobra.Command{
+		Use:   "vm UUID NAME",
+		Short: "Revert a VM to a snapshot",
+		Long:  "Revert the powered off VM identified by UUID to the snapshot NAME, discarding the current disk state and the later snapshots",
+		Args:  cobra.ExactArgs(2), /* UUID NAME */
+		Run: func(cmd *cobra.Command, args []string) {
+			vm_snapshot_revert_req(args[0], args[1])
+		},
+	}
 	var cmd_boot = &cobra.Command{
 		Use:   "boot",
 		Short: "Startup a runnable resource",
@@ -228,14 +419,10 @@ func init() {
 		Long:  "Start a VM identified by UUID",
 		Args:  cobra.ExactArgs(1), /* UUID */
 		Run: func(cmd *cobra.Command, args []string) {
-			if (virtx.ok) {
-				vm_boot()
-			} else {
-				ud, _ := cmd.Flags().GetString("ci-userdata")
-				md, _ := cmd.Flags().GetString("ci-metadata")
-				nc, _ := cmd.Flags().GetString("ci-networkconfig")
-				vm_boot_req(args[0], ud, md, nc)
-			}
+			ud, _ := cmd.Flags().GetString("ci-userdata")
+			md, _ := cmd.Flags().GetString("ci-metadata")
+			nc, _ := cmd.Flags().GetString("ci-networkconfig")
+			vm_boot_req(args[0], ud, md, nc)
 		},
 	}
 	cmd_boot_vm.Flags().StringP("ci-userdata", "u", "",
@@ -254,14 +441,55 @@ func init() {
 		Long:  "Shutdown a VM guest gracefully with ACPI, or Poweroff with force",
 		Args:  cobra.ExactArgs(1), /* UUID */
 		Run: func(cmd *cobra.Command, args []string) {
-			if (virtx.ok) {
-				vm_shutdown()
-			} else {
-				vm_shutdown_req(args[0])
-			}
+			vm_shutdown_req(args[0])
 		},
 	}
 	cmd_shutdown_vm.Flags().CountVarP(&virtx.force, "force", "f", "send the VM process a SIGTERM, or if repeated a SIGKILL")
+	cmd_shutdown_vm.Flags().Int32VarP(&virtx.vm_shutdown_options.Timeout, "timeout", "t", 0, "seconds to wait for the guest before escalating to SIGTERM and then SIGKILL")
+	cmd_shutdown_vm.Flags().BoolVarP(&virtx.vm_shutdown_options.Agent, "agent", "a", false, "ask the guest agent to shutdown instead of using ACPI")
+	var cmd_reboot = &cobra.Command{
+		Use:   "reboot",
+		Short: "Reboot / Reset a runnable resource",
+	}
+	var cmd_reboot_vm = &cobra.Command{
+		Use:   "vm UUID",
+		Short: "Reboot or Reset a VM",
+		Long:  "Reboot a VM guest gracefully with ACPI, or Reset it with force",
+		Args:  cobra.ExactArgs(1), /* UUID */
+		Run: func(cmd *cobra.Command, args []string) {
+			vm_reboot_req(args[0])
+		},
+	}
+	cmd_reboot_vm.Flags().BoolVarP(&virtx.vm_reboot_options.Force, "force", "f", false, "reset the VM without waiting for the guest")
+	cmd_reboot_vm.Flags().BoolVarP(&virtx.vm_reboot_options.Agent, "agent", "a", false, "ask the guest agent to reboot instead of using ACPI")
+	var cmd_console = &cobra.Command{
+		Use:   "console",
+		Short: "Connect to the console of a resource",
+	}
+	var cmd_console_vm = &cobra.Command{
+		Use:   "vm UUID",
+		Short: "Connect to the serial console of a VM",
+		Long:  "Connect the terminal in raw mode to the serial console of a running VM. Type Ctrl-] to exit",
+		Args:  cobra.ExactArgs(1), /* UUID */
+		Run: func(cmd *cobra.Command, args []string) {
+			vm_console_req(args[0])
+		},
+	}
+	cmd_console_vm.Flags().BoolVarP(&virtx.console_force, "force", "f", false, "disconnect any other console session")
+	var cmd_vnc = &cobra.Command{
+		Use:   "vnc",
+		Short: "Connect to the graphics of a resource",
+	}
+	var cmd_vnc_vm = &cobra.Command{
+		Use:   "vm UUID",
+		Short: "Forward a local port to the VNC server of a VM",
+		Long:  "Listen on a local address and forward the VNC viewers connecting to it to the VNC server of a running VM",
+		Args:  cobra.ExactArgs(1), /* UUID */
+		Run: func(cmd *cobra.Command, args []string) {
+			vm_vnc_req(args[0])
+		},
+	}
+	cmd_vnc_vm.Flags().StringVarP(&virtx.vnc_listen, "listen", "l", "localhost:5900", "local address to listen on")
 	var cmd_pause = &cobra.Command{
 		Use:   "pause",
 		Short: "Pause a runnable resource",
@@ -272,11 +500,7 @@ func init() {
 		Long:  "Pause a VM identified by UUID",
 		Args:  cobra.ExactArgs(1), /* UUID */
 		Run: func(cmd *cobra.Command, args []string) {
-			if (virtx.ok) {
-				vm_pause()
-			} else {
-				vm_pause_req(args[0])
-			}
+			vm_pause_req(args[0])
 		},
 	}
 	var cmd_resume = &cobra.Command{
@@ -289,11 +513,7 @@ func init() {
 		Long:  "Resume a VM identified by UUID in paused state",
 		Args:  cobra.ExactArgs(1), /* UUID */
 		Run: func(cmd *cobra.Command, args []string) {
-			if (virtx.ok) {
-				vm_resume()
-			} else {
-				vm_resume_req(args[0])
-			}
+			vm_resume_req(args[0])
 		},
 	}
 	var cmd_migrate = &cobra.Command{
@@ -306,11 +526,7 @@ func init() {
 		Long:  "Migrate a VM identified by UUID",
 		Args:  cobra.MinimumNArgs(1), /* UUID and optionally HUUID */
 		Run: func(cmd *cobra.Command, args []string) {
-			if (virtx.ok) {
-				vm_migrate()
-			} else {
-				vm_migrate_req(args[0])
-			}
+			vm_migrate_req(args[0])
 		},
 	}
 	cmd_migrate_vm.Flags().BoolVarP(&virtx.live, "live", "l", false, "if true, perform live migration")
@@ -329,11 +545,7 @@ func init() {
 		Long:  "Abort the live migration of the VM identified by UUID",
 		Args:  cobra.ExactArgs(1), /* UUID */
 		Run: func(cmd *cobra.Command, args []string) {
-			if (virtx.ok) {
-				vm_migrate_abort()
-			} else {
-				vm_migrate_abort_req(args[0])
-			}
+			vm_migrate_abort_req(args[0])
 		},
 	}
 	var cmd_register = &cobra.Command{
@@ -346,11 +558,7 @@ func init() {
 		Long:  "Register a VM identified by host and VM UUIDs if it desynced between virtx and libvirt",
 		Args:  cobra.ExactArgs(1), /* UUID */
 		Run: func(cmd *cobra.Command, args []string) {
-			if (virtx.ok) {
-				vm_register()
-			} else {
-				vm_register_req(args[0])
-			}
+			vm_register_req(args[0])
 		},
 	}
 	cmd_register_vm.Flags().StringVarP(&virtx.vm_register_options.Host, "host", "h", "", "Register VM on the specified host")
@@ -366,6 +574,10 @@ func init() {
 	cmd.AddCommand(cmd_list)
 	cmd_list.AddCommand(cmd_list_host)
 	cmd_list.AddCommand(cmd_list_vm)
+	cmd_list.AddCommand(cmd_list_task)
+	cmd_list.AddCommand(cmd_list_snapshot)
+	cmd_list_snapshot.AddCommand(cmd_list_snapshot_vm)
+	cmd_list.AddCommand(cmd_list_template)
 	cmd.AddCommand(cmd_get)
 	cmd_get.AddCommand(cmd_get_host)
 	cmd_get.AddCommand(cmd_get_vm)
@@ -373,16 +585,50 @@ func init() {
 	cmd_get_runstate.AddCommand(cmd_get_runstate_vm)
 	cmd_get.AddCommand(cmd_get_migrate)
 	cmd_get_migrate.AddCommand(cmd_get_migrate_vm)
+	cmd_get.AddCommand(cmd_get_screenshot)
+	cmd_get_screenshot.AddCommand(cmd_get_screenshot_vm)
+	cmd_get.AddCommand(cmd_get_task)
 	cmd.AddCommand(cmd_create)
 	cmd_create.AddCommand(cmd_create_vm)
+	cmd_create.AddCommand(cmd_create_snapshot)
+	cmd_create_snapshot.AddCommand(cmd_create_snapshot_vm)
+	cmd_create.AddCommand(cmd_create_template)
+	cmd_create_template.AddCommand(cmd_create_template_vm)
+	cmd.AddCommand(cmd_clone)
+	cmd_clone.AddCommand(cmd_clone_vm)
 	cmd.AddCommand(cmd_update)
 	cmd_update.AddCommand(cmd_update_vm)
+	cmd.AddCommand(cmd_patch)
+	cmd_patch.AddCommand(cmd_patch_vm)
+	cmd.AddCommand(cmd_attach)
+	cmd_attach.AddCommand(cmd_attach_disk)
+	cmd_attach_disk.AddCommand(cmd_attach_disk_vm)
+	cmd_attach.AddCommand(cmd_attach_net)
+	cmd_attach_net.AddCommand(cmd_attach_net_vm)
+	cmd.AddCommand(cmd_detach)
+	cmd_detach.AddCommand(cmd_detach_disk)
+	cmd_detach_disk.AddCommand(cmd_detach_disk_vm)
+	cmd_detach.AddCommand(cmd_detach_net)
+	cmd_detach_net.AddCommand(cmd_detach_net_vm)
+	cmd.AddCommand(cmd_resize)
+	cmd_resize.AddCommand(cmd_resize_vm)
 	cmd.AddCommand(cmd_delete)
 	cmd_delete.AddCommand(cmd_delete_vm)
+	cmd_delete.AddCommand(cmd_delete_snapshot)
+	cmd_delete_snapshot.AddCommand(cmd_delete_snapshot_vm)
+	cmd.AddCommand(cmd_revert)
+	cmd_revert.AddCommand(cmd_revert_snapshot)
+	cmd_revert_snapshot.AddCommand(cmd_revert_snapshot_vm)
 	cmd.AddCommand(cmd_boot)
 	cmd_boot.AddCommand(cmd_boot_vm)
 	cmd.AddCommand(cmd_shutdown)
 	cmd_shutdown.AddCommand(cmd_shutdown_vm)
+	cmd.AddCommand(cmd_reboot)
+	cmd_reboot.AddCommand(cmd_reboot_vm)
+	cmd.AddCommand(cmd_console)
+	cmd_console.AddCommand(cmd_console_vm)
+	cmd.AddCommand(cmd_vnc)
+	cmd_vnc.AddCommand(cmd_vnc_vm)
 	cmd.AddCommand(cmd_pause)
 	cmd_pause.AddCommand(cmd_pause_vm)
 	cmd.AddCommand(cmd_resume)
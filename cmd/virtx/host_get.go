@@ -6,10 +6,9 @@ import (
 )
 
 func host_get_req(arg string) {
-	virtx.path = fmt.Sprintf("/hosts/%s", arg)
-	virtx.method = "GET"
-	virtx.arg = nil
-	virtx.result = &openapi.Host{}
+	host, err := virtx.c.GetHost(virtx.ctx, arg)
+	cmd_check(err)
+	host_get(host)
 }
 
 func host_get(host *openapi.Host) {
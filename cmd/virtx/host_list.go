@@ -7,10 +7,9 @@ import (
 )
 
 func host_list_req() {
-	virtx.path = "/hosts"
-	virtx.method = "GET"
-	virtx.arg = &virtx.host_list_options
-	virtx.result = &openapi.HostList{}
+	list, err := virtx.c.ListHosts(virtx.ctx, &virtx.host_list_options)
+	cmd_check(err)
+	host_list(list)
 }
 
 func host_list(list *openapi.HostList) {
@@ -25,4 +24,7 @@ func host_list(list *openapi.HostList) {
 			item.Fields.Memoryavailable, item.Fields.Hpavailable,
 			item.Fields.Cstate, ts.Since(item.Fields.Ts))
 	}
+	if (list.Continue != "") {
+		fmt.Fprintf(virtx.w, "\ncontinue: %s\n", list.Continue)
+	}
 }
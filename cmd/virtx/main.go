@@ -19,31 +19,23 @@ package main
 
 import (
 	"os"
-	"time"
-	"net/http"
+	"os/signal"
 	"fmt"
+	"errors"
+	"strings"
+	"context"
 	"encoding/json"
-	"bytes"
 	writer "text/tabwriter"
 
 	"suse.com/virtx/pkg/model"
 	"suse.com/virtx/pkg/logger"
-	"suse.com/virtx/pkg/httpx"
+	"suse.com/virtx/pkg/client"
 )
 
-const (
-	CLIENT_TIMEOUT = 10
-	CLIENT_IDLE_CONN_MAX = 100
-	CLIENT_IDLE_CONN_MAX_PER_HOST = 10
-	CLIENT_IDLE_TIMEOUT = 15
-	CLIENT_TLS_TIMEOUT = 5
-)
 type VirtxClient struct {
-	api_server string           // the API server (default VIRTX_API_SERVER env)
-	path string                 // relative path of the REST request we will do
-	method string               // the REST method
-	ok bool                     // used by cmd to know whether to prepare the request or process the response
-	client http.Client          // the HTTP client
+	api_server string           // the API servers, comma separated (default VIRTX_API_SERVER env)
+	c *client.Client            // the API client
+	ctx context.Context         // canceled on interrupt
 	force int                   // how much force to apply
 	disk bool                   // show VM disks
 	net bool                    // show VM nets
@@ -53,6 +45,17 @@ type VirtxClient struct {
 	stat_mem bool               // show host/VM stats on mem
 	debug bool                  // verbose client output
 	live bool                   // live migration
+	etag bool                   // show the ETag of the VM definition
+	guest bool                  // show the guest agent information
+	if_match string             // ETag the VM definition must match to be changed
+	json_patch bool             // patch is a JSON patch instead of a JSON merge patch
+	console_force bool          // disconnect other console sessions
+	vnc_listen string           // local address for VNC viewers
+	output string               // output file, "-" for stdout
+	token string                // bearer token (default VIRTX_TOKEN env)
+	tls_ca string               // CA to verify the API server (default VIRTX_TLS_CA env)
+	tls_cert string             // client certificate (default VIRTX_TLS_CERT env)
+	tls_key string              // client private key (default VIRTX_TLS_KEY env)
 
 	/* args */
 	host_list_options openapi.HostListOptions
@@ -60,88 +63,95 @@ type VirtxClient struct {
 	vm_create_options openapi.VmCreateOptions
 	vm_update_options openapi.VmUpdateOptions
 	vm_shutdown_options openapi.VmShutdownOptions
+	vm_reboot_options openapi.VmRebootOptions
 	vm_delete_options openapi.VmDeleteOptions
 	vm_migrate_options openapi.VmMigrateOptions
 	vm_register_options openapi.VmRegisterOptions
+	vm_clone_options openapi.VmCloneOptions
 	vm_boot_options openapi.VmBootOptions
-
-	arg any                     // the argument if needed, the struct to be encoded into body of request
-	result any                  // pointer to struct to be decoded from body of the response
+	snapshot_create_options openapi.SnapshotCreateOptions
+	disk_detach_options openapi.DiskDetachOptions
+	vm_resize_options openapi.VmResizeOptions
 
 	w *writer.Writer
 }
-var virtx VirtxClient = VirtxClient{
-	client: http.Client{
-		Timeout: CLIENT_TIMEOUT * time.Second,
-		Transport: &http.Transport{
-			MaxIdleConns: CLIENT_IDLE_CONN_MAX,
-			MaxIdleConnsPerHost: CLIENT_IDLE_CONN_MAX_PER_HOST,
-			IdleConnTimeout: CLIENT_IDLE_TIMEOUT * time.Second,
-			TLSHandshakeTimeout: CLIENT_TLS_TIMEOUT * time.Second,
-		},
-	},
-}
+var virtx VirtxClient
 
 var version string = "unknown"
 
 func main() {
 	var (
 		err error
-		response *http.Response
+		stop context.CancelFunc
 	)
+	virtx.ctx, stop = signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
 	err = cmd_exec()
+	if (virtx.w != nil) {
+		virtx.w.Flush()
+	}
+	if (virtx.c != nil) {
+		virtx.c.Close()
+	}
 	if (err != nil) {
 		logger.Log("failed to parse command: %s\n", err.Error())
 		os.Exit(1)
 	}
+}
+
+/* called before running any command, after the flags have been parsed */
+func cmd_init() error {
+	var (
+		err error
+	)
 	if (virtx.debug) {
 		logger.Set_debug(true)
 	}
 	logger.Debug("version %s", version)
 
-	if (virtx.path == "") {
-		/* nothing else to do. */
-		os.Exit(0)
-	}
-	if (virtx.debug) {
-		logger.Log("api_server=%s, method=%s, path=%s, arg=%v", virtx.api_server, virtx.method, virtx.path, virtx.arg)
-		if (virtx.arg != nil) {
-			var buf bytes.Buffer
-			err = json.NewEncoder(&buf).Encode(virtx.arg)
-			if (err == nil) {
-				logger.Log("JSON\n%s", buf.String())
-			}
-		}
-	}
-	response, err = httpx.Do_request(virtx.api_server, virtx.method, virtx.path, virtx.arg)
+	virtx.c, err = client.New(client.Options{
+		Servers: strings.Split(virtx.api_server, ","),
+		Token: virtx.token,
+		Tls_ca: virtx.tls_ca,
+		Tls_cert: virtx.tls_cert,
+		Tls_key: virtx.tls_key,
+		Debug: virtx.debug,
+	})
 	if (err != nil) {
-		logger.Log("failed to send request: %s", err.Error())
-		os.Exit(1)
+		return err
 	}
-	if (response.Body != nil && virtx.result != nil) {
-		_, err = httpx.Decode_response_body(response, virtx.result)
-		if (err != nil) {
-			logger.Log("failed to decode response: %s", err.Error())
-			os.Exit(1)
-		}
-		if (virtx.debug) {
-			logger.Log("result=%v", virtx.result)
-			if (virtx.result != nil) {
-				var buf bytes.Buffer
-				err = json.NewEncoder(&buf).Encode(virtx.result)
-				if (err == nil) {
-					logger.Log("JSON\n%s", buf.String())
-				}
-			}
-		}
+	virtx.w = writer.NewWriter(os.Stdout, 0, 4, 1, ' ', writer.StripEscape | writer.Debug)
+	return nil
+}
+
+/* report a failed request and exit */
+func cmd_check(err error) {
+	var e *client.Error
+	if (err == nil) {
+		return
 	}
-	if (response.StatusCode >= 200 && response.StatusCode <= 299) {
-		virtx.ok = true
-		virtx.w = writer.NewWriter(os.Stdout, 0, 4, 1, ' ', writer.StripEscape | writer.Debug)
-		err = cmd_exec()
-		virtx.w.Flush()
+	if (errors.As(err, &e)) {
+		print_problem(fmt.Sprintf("%d %s", e.Status, e.Problem.Title), e.Problem)
 	} else {
-		fmt.Printf("%s\n", response.Status)
+		logger.Log("request failed: %s", err.Error())
+	}
+	os.Exit(1)
+}
+
+/* print the error details returned by the server */
+func print_problem(status string, p openapi.Problem) {
+	fmt.Printf("%s\n", status)
+	if (p.Code != "") {
+		fmt.Printf("code:   %s\n", p.Code)
+	}
+	if (p.Detail != "") {
+		fmt.Printf("detail: %s\n", p.Detail)
+	}
+	if (p.Field != "") {
+		fmt.Printf("field:  %s\n", p.Field)
+	}
+	if (p.Host != "") {
+		fmt.Printf("host:   %s\n", p.Host)
 	}
 }
 
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"fmt"
+	"suse.com/virtx/pkg/model"
+	"suse.com/virtx/pkg/ts"
+)
+
+func task_get_req(arg string) {
+	t, err := virtx.c.GetTask(virtx.ctx, arg)
+	cmd_check(err)
+	task_get(t)
+}
+
+func task_get(t *openapi.Task) {
+	fmt.Fprintf(virtx.w, "UUID\tOP\tVM\tHOST\tSTATUS\tPROGRESS\tSTARTED\tENDED\tMSG\n")
+	fmt.Fprintf(virtx.w, "%s\t%s\t%s\t%s\t%s\t%3d%%\t%s\t%s\t%s\n", t.Uuid, t.Op, t.Vm, t.Host,
+		t.Status, t.Progress, ts.String(t.Ts), ts.String(t.Te), t.Msg)
+}
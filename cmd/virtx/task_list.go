@@ -0,0 +1,31 @@
+package main
+
+import (
+	"fmt"
+	"suse.com/virtx/pkg/model"
+	"suse.com/virtx/pkg/ts"
+)
+
+func task_list_req(vm string) {
+	var (
+		list *openapi.TaskList
+		err error
+	)
+	if (vm != "") {
+		list, err = virtx.c.ListVmTasks(virtx.ctx, vm)
+	} else {
+		list, err = virtx.c.ListTasks(virtx.ctx)
+	}
+	cmd_check(err)
+	task_list(list)
+}
+
+func task_list(list *openapi.TaskList) {
+
+	fmt.Fprintf(virtx.w, "UUID\tOP\tVM\tHOST\tSTATUS\tPROGRESS\tAGE\n")
+
+	for _, item := range (list.Items) {
+		fmt.Fprintf(virtx.w, "%s\t%s\t%s\t%s\t%s\t%3d%%\t%s\n", item.Uuid, item.Op, item.Vm, item.Host,
+			item.Status, item.Progress, ts.Since(item.Ts))
+	}
+}
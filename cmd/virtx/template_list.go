@@ -0,0 +1,19 @@
+package main
+
+import (
+	"fmt"
+	"suse.com/virtx/pkg/model"
+)
+
+func template_list_req() {
+	list, err := virtx.c.ListTemplates(virtx.ctx)
+	cmd_check(err)
+	template_list(list)
+}
+
+func template_list(list *openapi.TemplateList) {
+	fmt.Fprintf(virtx.w, "NAME\tSIZE\tPATH\n")
+	for _, item := range (list.Items) {
+		fmt.Fprintf(virtx.w, "%s\t%d\t%s\n", item.Name, item.Size, item.Path)
+	}
+}
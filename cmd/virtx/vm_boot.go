@@ -19,7 +19,6 @@
 package main
 
 import (
-	"fmt"
 	"os"
 
 	"suse.com/virtx/pkg/logger"
@@ -27,17 +26,11 @@ import (
 )
 
 func vm_boot_req(arg string, ud_path string, md_path string, nc_path string) {
-	virtx.path = fmt.Sprintf("/vms/%s/runstate/boot", arg)
-	virtx.method = "POST"
-	virtx.arg = &virtx.vm_boot_options
-	virtx.result = nil
-
 	read_opt("ci-userdata", ud_path)
 	read_opt("ci-metadata", md_path)
 	read_opt("ci-networkconfig", nc_path)
-}
 
-func vm_boot() {
+	cmd_check(virtx.c.Boot(virtx.ctx, arg, &virtx.vm_boot_options))
 }
 
 func read_opt(name string, path string) {
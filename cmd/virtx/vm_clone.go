@@ -0,0 +1,8 @@
+package main
+
+func vm_clone_req(uuid string, name string) {
+	virtx.vm_clone_options.Name = name
+	t, err := virtx.c.CloneVm(virtx.ctx, uuid, &virtx.vm_clone_options)
+	cmd_check(err)
+	task_get(t)
+}
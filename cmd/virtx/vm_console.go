@@ -0,0 +1,89 @@
+package main
+
+import (
+	"os"
+	"io"
+	"bytes"
+	"fmt"
+
+	"golang.org/x/sys/unix"
+	"suse.com/virtx/pkg/logger"
+)
+
+const CONSOLE_ESCAPE = 0x1d /* Ctrl-] */
+
+/* put the terminal fd in raw mode, returning the previous settings to restore */
+func console_raw(fd int) (*unix.Termios, error) {
+	var (
+		err error
+		old *unix.Termios
+		t unix.Termios
+	)
+	old, err = unix.IoctlGetTermios(fd, unix.TCGETS)
+	if (err != nil) {
+		return nil, err
+	}
+	t = *old
+	/* as cfmakeraw(3) */
+	t.Iflag &^= unix.IGNBRK | unix.BRKINT | unix.PARMRK | unix.ISTRIP | unix.INLCR | unix.IGNCR | unix.ICRNL | unix.IXON
+	t.Oflag &^= unix.OPOST
+	t.Lflag &^= unix.ECHO | unix.ECHONL | unix.ICANON | unix.ISIG | unix.IEXTEN
+	t.Cflag &^= unix.CSIZE | unix.PARENB
+	t.Cflag |= unix.CS8
+	t.Cc[unix.VMIN] = 1
+	t.Cc[unix.VTIME] = 0
+	err = unix.IoctlSetTermios(fd, unix.TCSETS, &t)
+	if (err != nil) {
+		return nil, err
+	}
+	return old, nil
+}
+
+/* copy stdin to the console until the escape character is typed */
+func console_input(conn io.Writer) {
+	var (
+		err error
+		n int
+		buf [1024]byte
+	)
+	for {
+		n, err = os.Stdin.Read(buf[:])
+		if (n > 0) {
+			var i int = bytes.IndexByte(buf[:n], CONSOLE_ESCAPE)
+			if (i >= 0) {
+				conn.Write(buf[:i])
+				return
+			}
+			_, err = conn.Write(buf[:n])
+		}
+		if (err != nil) {
+			return
+		}
+	}
+}
+
+func vm_console_req(arg string) {
+	var (
+		err error
+		conn io.ReadWriteCloser
+		old *unix.Termios
+		fd int = int(os.Stdin.Fd())
+	)
+	conn, err = virtx.c.Console(virtx.ctx, arg, virtx.console_force)
+	cmd_check(err)
+	fmt.Fprintf(os.Stderr, "Connected to the console of %s. Escape character is ^]\n", arg)
+	old, err = console_raw(fd)
+	if (err != nil) {
+		logger.Debug("not a terminal: %s", err.Error())
+	}
+	go func() {
+		console_input(conn)
+		conn.Close()
+	}()
+	io.Copy(os.Stdout, conn)
+	conn.Close()
+	if (old != nil) {
+		unix.IoctlSetTermios(fd, unix.TCSETS, old)
+	}
+	fmt.Fprintf(os.Stderr, "\r\nDisconnected.\n")
+}
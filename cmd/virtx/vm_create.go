@@ -1,18 +1,16 @@
 package main
 
 import (
-	"fmt"
+	"suse.com/virtx/pkg/model"
 )
 
 func vm_create_req(arg string) {
 	read_json(arg, &virtx.vm_create_options.Vmdef)
-	virtx.path = "/vms"
-	virtx.method = "POST"
-	virtx.arg = &virtx.vm_create_options
-	virtx.result = new(string)
+	t, err := virtx.c.CreateVm(virtx.ctx, &virtx.vm_create_options)
+	cmd_check(err)
+	vm_create(t)
 }
 
-func vm_create(uuid *string) {
-	fmt.Fprintf(virtx.w, "UUID\n")
-	fmt.Fprintf(virtx.w, "%s\n", *uuid)
+func vm_create(t *openapi.Task) {
+	task_get(t)
 }
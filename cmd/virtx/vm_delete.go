@@ -1,15 +1,17 @@
 package main
 
 import (
-	"fmt"
+	"suse.com/virtx/pkg/model"
 )
 
 func vm_delete_req(arg string) {
-	virtx.path = fmt.Sprintf("/vms/%s", arg)
-	virtx.method = "DELETE"
-	virtx.arg = &virtx.vm_delete_options
-	virtx.result = nil
+	t, err := vm_etag_retry(arg, func(etag string) (*openapi.Task, error) {
+		return virtx.c.DeleteVm(virtx.ctx, arg, etag, &virtx.vm_delete_options)
+	})
+	cmd_check(err)
+	vm_delete(t)
 }
 
-func vm_delete() {
+func vm_delete(t *openapi.Task) {
+	task_get(t)
 }
@@ -0,0 +1,13 @@
+package main
+
+import (
+	"suse.com/virtx/pkg/model"
+)
+
+func vm_disk_attach_req(uuid string, arg string) {
+	var disk openapi.Disk
+	read_json(arg, &disk)
+	t, err := virtx.c.AttachDisk(virtx.ctx, uuid, &disk)
+	cmd_check(err)
+	task_get(t)
+}
@@ -0,0 +1,8 @@
+package main
+
+func vm_disk_detach_req(uuid string, path string) {
+	virtx.disk_detach_options.Path = path
+	t, err := virtx.c.DetachDisk(virtx.ctx, uuid, &virtx.disk_detach_options)
+	cmd_check(err)
+	task_get(t)
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"net/http"
+
+	"suse.com/virtx/pkg/model"
+	"suse.com/virtx/pkg/logger"
+	"suse.com/virtx/pkg/client"
+)
+
+const (
+	ETAG_RETRIES = 3 /* attempts to change a VM definition which keeps changing */
+)
+
+/*
+ * change the VM definition with fn, passing the ETag from --if-match.
+ * Without --if-match, read the current ETag, and if the definition changes before
+ * the request gets to the server (412), re-read it and retry.
+ */
+func vm_etag_retry(uuid string, fn func(etag string) (*openapi.Task, error)) (*openapi.Task, error) {
+	var (
+		t *openapi.Task
+		err error
+		etag string
+	)
+	if (virtx.if_match != "") {
+		return fn(virtx.if_match)
+	}
+	for i := 0; i < ETAG_RETRIES; i++ {
+		_, etag, err = virtx.c.GetVmEtag(virtx.ctx, uuid)
+		if (err != nil) {
+			return nil, err
+		}
+		t, err = fn(etag)
+		if (client.Status(err) != http.StatusPreconditionFailed) {
+			break
+		}
+		logger.Debug("VM %s definition changed, retrying", uuid)
+	}
+	return t, err
+}
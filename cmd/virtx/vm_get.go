@@ -7,14 +7,19 @@ import (
 )
 
 func vm_get_req(arg string) {
-	virtx.path = fmt.Sprintf("/vms/%s", arg)
-	virtx.method = "GET"
-	virtx.arg = nil
-	virtx.result = &openapi.Vm{}
+	vm, etag, err := virtx.c.GetVmEtag(virtx.ctx, arg)
+	cmd_check(err)
+	vm_get(vm, etag)
 }
 
-func vm_get(vm *openapi.Vm) {
-	if (virtx.disk) {
+func vm_get(vm *openapi.Vm, etag string) {
+	if (virtx.etag) {
+		fmt.Fprintf(virtx.w, "%s\n", etag)
+	} else if (virtx.guest) {
+		fmt.Fprintf(virtx.w, "HOSTNAME\tOS\tVERSION\tADDRESSES\n")
+		fmt.Fprintf(virtx.w, "%s\t%s\t%s\t%v\n", vm.Stats.Guest.Hostname, vm.Stats.Guest.Osname,
+			vm.Stats.Guest.Osv, vm.Stats.Guest.Addresses)
+	} else if (virtx.disk) {
 		fmt.Fprintf(virtx.w, "PATH\tDEVICE\tBUS\tMAN\tPROV\n")
 		vm_get_disk(&vm.Def.Osdisk);
 		for _, disk := range (vm.Def.Disks) {
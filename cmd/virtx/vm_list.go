@@ -7,10 +7,9 @@ import (
 )
 
 func vm_list_req() {
-	virtx.path = "/vms"
-	virtx.method = "GET"
-	virtx.arg = &virtx.vm_list_options
-	virtx.result = &openapi.VmList{}
+	list, err := virtx.c.ListVms(virtx.ctx, &virtx.vm_list_options)
+	cmd_check(err)
+	vm_list(list)
 }
 
 func vm_list(list *openapi.VmList) {
@@ -21,4 +20,7 @@ func vm_list(list *openapi.VmList) {
 		fmt.Fprintf(virtx.w, "%s\t%s\t%s\t%6d\t%v\t%s\t%s\n", item.Uuid, item.Fields.Name, item.Fields.Host,
 			item.Fields.Vlanid, item.Fields.Custom, item.Fields.Runstate, ts.Since(item.Fields.Ts))
 	}
+	if (list.Continue != "") {
+		fmt.Fprintf(virtx.w, "\ncontinue: %s\n", list.Continue)
+	}
 }
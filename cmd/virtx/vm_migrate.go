@@ -1,7 +1,6 @@
 package main
 
 import (
-	"fmt"
 	"suse.com/virtx/pkg/model"
 )
 
@@ -11,11 +10,11 @@ func vm_migrate_req(arg string) {
 	} else {
 		virtx.vm_migrate_options.MigrationType = openapi.MIGRATION_COLD
 	}
-	virtx.path = fmt.Sprintf("/vms/%s/runstate/migrate", arg)
-	virtx.method = "POST"
-	virtx.arg = &virtx.vm_migrate_options
-	virtx.result = nil
+	t, err := virtx.c.Migrate(virtx.ctx, arg, &virtx.vm_migrate_options)
+	cmd_check(err)
+	vm_migrate(t)
 }
 
-func vm_migrate() {
+func vm_migrate(t *openapi.Task) {
+	task_get(t)
 }
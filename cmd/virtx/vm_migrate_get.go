@@ -6,10 +6,9 @@ import (
 )
 
 func vm_migrate_get_req(arg string) {
-	virtx.path = fmt.Sprintf("/vms/%s/runstate/migrate", arg)
-	virtx.method = "GET"
-	virtx.arg = nil
-	virtx.result = &openapi.MigrationInfo{}
+	info, err := virtx.c.GetMigration(virtx.ctx, arg)
+	cmd_check(err)
+	vm_migrate_get(info)
 }
 
 func vm_migrate_get(info *openapi.MigrationInfo) {
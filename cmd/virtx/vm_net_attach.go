@@ -0,0 +1,13 @@
+package main
+
+import (
+	"suse.com/virtx/pkg/model"
+)
+
+func vm_net_attach_req(uuid string, arg string) {
+	var net openapi.Net
+	read_json(arg, &net)
+	t, err := virtx.c.AttachNet(virtx.ctx, uuid, &net)
+	cmd_check(err)
+	task_get(t)
+}
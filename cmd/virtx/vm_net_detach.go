@@ -0,0 +1,11 @@
+package main
+
+import (
+	"suse.com/virtx/pkg/model"
+)
+
+func vm_net_detach_req(uuid string, mac string) {
+	t, err := virtx.c.DetachNet(virtx.ctx, uuid, &openapi.NetDetachOptions{ Mac: mac })
+	cmd_check(err)
+	task_get(t)
+}
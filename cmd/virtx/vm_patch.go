@@ -0,0 +1,28 @@
+package main
+
+import (
+	"encoding/json"
+
+	"suse.com/virtx/pkg/model"
+	"suse.com/virtx/pkg/encoding/jsonpatch"
+)
+
+func vm_patch_req(arg0 string, arg1 string) {
+	var (
+		patch json.RawMessage
+		content_type string = jsonpatch.MERGE_CONTENT_TYPE
+	)
+	read_json(arg1, &patch)
+	if (virtx.json_patch) {
+		content_type = jsonpatch.PATCH_CONTENT_TYPE
+	}
+	t, err := vm_etag_retry(arg0, func(etag string) (*openapi.Task, error) {
+		return virtx.c.PatchVm(virtx.ctx, arg0, etag, content_type, patch)
+	})
+	cmd_check(err)
+	vm_patch(t)
+}
+
+func vm_patch(t *openapi.Task) {
+	task_get(t)
+}
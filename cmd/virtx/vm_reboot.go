@@ -0,0 +1,5 @@
+package main
+
+func vm_reboot_req(arg string) {
+	cmd_check(virtx.c.Reboot(virtx.ctx, arg, &virtx.vm_reboot_options))
+}
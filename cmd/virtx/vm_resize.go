@@ -0,0 +1,7 @@
+package main
+
+func vm_resize_req(uuid string) {
+	t, err := virtx.c.ResizeVm(virtx.ctx, uuid, &virtx.vm_resize_options)
+	cmd_check(err)
+	task_get(t)
+}
@@ -6,10 +6,9 @@ import (
 )
 
 func vm_runstate_get_req(arg string) {
-	virtx.path = fmt.Sprintf("/vms/%s/runstate", arg)
-	virtx.method = "GET"
-	virtx.arg = nil
-	virtx.result = &openapi.Vmruninfo{}
+	runinfo, err := virtx.c.GetRunstate(virtx.ctx, arg)
+	cmd_check(err)
+	vm_runstate_get(runinfo)
 }
 
 func vm_runstate_get(runinfo *openapi.Vmruninfo) {
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"os"
+
+	"suse.com/virtx/pkg/logger"
+)
+
+func vm_screenshot_get_req(arg string) {
+	data, err := virtx.c.Screenshot(virtx.ctx, arg)
+	cmd_check(err)
+	if (virtx.output == "-") {
+		_, err = os.Stdout.Write(data)
+	} else {
+		err = os.WriteFile(virtx.output, data, 0644)
+	}
+	if (err != nil) {
+		logger.Log("failed to write screenshot: %s", err.Error())
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,8 @@
+package main
+
+func vm_snapshot_create_req(uuid string, name string) {
+	virtx.snapshot_create_options.Name = name
+	t, err := virtx.c.CreateSnapshot(virtx.ctx, uuid, &virtx.snapshot_create_options)
+	cmd_check(err)
+	task_get(t)
+}
@@ -0,0 +1,7 @@
+package main
+
+func vm_snapshot_delete_req(uuid string, name string) {
+	t, err := virtx.c.DeleteSnapshot(virtx.ctx, uuid, name)
+	cmd_check(err)
+	task_get(t)
+}
@@ -0,0 +1,20 @@
+package main
+
+import (
+	"fmt"
+	"suse.com/virtx/pkg/model"
+	"suse.com/virtx/pkg/ts"
+)
+
+func vm_snapshot_list_req(arg string) {
+	list, err := virtx.c.ListSnapshots(virtx.ctx, arg)
+	cmd_check(err)
+	vm_snapshot_list(list)
+}
+
+func vm_snapshot_list(list *openapi.SnapshotList) {
+	fmt.Fprintf(virtx.w, "NAME\tCREATED\tLIVE\tDESCRIPTION\n")
+	for _, item := range (list.Items) {
+		fmt.Fprintf(virtx.w, "%s\t%s\t%t\t%s\n", item.Name, ts.String(item.Ts), item.Live, item.Description)
+	}
+}
@@ -0,0 +1,11 @@
+package main
+
+import (
+	"suse.com/virtx/pkg/model"
+)
+
+func vm_template_create_req(uuid string, name string) {
+	t, err := virtx.c.CreateTemplate(virtx.ctx, uuid, &openapi.VmTemplateOptions{ Name: name })
+	cmd_check(err)
+	task_get(t)
+}
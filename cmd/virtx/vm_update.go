@@ -1,16 +1,18 @@
 package main
 
 import (
-	"fmt"
+	"suse.com/virtx/pkg/model"
 )
 
 func vm_update_req(arg0 string, arg1 string) {
 	read_json(arg1, &virtx.vm_update_options.Vmdef)
-	virtx.path = fmt.Sprintf("/vms/%s", arg0)
-	virtx.method = "PUT"
-	virtx.arg = &virtx.vm_update_options
-	virtx.result = nil
+	t, err := vm_etag_retry(arg0, func(etag string) (*openapi.Task, error) {
+		return virtx.c.UpdateVm(virtx.ctx, arg0, etag, &virtx.vm_update_options)
+	})
+	cmd_check(err)
+	vm_update(t)
 }
 
-func vm_update() {
+func vm_update(t *openapi.Task) {
+	task_get(t)
 }
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"net"
+	"io"
+
+	"suse.com/virtx/pkg/logger"
+)
+
+/* forward a local VNC viewer connection to the VNC server of the VM */
+func vm_vnc_forward(arg string, local net.Conn) {
+	var (
+		err error
+		conn io.ReadWriteCloser
+		done chan struct{} = make(chan struct{})
+	)
+	defer local.Close()
+	conn, err = virtx.c.Vnc(virtx.ctx, arg)
+	if (err != nil) {
+		logger.Log("failed to connect to VNC: %s", err.Error())
+		return
+	}
+	/* closing either side terminates the other copy */
+	go func() {
+		io.Copy(conn, local)
+		conn.Close()
+		close(done)
+	}()
+	io.Copy(local, conn)
+	local.Close()
+	<-done
+}
+
+/* listen on a local address for VNC viewers, until interrupted */
+func vm_vnc_req(arg string) {
+	var (
+		err error
+		l net.Listener
+		local net.Conn
+	)
+	l, err = net.Listen("tcp", virtx.vnc_listen)
+	if (err != nil) {
+		logger.Log("failed to listen on %s: %s", virtx.vnc_listen, err.Error())
+		return
+	}
+	go func() {
+		<-virtx.ctx.Done()
+		l.Close()
+	}()
+	logger.Log("connect a VNC viewer to %s, interrupt to exit", l.Addr().String())
+	for {
+		local, err = l.Accept()
+		if (err != nil) {
+			return
+		}
+		go vm_vnc_forward(arg, local)
+	}
+}
@@ -30,6 +30,9 @@ import (
 	"suse.com/virtx/pkg/virtx"
 	"suse.com/virtx/pkg/logger"
 	"suse.com/virtx/pkg/lockman"
+	"suse.com/virtx/pkg/httpx"
+	"suse.com/virtx/pkg/auth"
+	"suse.com/virtx/pkg/task"
 )
 
 var version string = "unknown"
@@ -38,12 +41,28 @@ func main() {
 	var (
 		err error
 		debug *bool
+		tls_ca, tls_cert, tls_key *string
 	)
 	logger.Log("version %s", version)
 	debug = flag.Bool("D", false, "add debug info to logs")
+	tls_ca = flag.String("tls-ca", "", "PEM CA bundle to verify client certificates (enables mutual TLS)")
+	tls_cert = flag.String("tls-cert", "", "PEM certificate of this host (enables HTTPS)")
+	tls_key = flag.String("tls-key", "", "PEM private key of this host")
 	flag.Parse()
 	logger.Set_debug(*debug)
 
+	/* httpx: configure TLS for the API service and for proxying to other hosts */
+	if (*tls_cert != "" || *tls_key != "") {
+		err = httpx.Init_tls(*tls_ca, *tls_cert, *tls_key)
+		if (err != nil) {
+			logger.Fatal(err.Error())
+		}
+	} else if (*tls_ca != "") {
+		logger.Fatal("-tls-ca requires -tls-cert and -tls-key")
+	} else {
+		logger.Log("TLS not configured, the API is served over plain HTTP")
+	}
+
 	/* hypervisor: initialize and start listening to hypervisor events */
 	err = hypervisor.Connect()
 	if (err != nil) {
@@ -63,6 +82,14 @@ func main() {
 	}
 	defer lockman.Shutdown()
 
+	/* auth: load the bearer tokens from shared storage */
+	err = auth.Init()
+	if (err != nil) {
+		logger.Fatal(err.Error())
+	}
+	if (!auth.Enabled()) {
+		logger.Log("auth: no tokens file, authentication is disabled")
+	}
 	/* virtx service: initialize */
 	virtx.Init()
 	/*
@@ -76,7 +103,8 @@ func main() {
 	}
 	defer serfcomm.Shutdown()
 
-	serfcomm.Start_listening(hypervisor.Get_vm_event_channel(), hypervisor.Get_system_info_channel())
+	serfcomm.Start_listening(hypervisor.Get_vm_event_channel(), hypervisor.Get_system_info_channel(),
+		task.Get_event_channel())
 
 	/* create server subroutine to listen for API requests */
 	virtx_err_ch := virtx.Start_listening()
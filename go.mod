@@ -6,6 +6,7 @@ require (
 	github.com/google/uuid v1.6.0
 	github.com/hashicorp/serf v0.10.1
 	github.com/spf13/cobra v1.9.1
+	golang.org/x/sys v0.20.0
 	libvirt.org/go/libvirt v1.10003.0
 	libvirt.org/go/libvirtxml v1.10003.0
 )
@@ -30,7 +31,6 @@ require (
 	golang.org/x/mod v0.17.0 // indirect
 	golang.org/x/net v0.24.0 // indirect
 	golang.org/x/sync v0.7.0 // indirect
-	golang.org/x/sys v0.20.0 // indirect
 	golang.org/x/tools v0.20.0 // indirect
 )
 
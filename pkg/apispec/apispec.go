@@ -0,0 +1,345 @@
+/*
+ * Copyright (c) 2024-2026 SUSE LLC
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, see
+ * <https://www.gnu.org/licenses/>
+ */
+package apispec
+
+import (
+	_ "embed"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+/*
+ * the OpenAPI document describing the REST API.
+ * pkg/model is generated from the schemas in this document,
+ * so both need to be updated together.
+ */
+//go:embed openapi.json
+var document []byte
+
+type Schema struct {
+	Ref string `json:"$ref"`
+	Type string `json:"type"`
+	Format string `json:"format"`
+	Enum []any `json:"enum"`
+	Items *Schema `json:"items"`
+	Properties map[string]*Schema `json:"properties"`
+	AdditionalProperties *bool `json:"additionalProperties"`
+	AllOf []*Schema `json:"allOf"`
+}
+
+type media_type struct {
+	Schema *Schema `json:"schema"`
+}
+
+type request_body struct {
+	Content map[string]media_type `json:"content"`
+}
+
+type operation struct {
+	RequestBody *request_body `json:"requestBody"`
+}
+
+type spec struct {
+	Paths map[string]map[string]operation `json:"paths"`
+	Components struct {
+		Schemas map[string]*Schema `json:"schemas"`
+	} `json:"components"`
+}
+
+var api spec
+
+/*
+ * FieldError: a request body does not match the schema,
+ * the field is identified by its JSON path, f.e. "vmdef.disks[1].bus"
+ */
+type FieldError struct {
+	Field string
+	Msg string
+}
+
+func (e *FieldError) Error() string {
+	if (e.Field == "") {
+		return e.Msg
+	}
+	return e.Field + ": " + e.Msg
+}
+
+func apispec_field_error(field string, msg string) error {
+	return &FieldError{ Field: field, Msg: msg }
+}
+
+/* return the JSON path of the invalid field, or "" if err is not a FieldError */
+func Error_field(err error) string {
+	var fe *FieldError
+	if (errors.As(err, &fe)) {
+		return fe.Field
+	}
+	return ""
+}
+
+/* return the OpenAPI document in JSON format */
+func Document() []byte {
+	return document
+}
+
+/*
+ * find the schema of the request body for method, path and content_type,
+ * where path is matched against the path templates of the document,
+ * f.e. "/vms/{uuid}/runstate/boot". If the document has no schema for the content type,
+ * the "application/json" one is used. Returns nil if there is no body to validate.
+ */
+func Request_schema(method string, path string, content_type string) *Schema {
+	var (
+		template string
+		methods map[string]operation
+		op operation
+		ok bool
+		media media_type
+	)
+	content_type, _, _ = strings.Cut(content_type, ";")
+	content_type = strings.TrimSpace(content_type)
+	for template, methods = range api.Paths {
+		if (!apispec_path_match(template, path)) {
+			continue
+		}
+		op, ok = methods[strings.ToLower(method)]
+		if (!ok || op.RequestBody == nil) {
+			continue
+		}
+		media, ok = op.RequestBody.Content[content_type]
+		if (!ok) {
+			media, ok = op.RequestBody.Content["application/json"]
+		}
+		if (ok) {
+			return media.Schema
+		}
+	}
+	return nil
+}
+
+func apispec_path_match(template string, path string) bool {
+	var (
+		t []string = strings.Split(template, "/")
+		p []string = strings.Split(path, "/")
+		i int
+	)
+	if (len(t) != len(p)) {
+		return false
+	}
+	for i = range t {
+		if (strings.HasPrefix(t[i], "{") && strings.HasSuffix(t[i], "}")) {
+			if (p[i] == "") {
+				return false
+			}
+			continue
+		}
+		if (t[i] != p[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+/*
+ * validate the JSON request body for method, path and content_type against the document.
+ * Unknown fields, values of the wrong type and out of range enum values are rejected.
+ * Missing fields are accepted, since all fields are marked as required in the document
+ * only to get better code generator results, and the zero value means "unset".
+ * For the same reason null is accepted for any field.
+ * An empty body, or a request without a body in the document, is left to the handler.
+ */
+func Validate(method string, path string, content_type string, body []byte) error {
+	var schema *Schema
+	schema = Request_schema(method, path, content_type)
+	if (schema == nil || len(body) == 0) {
+		return nil
+	}
+	return apispec_validate_body(schema, body)
+}
+
+/* validate the JSON document body against the schema name of the document, f.e. "Vmdef" */
+func Validate_schema(name string, body []byte) error {
+	return apispec_validate_body(&Schema{ Ref: "#/components/schemas/" + name }, body)
+}
+
+func apispec_validate_body(schema *Schema, body []byte) error {
+	var (
+		err error
+		value any
+		d *json.Decoder
+	)
+	d = json.NewDecoder(bytes.NewReader(body))
+	d.UseNumber()
+	err = d.Decode(&value)
+	if (err != nil) {
+		return apispec_field_error("", "invalid JSON: " + err.Error())
+	}
+	return apispec_validate(schema, value, "")
+}
+
+/* resolve $ref and allOf (used in the document only to add a description to a $ref) */
+func apispec_resolve(schema *Schema) (*Schema, string) {
+	var name string
+	for {
+		if (len(schema.AllOf) == 1) {
+			schema = schema.AllOf[0]
+			continue
+		}
+		if (schema.Ref != "") {
+			name = strings.TrimPrefix(schema.Ref, "#/components/schemas/")
+			schema = api.Components.Schemas[name]
+			continue
+		}
+		return schema, name
+	}
+}
+
+func apispec_field(path string, name string) string {
+	if (path == "") {
+		return name
+	}
+	return path + "." + name
+}
+
+func apispec_validate(schema *Schema, value any, path string) error {
+	var (
+		err error
+		name string
+		ok bool
+	)
+	schema, name = apispec_resolve(schema)
+	if (value == nil) {
+		return nil
+	}
+	switch (schema.Type) {
+	case "object":
+		var (
+			obj map[string]any
+			key string
+			prop *Schema
+		)
+		obj, ok = value.(map[string]any)
+		if (!ok) {
+			return apispec_field_error(path, "expected object")
+		}
+		for key = range obj {
+			prop, ok = schema.Properties[key]
+			if (!ok) {
+				if (schema.AdditionalProperties != nil && !*schema.AdditionalProperties) {
+					return apispec_field_error(apispec_field(path, key), "unknown field")
+				}
+				continue
+			}
+			err = apispec_validate(prop, obj[key], apispec_field(path, key))
+			if (err != nil) {
+				return err
+			}
+		}
+	case "array":
+		var (
+			arr []any
+			i int
+		)
+		arr, ok = value.([]any)
+		if (!ok) {
+			return apispec_field_error(path, "expected array")
+		}
+		for i = range arr {
+			err = apispec_validate(schema.Items, arr[i], path + "[" + strconv.Itoa(i) + "]")
+			if (err != nil) {
+				return err
+			}
+		}
+	case "string":
+		var str string
+		str, ok = value.(string)
+		if (!ok) {
+			return apispec_field_error(path, "expected string")
+		}
+		if (len(schema.Enum) > 0 && !apispec_enum_has(schema.Enum, str)) {
+			return apispec_field_error(path, "invalid value " + str)
+		}
+	case "boolean":
+		_, ok = value.(bool)
+		if (!ok) {
+			return apispec_field_error(path, "expected boolean")
+		}
+	case "number":
+		_, ok = value.(json.Number)
+		if (!ok) {
+			return apispec_field_error(path, "expected number")
+		}
+	case "integer":
+		var (
+			n json.Number
+			bits int
+		)
+		n, ok = value.(json.Number)
+		if (!ok) {
+			return apispec_field_error(path, "expected integer")
+		}
+		bits, _ = strconv.Atoi(strings.TrimPrefix(schema.Format, "int"))
+		if (bits == 0) {
+			bits = 64
+		}
+		_, err = strconv.ParseInt(n.String(), 10, bits)
+		if (err != nil) {
+			return apispec_field_error(path, "invalid " + schema.Format + " value " + n.String())
+		}
+		if (len(schema.Enum) > 0 && !apispec_enum_has(schema.Enum, n)) {
+			return apispec_field_error(path, "invalid " + name + " value " + n.String())
+		}
+	}
+	return nil
+}
+
+/* enum values are decoded as float64 for numbers, values as json.Number */
+func apispec_enum_has(enum []any, value any) bool {
+	var (
+		e any
+		n json.Number
+		f float64
+		err error
+		ok bool
+	)
+	n, ok = value.(json.Number)
+	if (ok) {
+		f, err = n.Float64()
+		if (err != nil) {
+			return false
+		}
+		value = f
+	}
+	for _, e = range enum {
+		if (e == value) {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	var err error
+	err = json.Unmarshal(document, &api)
+	if (err != nil) {
+		panic(err)
+	}
+}
@@ -0,0 +1,163 @@
+/*
+ * Copyright (c) 2024-2026 SUSE LLC
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, see
+ * <https://www.gnu.org/licenses/>
+ */
+package apispec
+
+import (
+	"testing"
+	"reflect"
+	"strings"
+
+	"suse.com/virtx/pkg/model"
+)
+
+func Test_validate(t *testing.T) {
+	var tests = []struct {
+		method string
+		path string
+		body string
+		field string
+		ok bool
+	}{
+		{ "POST", "/vms", `{"host":"","vmdef":{"name":"vm1","osdisk":{"path":"/vms/ds/vm1.qcow2","device":0,"bus":1},"disks":null,"nets":[{"mac":"","model":0}]}}`, "", true },
+		{ "POST", "/vms", `{"vmdef":{"name":"vm1","nmae":"typo"}}`, "vmdef.nmae", false },
+		{ "POST", "/vms", `{"vmdef":{"osdisk":{"device":7}}}`, "vmdef.osdisk.device", false },
+		{ "POST", "/vms", `{"vmdef":{"disks":[{"bus":0},{"bus":9}]}}`, "vmdef.disks[1].bus", false },
+		{ "POST", "/vms", `{"vmdef":{"vlanid":40000}}`, "vmdef.vlanid", false },
+		{ "POST", "/vms", `{"vmdef":{"name":1}}`, "vmdef.name", false },
+		{ "POST", "/vms", `{"vmdef":`, "", false },
+		{ "POST", "/vms/0b4b3bb4-6d85-4a6b-9c34-7cda2b20d5d2/runstate/migrate", `{"host":"","migration_type":1}`, "", true },
+		{ "POST", "/vms/0b4b3bb4-6d85-4a6b-9c34-7cda2b20d5d2/runstate/migrate", `{"migration_type":5}`, "migration_type", false },
+		{ "GET", "/vms", `{"sort":"name","page":{"size":10},"extra":true}`, "extra", false },
+		/* no body in the document for these */
+		{ "GET", "/vms/0b4b3bb4-6d85-4a6b-9c34-7cda2b20d5d2", `{"anything":1}`, "", true },
+		{ "GET", "/nonexistent", `{"anything":1}`, "", true },
+		{ "POST", "/vms", ``, "", true },
+	}
+	for _, test := range tests {
+		err := Validate(test.method, test.path, "application/json", []byte(test.body))
+		if ((err == nil) != test.ok) {
+			t.Errorf("%s %s %s: unexpected result %v", test.method, test.path, test.body, err)
+			continue
+		}
+		if (Error_field(err) != test.field) {
+			t.Errorf("%s %s %s: field %q, expected %q", test.method, test.path, test.body, Error_field(err), test.field)
+		}
+	}
+}
+
+func Test_path_match(t *testing.T) {
+	if (!apispec_path_match("/vms/{uuid}/runstate", "/vms/x/runstate")) {
+		t.Error("expected match")
+	}
+	if (apispec_path_match("/vms/{uuid}", "/vms/")) {
+		t.Error("empty path parameter should not match")
+	}
+	if (apispec_path_match("/vms/{uuid}", "/vms/x/runstate")) {
+		t.Error("different length should not match")
+	}
+}
+
+/* check that the schemas in the document match the pkg/model types */
+func test_model_match(t *testing.T, typ reflect.Type, seen map[string]bool) {
+	var (
+		schema *Schema
+		ok bool
+		i int
+	)
+	if (seen[typ.Name()]) {
+		return
+	}
+	seen[typ.Name()] = true
+	schema, ok = api.Components.Schemas[typ.Name()]
+	if (!ok) {
+		t.Errorf("%s: missing schema", typ.Name())
+		return
+	}
+	if (len(schema.Properties) != typ.NumField()) {
+		t.Errorf("%s: %d properties, %d fields", typ.Name(), len(schema.Properties), typ.NumField())
+	}
+	for i = 0; i < typ.NumField(); i++ {
+		var (
+			f reflect.StructField = typ.Field(i)
+			name string = strings.Split(f.Tag.Get("json"), ",")[0]
+			ft reflect.Type = f.Type
+		)
+		if (schema.Properties[name] == nil) {
+			t.Errorf("%s: missing property %s", typ.Name(), name)
+		}
+		if (ft.Kind() == reflect.Slice) {
+			ft = ft.Elem()
+		}
+		if (ft.Kind() == reflect.Struct) {
+			test_model_match(t, ft, seen)
+		}
+	}
+}
+
+func Test_model(t *testing.T) {
+	var seen map[string]bool = make(map[string]bool)
+	for _, v := range []any{
+		openapi.VmCreateOptions{}, openapi.VmUpdateOptions{}, openapi.VmDeleteOptions{},
+		openapi.VmListOptions{}, openapi.VmBootOptions{}, openapi.VmShutdownOptions{},
+		openapi.VmMigrateOptions{}, openapi.VmRegisterOptions{}, openapi.HostListOptions{},
+		openapi.Vm{}, openapi.VmList{}, openapi.Host{}, openapi.HostList{}, openapi.Task{},
+		openapi.TaskList{}, openapi.MigrationInfo{}, openapi.Vmruninfo{}, openapi.Health{},
+		openapi.Problem{},
+	} {
+		test_model_match(t, reflect.TypeOf(v), seen)
+	}
+	for _, e := range []struct {
+		name string
+		values int
+	}{
+		{ "DiskDevice", len(openapi.AllowedDiskDeviceEnumValues) },
+		{ "MigrationType", len(openapi.AllowedMigrationTypeEnumValues) },
+		{ "Vmrunstate", len(openapi.AllowedVmrunstateEnumValues) },
+	} {
+		if (len(api.Components.Schemas[e.name].Enum) != e.values) {
+			t.Errorf("%s: enum values do not match the model", e.name)
+		}
+	}
+}
+
+func Test_validate_patch(t *testing.T) {
+	var (
+		path string = "/vms/0b4b3bb4-6d85-4a6b-9c34-7cda2b20d5d2"
+		err error
+	)
+	err = Validate("PATCH", path, "application/merge-patch+json", []byte(`{"memory":{"total":4096},"custom":null}`))
+	if (err != nil) {
+		t.Errorf("merge patch: %v", err)
+	}
+	err = Validate("PATCH", path, "application/merge-patch+json; charset=utf-8", []byte(`{"memroy":{"total":4096}}`))
+	if (Error_field(err) != "memroy") {
+		t.Errorf("merge patch unknown field: %v", err)
+	}
+	err = Validate("PATCH", path, "application/json-patch+json", []byte(`[{"op":"add","path":"/disks/-","value":{"path":"/vms/ds/d1.qcow2"}}]`))
+	if (err != nil) {
+		t.Errorf("json patch: %v", err)
+	}
+	err = Validate("PATCH", path, "application/json-patch+json", []byte(`[{"op":"append","path":"/disks/-"}]`))
+	if (Error_field(err) != "[0].op") {
+		t.Errorf("json patch invalid op: %v", err)
+	}
+	err = Validate_schema("Vmdef", []byte(`{"disks":[{"device":4}]}`))
+	if (Error_field(err) != "disks[0].device") {
+		t.Errorf("Validate_schema: %v", err)
+	}
+}
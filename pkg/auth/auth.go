@@ -0,0 +1,355 @@
+/*
+ * Copyright (c) 2024-2026 SUSE LLC
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, see
+ * <https://www.gnu.org/licenses/>
+ */
+package auth
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"suse.com/virtx/pkg/model"
+	"suse.com/virtx/pkg/logger"
+	. "suse.com/virtx/pkg/constants"
+)
+
+type Role int16
+
+const (
+	ROLE_NONE Role = iota
+	ROLE_VIEWER        /* read-only access to hosts and VMs */
+	ROLE_OPERATOR      /* viewer, and can change the runstate of VMs */
+	ROLE_ADMIN         /* everything, including VM creation, update and deletion */
+)
+
+/* the authenticated caller */
+type Identity struct {
+	Name string
+	Role Role
+}
+
+/*
+ * Authenticator: pluggable authentication method.
+ * Authenticate returns ErrNoCredentials if the request does not carry
+ * credentials for this method, so that the next method can be tried.
+ */
+type Authenticator interface {
+	Authenticate(r *http.Request) (Identity, error)
+}
+
+var ErrNoCredentials error = errors.New("no credentials")
+var ErrInvalidCredentials error = errors.New("invalid credentials")
+
+const (
+	AUTH_RELOAD_INTERVAL = 5 /* seconds between checks for changes of the tokens file */
+)
+
+/*
+ * minimum role required for each operation.
+ * Operations not listed here require ROLE_ADMIN.
+ */
+var role_required = map[openapi.Operation]Role{
+	openapi.OpEventList: ROLE_VIEWER,
+	openapi.OpHostGet: ROLE_VIEWER,
+	openapi.OpHostList: ROLE_VIEWER,
+	openapi.OpMetricsGet: ROLE_VIEWER,
+	openapi.OpTaskGet: ROLE_VIEWER,
+	openapi.OpTaskList: ROLE_VIEWER,
+	openapi.OpVmGet: ROLE_VIEWER,
+	openapi.OpVmList: ROLE_VIEWER,
+	openapi.OpVmMigrateGet: ROLE_VIEWER,
+	openapi.OpVmRunstateGet: ROLE_VIEWER,
+	openapi.OpTemplateList: ROLE_VIEWER,
+	openapi.OpVmSnapshotList: ROLE_VIEWER,
+
+	openapi.OpVmBoot: ROLE_OPERATOR,
+	openapi.OpVmShutdown: ROLE_OPERATOR,
+	openapi.OpVmReboot: ROLE_OPERATOR,
+	openapi.OpVmConsole: ROLE_OPERATOR,
+	openapi.OpVmVnc: ROLE_OPERATOR,
+	openapi.OpVmScreenshot: ROLE_OPERATOR,
+	openapi.OpVmSnapshotCreate: ROLE_OPERATOR,
+	openapi.OpVmSnapshotDelete: ROLE_OPERATOR,
+	openapi.OpVmPause: ROLE_OPERATOR,
+	openapi.OpVmResume: ROLE_OPERATOR,
+	openapi.OpVmMigrate: ROLE_OPERATOR,
+	openapi.OpVmMigrateAbort: ROLE_OPERATOR,
+
+	openapi.OpVmCreate: ROLE_ADMIN,
+	openapi.OpVmClone: ROLE_ADMIN,
+	openapi.OpVmDiskAttach: ROLE_ADMIN,
+	openapi.OpVmDiskDetach: ROLE_ADMIN,
+	openapi.OpVmNetAttach: ROLE_ADMIN,
+	openapi.OpVmNetDetach: ROLE_ADMIN,
+	openapi.OpVmResize: ROLE_ADMIN,
+	openapi.OpVmTemplateCreate: ROLE_ADMIN,
+	openapi.OpVmUpdate: ROLE_ADMIN,
+	openapi.OpVmPatch: ROLE_ADMIN,
+	openapi.OpVmDelete: ROLE_ADMIN,
+	openapi.OpVmRegister: ROLE_ADMIN,
+	openapi.OpVmSnapshotRevert: ROLE_ADMIN,
+}
+
+/*
+ * bearer tokens, read from AUTH_FILE in shared storage, so that all hosts agree.
+ * Each line contains the sha256 hex digest of a token, the role and the name
+ * of the token owner, separated by whitespace. Lines starting with # are ignored.
+ */
+type auth_tokens struct {
+	m sync.Mutex
+	filename string
+	checked time.Time            /* last time we checked the file for changes */
+	mtime time.Time              /* modification time of the loaded file */
+	entries map[string]Identity  /* hex digest -> Identity */
+}
+
+var authenticators []Authenticator
+var enabled bool
+
+func (role Role) String() string {
+	switch (role) {
+	case ROLE_VIEWER:
+		return "viewer"
+	case ROLE_OPERATOR:
+		return "operator"
+	case ROLE_ADMIN:
+		return "admin"
+	}
+	return ""
+}
+
+func (role *Role) Parse(s string) error {
+	switch (s) {
+	case "viewer":
+		*role = ROLE_VIEWER
+		return nil
+	case "operator":
+		*role = ROLE_OPERATOR
+		return nil
+	case "admin":
+		*role = ROLE_ADMIN
+		return nil
+	}
+	return errors.New("could not parse role")
+}
+
+/*
+ * Initialize authentication with the bearer tokens file.
+ * If the file does not exist, authentication is disabled.
+ */
+func Init() error {
+	var (
+		err error
+		tokens *auth_tokens
+	)
+	tokens = &auth_tokens{ filename: AUTH_FILE }
+	_, err = os.Stat(tokens.filename)
+	if (err != nil) {
+		if (errors.Is(err, os.ErrNotExist)) {
+			return nil
+		}
+		return errors.New("auth: " + err.Error())
+	}
+	err = tokens.load()
+	if (err != nil) {
+		return errors.New("auth: " + err.Error())
+	}
+	Register(tokens)
+	enabled = true
+	return nil
+}
+
+/* add an authentication method. Methods are tried in order of registration. */
+func Register(a Authenticator) {
+	authenticators = append(authenticators, a)
+}
+
+func Enabled() bool {
+	return enabled
+}
+
+func Authenticate(r *http.Request) (Identity, error) {
+	var (
+		err error
+		id Identity
+		a Authenticator
+	)
+	for _, a = range authenticators {
+		id, err = a.Authenticate(r)
+		if (errors.Is(err, ErrNoCredentials)) {
+			continue
+		}
+		return id, err
+	}
+	return id, ErrNoCredentials
+}
+
+func Authorized(id Identity, op openapi.Operation) bool {
+	var (
+		required Role
+		present bool
+	)
+	required, present = role_required[op]
+	if (!present) {
+		required = ROLE_ADMIN
+	}
+	return id.Role >= required
+}
+
+func (tokens *auth_tokens) Authenticate(r *http.Request) (Identity, error) {
+	var (
+		header, token string
+		digest [sha256.Size]byte
+		id Identity
+		found bool
+	)
+	header = r.Header.Get("Authorization")
+	token, found = strings.CutPrefix(header, "Bearer ")
+	if (!found && strings.EqualFold(r.Header.Get("Upgrade"), "websocket")) {
+		/* browsers cannot set headers on WebSocket connections (f.e. noVNC), RFC 6750 2.3 */
+		token = r.URL.Query().Get("access_token")
+		found = true
+	}
+	if (!found || token == "") {
+		return id, ErrNoCredentials
+	}
+	digest = sha256.Sum256([]byte(token))
+
+	tokens.m.Lock()
+	defer tokens.m.Unlock()
+	tokens.reload()
+	id, found = auth_lookup(tokens.entries, digest[:])
+	if (!found) {
+		return id, ErrInvalidCredentials
+	}
+	return id, nil
+}
+
+/* look up the digest comparing in constant time */
+func auth_lookup(entries map[string]Identity, digest []byte) (Identity, bool) {
+	var (
+		key string
+		id, result Identity
+		found bool
+		stored []byte
+		err error
+	)
+	for key, id = range entries {
+		stored, err = hex.DecodeString(key)
+		if (err != nil) {
+			continue
+		}
+		if (subtle.ConstantTimeCompare(stored, digest) == 1) {
+			result = id
+			found = true
+		}
+	}
+	return result, found
+}
+
+/* reload the tokens file if it changed. Called with tokens.m held. */
+func (tokens *auth_tokens) reload() {
+	var (
+		err error
+		info os.FileInfo
+	)
+	if (time.Since(tokens.checked) < AUTH_RELOAD_INTERVAL * time.Second) {
+		return
+	}
+	tokens.checked = time.Now()
+	info, err = os.Stat(tokens.filename)
+	if (err != nil) {
+		logger.Log("auth: could not stat %s, keeping previous tokens: %s", tokens.filename, err.Error())
+		return
+	}
+	if (info.ModTime().Equal(tokens.mtime)) {
+		return
+	}
+	err = tokens.load()
+	if (err != nil) {
+		logger.Log("auth: could not reload %s, keeping previous tokens: %s", tokens.filename, err.Error())
+	}
+}
+
+func (tokens *auth_tokens) load() error {
+	var (
+		err error
+		file *os.File
+		info os.FileInfo
+		entries map[string]Identity
+	)
+	file, err = os.Open(tokens.filename)
+	if (err != nil) {
+		return err
+	}
+	defer file.Close()
+	info, err = file.Stat()
+	if (err != nil) {
+		return err
+	}
+	entries, err = auth_parse(bufio.NewScanner(file))
+	if (err != nil) {
+		return err
+	}
+	tokens.entries = entries
+	tokens.mtime = info.ModTime()
+	tokens.checked = time.Now()
+	logger.Log("auth: loaded %d tokens from %s", len(entries), tokens.filename)
+	return nil
+}
+
+func auth_parse(scanner *bufio.Scanner) (map[string]Identity, error) {
+	var (
+		err error
+		line string
+		fields []string
+		digest []byte
+		role Role
+		entries map[string]Identity = make(map[string]Identity)
+	)
+	for scanner.Scan() {
+		line = strings.TrimSpace(scanner.Text())
+		if (line == "" || strings.HasPrefix(line, "#")) {
+			continue
+		}
+		fields = strings.Fields(line)
+		if (len(fields) != 3) {
+			return nil, errors.New("invalid line, expected: DIGEST ROLE NAME")
+		}
+		digest, err = hex.DecodeString(fields[0])
+		if (err != nil || len(digest) != sha256.Size) {
+			return nil, errors.New("invalid sha256 digest for " + fields[2])
+		}
+		err = role.Parse(fields[1])
+		if (err != nil) {
+			return nil, errors.New("invalid role for " + fields[2])
+		}
+		entries[strings.ToLower(fields[0])] = Identity{ Name: fields[2], Role: role }
+	}
+	err = scanner.Err()
+	if (err != nil) {
+		return nil, err
+	}
+	return entries, nil
+}
@@ -0,0 +1,173 @@
+/*
+ * Copyright (c) 2024-2026 SUSE LLC
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, see
+ * <https://www.gnu.org/licenses/>
+ */
+package auth
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"suse.com/virtx/pkg/model"
+)
+
+func digest_hex(token string) string {
+	d := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(d[:])
+}
+
+/* *** Role *** */
+
+func Test_role_parse(t *testing.T) {
+	cases := []struct {
+		input   string
+		want    Role
+		wantErr bool
+	}{
+		{"viewer", ROLE_VIEWER, false},
+		{"operator", ROLE_OPERATOR, false},
+		{"admin", ROLE_ADMIN, false},
+		{"root", ROLE_NONE, true},
+		{"", ROLE_NONE, true},
+	}
+	for _, tc := range cases {
+		var role Role
+		err := role.Parse(tc.input)
+		if (tc.wantErr) {
+			if (err == nil) {
+				t.Errorf("Role.Parse(%q): expected error", tc.input)
+			}
+		} else if (err != nil) {
+			t.Errorf("Role.Parse(%q): %v", tc.input, err)
+		} else if (role != tc.want || role.String() != tc.input) {
+			t.Errorf("Role.Parse(%q) = %d (%q), want %d", tc.input, role, role.String(), tc.want)
+		}
+	}
+}
+
+/* *** Authorized *** */
+
+func Test_authorized(t *testing.T) {
+	cases := []struct {
+		role Role
+		op   openapi.Operation
+		want bool
+	}{
+		{ROLE_NONE, openapi.OpVmList, false},
+		{ROLE_VIEWER, openapi.OpVmList, true},
+		{ROLE_VIEWER, openapi.OpHostGet, true},
+		{ROLE_VIEWER, openapi.OpVmBoot, false},
+		{ROLE_OPERATOR, openapi.OpVmBoot, true},
+		{ROLE_OPERATOR, openapi.OpVmMigrate, true},
+		{ROLE_OPERATOR, openapi.OpVmDelete, false},
+		{ROLE_ADMIN, openapi.OpVmDelete, true},
+		{ROLE_OPERATOR, openapi.Operation(999), false},
+		{ROLE_ADMIN, openapi.Operation(999), true},
+	}
+	for _, tc := range cases {
+		got := Authorized(Identity{Name: "test", Role: tc.role}, tc.op)
+		if (got != tc.want) {
+			t.Errorf("Authorized(%s, %d) = %v, want %v", tc.role.String(), tc.op, got, tc.want)
+		}
+	}
+}
+
+/* *** tokens file *** */
+
+func Test_auth_parse(t *testing.T) {
+	input := "# comment\n\n" +
+		digest_hex("secret1") + " admin alice\n" +
+		"  " + digest_hex("secret2") + "\toperator   bob  \n"
+	entries, err := auth_parse(bufio.NewScanner(strings.NewReader(input)))
+	if (err != nil) {
+		t.Fatalf("auth_parse: %v", err)
+	}
+	if (len(entries) != 2) {
+		t.Fatalf("auth_parse: expected 2 entries, got %d", len(entries))
+	}
+	id := entries[digest_hex("secret2")]
+	if (id.Name != "bob" || id.Role != ROLE_OPERATOR) {
+		t.Errorf("auth_parse: expected {bob, operator}, got {%s, %s}", id.Name, id.Role.String())
+	}
+}
+
+func Test_auth_parse_invalid(t *testing.T) {
+	cases := []string{
+		"abcd admin alice\n",
+		digest_hex("x") + " superuser alice\n",
+		digest_hex("x") + " admin\n",
+		digest_hex("x") + " admin alice extra\n",
+	}
+	for _, input := range cases {
+		_, err := auth_parse(bufio.NewScanner(strings.NewReader(input)))
+		if (err == nil) {
+			t.Errorf("auth_parse(%q): expected error", input)
+		}
+	}
+}
+
+func Test_tokens_authenticate(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "tokens")
+	err := os.WriteFile(filename, []byte(digest_hex("s3cr3t") + " viewer carol\n"), 0600)
+	if (err != nil) {
+		t.Fatal(err)
+	}
+	tokens := &auth_tokens{ filename: filename }
+	err = tokens.load()
+	if (err != nil) {
+		t.Fatalf("load: %v", err)
+	}
+	cases := []struct {
+		header  string
+		want    error
+	}{
+		{"", ErrNoCredentials},
+		{"Basic Y2Fyb2w6czNjcjN0", ErrNoCredentials},
+		{"Bearer ", ErrNoCredentials},
+		{"Bearer wrong", ErrInvalidCredentials},
+		{"Bearer s3cr3t", nil},
+	}
+	for _, tc := range cases {
+		r, _ := http.NewRequest("GET", "/vms", nil)
+		if (tc.header != "") {
+			r.Header.Set("Authorization", tc.header)
+		}
+		id, err := tokens.Authenticate(r)
+		if (err != tc.want) {
+			t.Errorf("Authenticate(%q): expected %v, got %v", tc.header, tc.want, err)
+		}
+		if (err == nil && (id.Name != "carol" || id.Role != ROLE_VIEWER)) {
+			t.Errorf("Authenticate(%q): unexpected identity {%s, %s}", tc.header, id.Name, id.Role.String())
+		}
+	}
+	/* the token in the query is only accepted for WebSocket upgrades */
+	r, _ := http.NewRequest("GET", "/vms/x/vnc?access_token=s3cr3t", nil)
+	_, err = tokens.Authenticate(r)
+	if (err != ErrNoCredentials) {
+		t.Errorf("access_token without upgrade: expected %v, got %v", ErrNoCredentials, err)
+	}
+	r.Header.Set("Upgrade", "websocket")
+	id, err := tokens.Authenticate(r)
+	if (err != nil || id.Name != "carol") {
+		t.Errorf("access_token with upgrade: %v", err)
+	}
+}
@@ -0,0 +1,282 @@
+/*
+ * Copyright (c) 2024-2026 SUSE LLC
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, see
+ * <https://www.gnu.org/licenses/>
+ */
+/*
+ * client: Go client for the VirtX REST API.
+ *
+ * All methods take a context, and return a *Error for non-2xx replies,
+ * carrying the problem details provided by the server.
+ * Requests which could not reach an API server are retried on the next
+ * configured API server; POST requests only if the connection could not be
+ * established at all, so that they are never executed twice.
+ */
+package client
+
+import (
+	"net"
+	"net/http"
+	"context"
+	"errors"
+	"encoding/json"
+	"bytes"
+	"io"
+	"fmt"
+	"sync"
+	"time"
+
+	"suse.com/virtx/pkg/model"
+	"suse.com/virtx/pkg/httpx"
+	"suse.com/virtx/pkg/logger"
+	. "suse.com/virtx/pkg/constants"
+)
+
+const (
+	API_PORT = "8080"
+	CLIENT_TIMEOUT = 10
+	CLIENT_IDLE_CONN_MAX = 100
+	CLIENT_IDLE_CONN_MAX_PER_HOST = 10
+	CLIENT_IDLE_TIMEOUT = 15
+	CLIENT_TLS_TIMEOUT = 5
+	CLIENT_IMAGE_MAX = 64 * 1024 * 1024 /* max size of images returned by the API */
+)
+
+type Options struct {
+	Servers []string            /* API servers "host" or "host:port", tried in order */
+	Token string                /* bearer token, "" for none */
+	Tls_ca string               /* PEM CA bundle to verify the API servers */
+	Tls_cert string             /* PEM client certificate */
+	Tls_key string              /* PEM client private key */
+	Timeout time.Duration       /* timeout for each request, 0 for the default */
+	Debug bool                  /* log requests and responses */
+}
+
+type Client struct {
+	servers []string
+	token string
+	scheme string
+	debug bool
+	http http.Client            /* requests, with timeout */
+	stream http.Client          /* event streams, without timeout */
+
+	m sync.Mutex
+	current int                 /* index of the last API server which could be reached */
+}
+
+/* Error: the API server replied with a non-2xx status */
+type Error struct {
+	Status int
+	Problem openapi.Problem
+}
+
+func (e *Error) Error() string {
+	var s string = fmt.Sprintf("%d %s", e.Status, http.StatusText(e.Status))
+	if (e.Problem.Detail != "") {
+		s += ": " + e.Problem.Detail
+	}
+	if (e.Problem.Field != "") {
+		s += " (field " + e.Problem.Field + ")"
+	}
+	return s
+}
+
+/* return the HTTP status of a *Error, or 0 if err is not a *Error */
+func Status(err error) int {
+	var e *Error
+	if (errors.As(err, &e)) {
+		return e.Status
+	}
+	return 0
+}
+
+func New(o Options) (*Client, error) {
+	var (
+		err error
+		c *Client
+		transport *http.Transport
+	)
+	if (len(o.Servers) < 1) {
+		return nil, errors.New("no API server")
+	}
+	c = &Client{
+		token: o.Token,
+		scheme: "http",
+		debug: o.Debug,
+	}
+	for _, server := range o.Servers {
+		if (server == "") {
+			continue
+		}
+		_, _, err = net.SplitHostPort(server)
+		if (err != nil) {
+			server = net.JoinHostPort(server, API_PORT)
+		}
+		c.servers = append(c.servers, server)
+	}
+	if (len(c.servers) < 1) {
+		return nil, errors.New("no API server")
+	}
+	transport = &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		MaxIdleConns: CLIENT_IDLE_CONN_MAX,
+		MaxIdleConnsPerHost: CLIENT_IDLE_CONN_MAX_PER_HOST,
+		IdleConnTimeout: CLIENT_IDLE_TIMEOUT * time.Second,
+		TLSHandshakeTimeout: CLIENT_TLS_TIMEOUT * time.Second,
+	}
+	if (o.Tls_ca != "" || o.Tls_cert != "" || o.Tls_key != "") {
+		transport.TLSClientConfig, err = httpx.Client_tls_config(o.Tls_ca, o.Tls_cert, o.Tls_key)
+		if (err != nil) {
+			return nil, err
+		}
+		c.scheme = "https"
+	}
+	c.http = http.Client{
+		Timeout: o.Timeout,
+		Transport: transport,
+	}
+	if (c.http.Timeout == 0) {
+		c.http.Timeout = CLIENT_TIMEOUT * time.Second
+	}
+	c.stream = http.Client{
+		Transport: transport,
+	}
+	return c, nil
+}
+
+/* release the idle connections */
+func (c *Client) Close() {
+	c.http.CloseIdleConnections()
+}
+
+/*
+ * a request can be retried on another API server if it never reached the server,
+ * or if it only reads. Some PUT and DELETE requests start tasks or change state,
+ * so they are not retried after they may have been sent.
+ */
+func client_retryable(method string, err error) bool {
+	var op *net.OpError
+	if (method == http.MethodGet || method == http.MethodHead) {
+		return true
+	}
+	return errors.As(err, &op) && op.Op == "dial"
+}
+
+/*
+ * send the request to the API servers in turn, until one can be reached.
+ * header contains additional request headers, and can be nil.
+ */
+func (c *Client) send(ctx context.Context, hc *http.Client, method string, path string, header http.Header, body []byte) (*http.Response, error) {
+	var (
+		err error
+		req *http.Request
+		resp *http.Response
+		start int
+	)
+	c.m.Lock()
+	start = c.current
+	c.m.Unlock()
+
+	for i := 0; i < len(c.servers); i++ {
+		var n int = (start + i) % len(c.servers)
+		req, err = http.NewRequestWithContext(ctx, method, c.scheme + "://" + c.servers[n] + path, bytes.NewReader(body))
+		if (err != nil) {
+			return nil, err
+		}
+		for name, values := range header {
+			req.Header[name] = values
+		}
+		if (body != nil && req.Header.Get("Content-Type") == "") {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		if (c.token != "") {
+			req.Header.Set("Authorization", "Bearer " + c.token)
+		}
+		if (c.debug) {
+			logger.Log("%s %s", method, req.URL.String())
+			if (body != nil) {
+				logger.Log("JSON\n%s", string(body))
+			}
+		}
+		resp, err = hc.Do(req)
+		if (err == nil) {
+			c.m.Lock()
+			c.current = n
+			c.m.Unlock()
+			return resp, nil
+		}
+		if (ctx.Err() != nil || !client_retryable(method, err)) {
+			return nil, err
+		}
+		logger.Debug("%s unreachable: %s", c.servers[n], err.Error())
+	}
+	return nil, err
+}
+
+/* return a *Error with the problem details of the non-2xx response */
+func client_error(resp *http.Response) error {
+	return &Error{
+		Status: resp.StatusCode,
+		Problem: httpx.Decode_problem(resp),
+	}
+}
+
+/*
+ * send a request with the JSON encoding of arg as body (if not nil),
+ * and decode the JSON response body into result (if not nil).
+ */
+func (c *Client) do(ctx context.Context, method string, path string, arg any, result any) error {
+	_, err := c.do_header(ctx, method, path, nil, arg, result)
+	return err
+}
+
+/* same as do, with additional request headers, returning the response headers */
+func (c *Client) do_header(ctx context.Context, method string, path string, header http.Header, arg any, result any) (http.Header, error) {
+	var (
+		err error
+		body []byte
+		resp *http.Response
+		data []byte
+	)
+	if (arg != nil) {
+		body, err = json.Marshal(arg)
+		if (err != nil) {
+			return nil, err
+		}
+	}
+	resp, err = c.send(ctx, &c.http, method, path, header, body)
+	if (err != nil) {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if (resp.StatusCode < 200 || resp.StatusCode > 299) {
+		return resp.Header, client_error(resp)
+	}
+	data, err = io.ReadAll(io.LimitReader(resp.Body, HTTP_MAX_BODY_LEN))
+	if (err != nil) {
+		return resp.Header, errors.New("failed to read response: " + err.Error())
+	}
+	if (c.debug) {
+		logger.Log("%s\n%s", resp.Status, string(data))
+	}
+	if (result == nil) {
+		return resp.Header, nil
+	}
+	err = json.Unmarshal(data, result)
+	if (err != nil) {
+		return resp.Header, errors.New("failed to decode response: " + err.Error())
+	}
+	return resp.Header, nil
+}
@@ -0,0 +1,406 @@
+/*
+ * Copyright (c) 2024-2026 SUSE LLC
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, see
+ * <https://www.gnu.org/licenses/>
+ */
+package client
+
+import (
+	"testing"
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"io"
+	"encoding/json"
+
+	"suse.com/virtx/pkg/model"
+	"suse.com/virtx/pkg/httpx"
+	"suse.com/virtx/pkg/websocket"
+)
+
+func test_server(t *testing.T, handler http.HandlerFunc) (*httptest.Server, string) {
+	s := httptest.NewServer(handler)
+	t.Cleanup(s.Close)
+	return s, strings.TrimPrefix(s.URL, "http://")
+}
+
+/* return the address of a closed port, to simulate an unreachable API server */
+func test_unreachable(t *testing.T) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if (err != nil) {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+	return addr
+}
+
+func Test_new(t *testing.T) {
+	_, err := New(Options{})
+	if (err == nil) {
+		t.Error("no servers: expected error")
+	}
+	c, err := New(Options{Servers: []string{"virt1", "", "virt2:9000"}})
+	if (err != nil) {
+		t.Fatal(err)
+	}
+	if (len(c.servers) != 2 || c.servers[0] != "virt1:" + API_PORT || c.servers[1] != "virt2:9000") {
+		t.Errorf("servers = %v", c.servers)
+	}
+}
+
+func Test_list_vms(t *testing.T) {
+	_, addr := test_server(t, func(w http.ResponseWriter, r *http.Request) {
+		if (r.Method != http.MethodGet || r.URL.Path != "/vms") {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if (r.Header.Get("Authorization") != "Bearer secret") {
+			t.Errorf("missing token")
+		}
+		body, _ := io.ReadAll(r.Body)
+		if (!strings.Contains(string(body), `"sort":"name"`)) {
+			t.Errorf("options not sent: %s", body)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"items":[{"uuid":"v1","fields":{"name":"vm1"}}],"continue":"c1"}`)
+	})
+	c, _ := New(Options{Servers: []string{addr}, Token: "secret"})
+	list, err := c.ListVms(context.Background(), &openapi.VmListOptions{Sort: "name"})
+	if (err != nil) {
+		t.Fatal(err)
+	}
+	if (len(list.Items) != 1 || list.Items[0].Fields.Name != "vm1" || list.Continue != "c1") {
+		t.Errorf("unexpected list %+v", list)
+	}
+}
+
+func Test_error(t *testing.T) {
+	_, addr := test_server(t, func(w http.ResponseWriter, r *http.Request) {
+		httpx.Do_error_field(w, http.StatusBadRequest, httpx.ERR_INVALID_PARAMETER, "invalid Disk Path", "disks[0].path")
+	})
+	c, _ := New(Options{Servers: []string{addr}})
+	_, err := c.CreateVm(context.Background(), &openapi.VmCreateOptions{})
+	e, ok := err.(*Error)
+	if (!ok) {
+		t.Fatalf("expected *Error, got %v", err)
+	}
+	if (e.Status != http.StatusBadRequest || e.Problem.Code != httpx.ERR_INVALID_PARAMETER || e.Problem.Field != "disks[0].path") {
+		t.Errorf("unexpected error %+v", e)
+	}
+	if (Status(err) != http.StatusBadRequest) {
+		t.Errorf("Status = %d", Status(err))
+	}
+}
+
+func Test_retry(t *testing.T) {
+	var n int
+	_, addr := test_server(t, func(w http.ResponseWriter, r *http.Request) {
+		n++
+		w.WriteHeader(http.StatusOK)
+	})
+	c, _ := New(Options{Servers: []string{test_unreachable(t), addr}})
+	err := c.Pause(context.Background(), "v1")
+	if (err != nil) {
+		t.Fatalf("POST not retried after dial failure: %v", err)
+	}
+	err = c.Resume(context.Background(), "v1")
+	if (err != nil) {
+		t.Fatal(err)
+	}
+	if (n != 2 || c.current != 1) {
+		t.Errorf("n = %d, current = %d", n, c.current)
+	}
+}
+
+func Test_retryable(t *testing.T) {
+	dial := &net.OpError{ Op: "dial", Err: io.EOF }
+	read := &net.OpError{ Op: "read", Err: io.EOF }
+	cases := []struct {
+		method string
+		err error
+		want bool
+	}{
+		{ http.MethodGet, read, true },
+		{ http.MethodHead, read, true },
+		{ http.MethodPut, read, false },
+		{ http.MethodDelete, read, false },
+		{ http.MethodPost, read, false },
+		{ http.MethodDelete, dial, true },
+		{ http.MethodPatch, dial, true },
+	}
+	for _, c := range cases {
+		if (client_retryable(c.method, c.err) != c.want) {
+			t.Errorf("client_retryable(%s, %v) != %t", c.method, c.err, c.want)
+		}
+	}
+}
+
+func Test_events(t *testing.T) {
+	_, addr := test_server(t, func(w http.ResponseWriter, r *http.Request) {
+		if (r.URL.Query().Get("type") != "vm,task") {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		io.WriteString(w, ": keepalive\n\nevent: vm\ndata: {\"uuid\":\"v1\"}\n\nevent: task\ndata: {}\n\n")
+	})
+	c, _ := New(Options{Servers: []string{addr}})
+	s, err := c.Events(context.Background(), "", "", []string{"vm", "task"})
+	if (err != nil) {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	e, err := s.Next()
+	if (err != nil || e.Type != "vm" || string(e.Data) != `{"uuid":"v1"}`) {
+		t.Errorf("first event %+v, %v", e, err)
+	}
+	e, err = s.Next()
+	if (err != nil || e.Type != "task") {
+		t.Errorf("second event %+v, %v", e, err)
+	}
+	_, err = s.Next()
+	if (err != io.EOF) {
+		t.Errorf("expected EOF, got %v", err)
+	}
+}
+
+func Test_etag(t *testing.T) {
+	_, addr := test_server(t, func(w http.ResponseWriter, r *http.Request) {
+		switch (r.Method) {
+		case http.MethodGet:
+			w.Header().Set("ETag", `"v1"`)
+			io.WriteString(w, `{"uuid":"vm1"}`)
+		case http.MethodDelete:
+			if (r.Header.Get("If-Match") == "") {
+				httpx.Do_error(w, http.StatusPreconditionRequired, httpx.ERR_PRECONDITION_REQUIRED, "If-Match header required")
+			} else if (r.Header.Get("If-Match") != `"v1"`) {
+				httpx.Do_error(w, http.StatusPreconditionFailed, httpx.ERR_PRECONDITION_FAILED, "VM definition has changed")
+			} else {
+				w.WriteHeader(http.StatusAccepted)
+				io.WriteString(w, `{"uuid":"t1"}`)
+			}
+		}
+	})
+	c, _ := New(Options{Servers: []string{addr}})
+	vm, etag, err := c.GetVmEtag(context.Background(), "vm1")
+	if (err != nil || vm.Uuid != "vm1" || etag != `"v1"`) {
+		t.Fatalf("GetVmEtag: %v %q %v", vm, etag, err)
+	}
+	_, err = c.DeleteVm(context.Background(), "vm1", "", nil)
+	if (Status(err) != http.StatusPreconditionRequired) {
+		t.Errorf("expected 428, got %v", err)
+	}
+	_, err = c.DeleteVm(context.Background(), "vm1", `"v0"`, nil)
+	if (Status(err) != http.StatusPreconditionFailed) {
+		t.Errorf("expected 412, got %v", err)
+	}
+	task, err := c.DeleteVm(context.Background(), "vm1", etag, nil)
+	if (err != nil || task.Uuid != "t1") {
+		t.Errorf("DeleteVm: %v %v", task, err)
+	}
+}
+
+func Test_shutdown(t *testing.T) {
+	_, addr := test_server(t, func(w http.ResponseWriter, r *http.Request) {
+		var o openapi.VmShutdownOptions
+		json.NewDecoder(r.Body).Decode(&o)
+		w.WriteHeader(http.StatusAccepted)
+		if (o.Timeout > 0) {
+			io.WriteString(w, `{"uuid":"t1"}`)
+		}
+	})
+	c, _ := New(Options{Servers: []string{addr}})
+	task, err := c.Shutdown(context.Background(), "vm1", nil)
+	if (err != nil || task != nil) {
+		t.Errorf("Shutdown: %v %v", task, err)
+	}
+	task, err = c.Shutdown(context.Background(), "vm1", &openapi.VmShutdownOptions{ Timeout: 60 })
+	if (err != nil || task == nil || task.Uuid != "t1") {
+		t.Errorf("Shutdown with timeout: %v %v", task, err)
+	}
+}
+
+func Test_console(t *testing.T) {
+	_, addr := test_server(t, func(w http.ResponseWriter, r *http.Request) {
+		if (r.URL.Query().Get("force") != "true") {
+			httpx.Do_error(w, http.StatusFailedDependency, httpx.ERR_HYPERVISOR, "console busy")
+			return
+		}
+		ws, err := websocket.Upgrade(w, r)
+		if (err != nil) {
+			t.Error(err)
+			return
+		}
+		defer ws.Close()
+		io.Copy(ws, ws)
+	})
+	c, _ := New(Options{Servers: []string{addr}})
+	_, err := c.Console(context.Background(), "vm1", false)
+	if (Status(err) != http.StatusFailedDependency) {
+		t.Errorf("expected 424, got %v", err)
+	}
+	conn, err := c.Console(context.Background(), "vm1", true)
+	if (err != nil) {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	io.WriteString(conn, "login: ")
+	buf := make([]byte, 7)
+	_, err = io.ReadFull(conn, buf)
+	if (err != nil || string(buf) != "login: ") {
+		t.Errorf("echo %q, %v", buf, err)
+	}
+}
+
+func Test_screenshot(t *testing.T) {
+	_, addr := test_server(t, func(w http.ResponseWriter, r *http.Request) {
+		if (r.URL.Path != "/vms/vm1/screenshot") {
+			httpx.Do_error(w, http.StatusNotFound, httpx.ERR_NOT_FOUND, "unknown uuid")
+			return
+		}
+		w.Header().Set("Content-Type", "image/png")
+		io.WriteString(w, "\x89PNG")
+	})
+	c, _ := New(Options{Servers: []string{addr}})
+	data, err := c.Screenshot(context.Background(), "vm1")
+	if (err != nil || string(data) != "\x89PNG") {
+		t.Errorf("Screenshot: %q %v", data, err)
+	}
+	_, err = c.Screenshot(context.Background(), "vm2")
+	if (Status(err) != http.StatusNotFound) {
+		t.Errorf("expected 404, got %v", err)
+	}
+}
+
+func Test_snapshots(t *testing.T) {
+	_, addr := test_server(t, func(w http.ResponseWriter, r *http.Request) {
+		if (r.Method + " " + r.URL.Path != "GET /vms/vm1/snapshots") {
+			httpx.Do_error(w, http.StatusNotFound, httpx.ERR_NOT_FOUND, "unknown uuid")
+			return
+		}
+		io.WriteString(w, `{"items":[{"name":"s1","description":"","ts":1700000000000,"live":true}]}`)
+	})
+	c, _ := New(Options{Servers: []string{addr}})
+	list, err := c.ListSnapshots(context.Background(), "vm1")
+	if (err != nil || len(list.Items) != 1 || list.Items[0].Name != "s1" || !list.Items[0].Live) {
+		t.Errorf("ListSnapshots: %v %v", list, err)
+	}
+}
+
+func Test_templates(t *testing.T) {
+	_, addr := test_server(t, func(w http.ResponseWriter, r *http.Request) {
+		if (r.Method + " " + r.URL.Path != "GET /templates") {
+			httpx.Do_error(w, http.StatusNotFound, httpx.ERR_NOT_FOUND, "unknown uuid")
+			return
+		}
+		io.WriteString(w, `{"items":[{"name":"sles15","path":"/vms/ds/templates/sles15.qcow2","size":20480}]}`)
+	})
+	c, _ := New(Options{Servers: []string{addr}})
+	list, err := c.ListTemplates(context.Background())
+	if (err != nil || len(list.Items) != 1 || list.Items[0].Size != 20480) {
+		t.Errorf("ListTemplates: %v %v", list, err)
+	}
+}
+
+/*
+ * requests which start a task: check the method, the path and the fields of the body sent,
+ * and that the task is returned. body lists the fields expected in the request body,
+ * nil if the request has no body.
+ */
+func Test_task_requests(t *testing.T) {
+	ctx := context.Background()
+	cases := []struct {
+		name string
+		route string
+		body map[string]any
+		call func(c *Client) (*openapi.Task, error)
+	}{
+		{ "CreateSnapshot", "POST /vms/vm1/snapshots", map[string]any{ "name": "s2", "description": "before update" },
+			func(c *Client) (*openapi.Task, error) {
+				return c.CreateSnapshot(ctx, "vm1", &openapi.SnapshotCreateOptions{ Name: "s2", Description: "before update" })
+			} },
+		{ "DeleteSnapshot", "DELETE /vms/vm1/snapshots/s1", nil,
+			func(c *Client) (*openapi.Task, error) { return c.DeleteSnapshot(ctx, "vm1", "s1") } },
+		{ "RevertSnapshot", "POST /vms/vm1/snapshots/s1/revert", nil,
+			func(c *Client) (*openapi.Task, error) { return c.RevertSnapshot(ctx, "vm1", "s1") } },
+		{ "CreateTemplate", "POST /vms/vm1/template", map[string]any{ "name": "sles15" },
+			func(c *Client) (*openapi.Task, error) {
+				return c.CreateTemplate(ctx, "vm1", &openapi.VmTemplateOptions{ Name: "sles15" })
+			} },
+		{ "AttachDisk", "POST /vms/vm1/disks", map[string]any{ "path": "/vms/ds/vm1/data.qcow2", "size": float64(1024) },
+			func(c *Client) (*openapi.Task, error) {
+				return c.AttachDisk(ctx, "vm1", &openapi.Disk{ Path: "/vms/ds/vm1/data.qcow2", Size: 1024 })
+			} },
+		{ "DetachDisk", "DELETE /vms/vm1/disks", map[string]any{ "path": "/vms/ds/vm1/data.qcow2", "deletestorage": true },
+			func(c *Client) (*openapi.Task, error) {
+				return c.DetachDisk(ctx, "vm1", &openapi.DiskDetachOptions{ Path: "/vms/ds/vm1/data.qcow2", Deletestorage: true })
+			} },
+		{ "AttachNet", "POST /vms/vm1/nets", map[string]any{ "name": "br0", "mac": "52:54:00:12:34:56" },
+			func(c *Client) (*openapi.Task, error) {
+				return c.AttachNet(ctx, "vm1", &openapi.Net{ Name: "br0", Nettype: openapi.NET_BRIDGE, Mac: "52:54:00:12:34:56" })
+			} },
+		{ "DetachNet", "DELETE /vms/vm1/nets", map[string]any{ "mac": "52:54:00:12:34:56" },
+			func(c *Client) (*openapi.Task, error) {
+				return c.DetachNet(ctx, "vm1", &openapi.NetDetachOptions{ Mac: "52:54:00:12:34:56" })
+			} },
+		{ "ResizeVm", "POST /vms/vm1/resize", map[string]any{ "sockets": float64(4), "memory": float64(8192) },
+			func(c *Client) (*openapi.Task, error) {
+				return c.ResizeVm(ctx, "vm1", &openapi.VmResizeOptions{ Sockets: 4, Memory: 8192 })
+			} },
+	}
+	for _, tc := range cases {
+		_, addr := test_server(t, func(w http.ResponseWriter, r *http.Request) {
+			var body map[string]any
+			if (r.Method + " " + r.URL.Path != tc.route) {
+				httpx.Do_error(w, http.StatusNotFound, httpx.ERR_NOT_FOUND, "unknown uuid")
+				return
+			}
+			data, _ := io.ReadAll(r.Body)
+			if (tc.body == nil && len(data) > 0) {
+				t.Errorf("%s: unexpected body %s", tc.name, data)
+			}
+			if (tc.body != nil) {
+				err := json.Unmarshal(data, &body)
+				if (err != nil) {
+					t.Errorf("%s: invalid body %q: %v", tc.name, data, err)
+				}
+				for name, want := range tc.body {
+					if (body[name] != want) {
+						t.Errorf("%s: body field %s = %v, want %v", tc.name, name, body[name], want)
+					}
+				}
+			}
+			w.WriteHeader(http.StatusAccepted)
+			io.WriteString(w, `{"uuid":"t1"}`)
+		})
+		c, _ := New(Options{Servers: []string{addr}})
+		task, err := tc.call(c)
+		if (err != nil || task == nil || task.Uuid != "t1") {
+			t.Errorf("%s: %v %v", tc.name, task, err)
+		}
+	}
+	/* errors are returned for all of them in the same way */
+	_, addr := test_server(t, func(w http.ResponseWriter, r *http.Request) {
+		httpx.Do_error(w, http.StatusNotFound, httpx.ERR_NOT_FOUND, "unknown uuid")
+	})
+	c, _ := New(Options{Servers: []string{addr}})
+	for _, tc := range cases {
+		_, err := tc.call(c)
+		if (Status(err) != http.StatusNotFound) {
+			t.Errorf("%s: expected 404, got %v", tc.name, err)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+/*
+ * Copyright (c) 2024-2026 SUSE LLC
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, see
+ * <https://www.gnu.org/licenses/>
+ */
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"encoding/json"
+	"bufio"
+	"strings"
+	"io"
+)
+
+/* Event: a server-sent event, Data is the JSON HostListItem, VmListItem or Task */
+type Event struct {
+	Type string
+	Data json.RawMessage
+}
+
+type EventStream struct {
+	resp *http.Response
+	scanner *bufio.Scanner
+}
+
+/*
+ * subscribe to the inventory and task changes. Empty vm, host and types match all.
+ * The stream ends when ctx is canceled, or when the server closes it
+ * because the client did not keep up.
+ */
+func (c *Client) Events(ctx context.Context, vm string, host string, types []string) (*EventStream, error) {
+	var (
+		err error
+		query url.Values = url.Values{}
+		path string = "/events"
+		resp *http.Response
+	)
+	if (vm != "") {
+		query.Set("vm", vm)
+	}
+	if (host != "") {
+		query.Set("host", host)
+	}
+	if (len(types) > 0) {
+		query.Set("type", strings.Join(types, ","))
+	}
+	if (len(query) > 0) {
+		path += "?" + query.Encode()
+	}
+	resp, err = c.send(ctx, &c.stream, http.MethodGet, path, nil, nil)
+	if (err != nil) {
+		return nil, err
+	}
+	if (resp.StatusCode != http.StatusOK) {
+		defer resp.Body.Close()
+		return nil, client_error(resp)
+	}
+	return &EventStream{ resp: resp, scanner: bufio.NewScanner(resp.Body) }, nil
+}
+
+/* wait for the next event. Returns io.EOF when the stream ends. */
+func (s *EventStream) Next() (Event, error) {
+	var e Event
+	for s.scanner.Scan() {
+		var line string = s.scanner.Text()
+		switch {
+		case line == "":
+			if (e.Type != "") {
+				return e, nil
+			}
+		case strings.HasPrefix(line, ":"):
+			/* comment, keepalive */
+		case strings.HasPrefix(line, "event: "):
+			e.Type = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			e.Data = append(e.Data, strings.TrimPrefix(line, "data: ")...)
+		}
+	}
+	if (s.scanner.Err() != nil) {
+		return e, s.scanner.Err()
+	}
+	return e, io.EOF
+}
+
+func (s *EventStream) Close() error {
+	return s.resp.Body.Close()
+}
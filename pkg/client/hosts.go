@@ -0,0 +1,143 @@
+/*
+ * Copyright (c) 2024-2026 SUSE LLC
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, see
+ * <https://www.gnu.org/licenses/>
+ */
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"encoding/json"
+	"io"
+
+	"suse.com/virtx/pkg/model"
+	. "suse.com/virtx/pkg/constants"
+)
+
+func (c *Client) ListHosts(ctx context.Context, o *openapi.HostListOptions) (*openapi.HostList, error) {
+	var list openapi.HostList
+	if (o == nil) {
+		o = &openapi.HostListOptions{}
+	}
+	err := c.do(ctx, http.MethodGet, "/hosts", o, &list)
+	if (err != nil) {
+		return nil, err
+	}
+	return &list, nil
+}
+
+func (c *Client) GetHost(ctx context.Context, uuid string) (*openapi.Host, error) {
+	var host openapi.Host
+	err := c.do(ctx, http.MethodGet, "/hosts/" + url.PathEscape(uuid), nil, &host)
+	if (err != nil) {
+		return nil, err
+	}
+	return &host, nil
+}
+
+func (c *Client) ListTemplates(ctx context.Context) (*openapi.TemplateList, error) {
+	var list openapi.TemplateList
+	err := c.do(ctx, http.MethodGet, "/templates", nil, &list)
+	if (err != nil) {
+		return nil, err
+	}
+	return &list, nil
+}
+
+func (c *Client) ListTasks(ctx context.Context) (*openapi.TaskList, error) {
+	var list openapi.TaskList
+	err := c.do(ctx, http.MethodGet, "/tasks", nil, &list)
+	if (err != nil) {
+		return nil, err
+	}
+	return &list, nil
+}
+
+func (c *Client) GetTask(ctx context.Context, uuid string) (*openapi.Task, error) {
+	var t openapi.Task
+	err := c.do(ctx, http.MethodGet, "/tasks/" + url.PathEscape(uuid), nil, &t)
+	if (err != nil) {
+		return nil, err
+	}
+	return &t, nil
+}
+
+/*
+ * the metrics and health endpoints describe a single host, so they are
+ * sent to the first API server only, without retries.
+ */
+func (c *Client) host_get(ctx context.Context, path string) (*http.Response, error) {
+	var (
+		err error
+		req *http.Request
+	)
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, c.scheme + "://" + c.servers[0] + path, nil)
+	if (err != nil) {
+		return nil, err
+	}
+	if (c.token != "") {
+		req.Header.Set("Authorization", "Bearer " + c.token)
+	}
+	return c.http.Do(req)
+}
+
+/* return the metrics of the first API server in Prometheus text format */
+func (c *Client) Metrics(ctx context.Context) ([]byte, error) {
+	resp, err := c.host_get(ctx, "/metrics")
+	if (err != nil) {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if (resp.StatusCode != http.StatusOK) {
+		return nil, client_error(resp)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, HTTP_MAX_BODY_LEN))
+}
+
+/* return the OpenAPI document describing the REST API */
+func (c *Client) Openapi(ctx context.Context) (json.RawMessage, error) {
+	var doc json.RawMessage
+	err := c.do(ctx, http.MethodGet, "/openapi.json", nil, &doc)
+	return doc, err
+}
+
+/*
+ * return the liveness (ready == false) or readiness (ready == true) of the first API server.
+ * A failing check is reported in the result with Status "fail", not as an error.
+ */
+func (c *Client) Health(ctx context.Context, ready bool) (*openapi.Health, error) {
+	var (
+		h openapi.Health
+		path string = "/healthz"
+	)
+	if (ready) {
+		path = "/readyz"
+	}
+	resp, err := c.host_get(ctx, path)
+	if (err != nil) {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if (resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusServiceUnavailable) {
+		return nil, client_error(resp)
+	}
+	err = json.NewDecoder(io.LimitReader(resp.Body, HTTP_MAX_BODY_LEN)).Decode(&h)
+	if (err != nil) {
+		return nil, err
+	}
+	return &h, nil
+}
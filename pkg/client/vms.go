@@ -0,0 +1,407 @@
+/*
+ * Copyright (c) 2024-2026 SUSE LLC
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, see
+ * <https://www.gnu.org/licenses/>
+ */
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+
+	"suse.com/virtx/pkg/model"
+	"suse.com/virtx/pkg/websocket"
+)
+
+/* If-Match value matching any VM definition, to skip the concurrency check */
+const ETAG_ANY = "*"
+
+func vm_path(uuid string) string {
+	return "/vms/" + url.PathEscape(uuid)
+}
+
+func (c *Client) ListVms(ctx context.Context, o *openapi.VmListOptions) (*openapi.VmList, error) {
+	var list openapi.VmList
+	if (o == nil) {
+		o = &openapi.VmListOptions{}
+	}
+	err := c.do(ctx, http.MethodGet, "/vms", o, &list)
+	if (err != nil) {
+		return nil, err
+	}
+	return &list, nil
+}
+
+/* create a VM, returning the task which tracks the creation */
+func (c *Client) CreateVm(ctx context.Context, o *openapi.VmCreateOptions) (*openapi.Task, error) {
+	var t openapi.Task
+	err := c.do(ctx, http.MethodPost, "/vms", o, &t)
+	if (err != nil) {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (c *Client) GetVm(ctx context.Context, uuid string) (*openapi.Vm, error) {
+	vm, _, err := c.GetVmEtag(ctx, uuid)
+	return vm, err
+}
+
+/* get the VM and the ETag of its definition, to pass to UpdateVm and DeleteVm */
+func (c *Client) GetVmEtag(ctx context.Context, uuid string) (*openapi.Vm, string, error) {
+	var vm openapi.Vm
+	header, err := c.do_header(ctx, http.MethodGet, vm_path(uuid), nil, nil, &vm)
+	if (err != nil) {
+		return nil, "", err
+	}
+	return &vm, header.Get("ETag"), nil
+}
+
+/*
+ * return the If-Match header for etag.
+ * The server refuses to change a VM definition without it (428 Precondition Required),
+ * and answers 412 Precondition Failed if the definition changed since etag was read.
+ */
+func client_if_match(etag string) http.Header {
+	var header http.Header = make(http.Header)
+	if (etag != "") {
+		header.Set("If-Match", etag)
+	}
+	return header
+}
+
+/*
+ * redefine a powered off VM, returning the task which tracks the update.
+ * Changes to only the name and the custom fields are also accepted while the VM is running.
+ * etag is the ETag returned by GetVmEtag, or ETAG_ANY to overwrite any definition.
+ */
+func (c *Client) UpdateVm(ctx context.Context, uuid string, etag string, o *openapi.VmUpdateOptions) (*openapi.Task, error) {
+	var t openapi.Task
+	_, err := c.do_header(ctx, http.MethodPut, vm_path(uuid), client_if_match(etag), o, &t)
+	if (err != nil) {
+		return nil, err
+	}
+	return &t, nil
+}
+
+/*
+ * change part of the definition of a powered off VM, returning the task which tracks the update.
+ * Patches touching only the name and the custom fields are also accepted while the VM is running.
+ * With content_type jsonpatch.MERGE_CONTENT_TYPE, patch is a JSON merge patch, f.e.
+ * map[string]any{ "memory": map[string]any{ "total": 4096 } } (not an openapi.Vmdef,
+ * which would reset all the other fields), with jsonpatch.PATCH_CONTENT_TYPE it is
+ * a list of JSON patch operations, f.e. []jsonpatch.Operation.
+ * etag is the ETag returned by GetVmEtag, or ETAG_ANY.
+ */
+func (c *Client) PatchVm(ctx context.Context, uuid string, etag string, content_type string, patch any) (*openapi.Task, error) {
+	var (
+		t openapi.Task
+		header http.Header = client_if_match(etag)
+	)
+	header.Set("Content-Type", content_type)
+	_, err := c.do_header(ctx, http.MethodPatch, vm_path(uuid), header, patch, &t)
+	if (err != nil) {
+		return nil, err
+	}
+	return &t, nil
+}
+
+/*
+ * delete a powered off VM, returning the task which tracks the deletion.
+ * etag is the ETag returned by GetVmEtag, or ETAG_ANY to delete any definition.
+ */
+func (c *Client) DeleteVm(ctx context.Context, uuid string, etag string, o *openapi.VmDeleteOptions) (*openapi.Task, error) {
+	var t openapi.Task
+	if (o == nil) {
+		o = &openapi.VmDeleteOptions{}
+	}
+	_, err := c.do_header(ctx, http.MethodDelete, vm_path(uuid), client_if_match(etag), o, &t)
+	if (err != nil) {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (c *Client) GetRunstate(ctx context.Context, uuid string) (*openapi.Vmruninfo, error) {
+	var runinfo openapi.Vmruninfo
+	err := c.do(ctx, http.MethodGet, vm_path(uuid) + "/runstate", nil, &runinfo)
+	if (err != nil) {
+		return nil, err
+	}
+	return &runinfo, nil
+}
+
+func (c *Client) Boot(ctx context.Context, uuid string, o *openapi.VmBootOptions) error {
+	if (o == nil) {
+		o = &openapi.VmBootOptions{}
+	}
+	return c.do(ctx, http.MethodPost, vm_path(uuid) + "/runstate/boot", o, nil)
+}
+
+/*
+ * shutdown a VM. With a Timeout and no Force the shutdown is bounded,
+ * and the returned task tracks it until the VM is powered off; otherwise the task is nil.
+ */
+func (c *Client) Shutdown(ctx context.Context, uuid string, o *openapi.VmShutdownOptions) (*openapi.Task, error) {
+	var t openapi.Task
+	if (o == nil) {
+		o = &openapi.VmShutdownOptions{}
+	}
+	if (o.Force != 0 || o.Timeout == 0) {
+		return nil, c.do(ctx, http.MethodDelete, vm_path(uuid) + "/runstate/boot", o, nil)
+	}
+	err := c.do(ctx, http.MethodDelete, vm_path(uuid) + "/runstate/boot", o, &t)
+	if (err != nil) {
+		return nil, err
+	}
+	return &t, nil
+}
+
+/* reboot a VM with ACPI, or reset it if o.Force is set */
+func (c *Client) Reboot(ctx context.Context, uuid string, o *openapi.VmRebootOptions) error {
+	if (o == nil) {
+		o = &openapi.VmRebootOptions{}
+	}
+	return c.do(ctx, http.MethodPost, vm_path(uuid) + "/runstate/reboot", o, nil)
+}
+
+func (c *Client) Pause(ctx context.Context, uuid string) error {
+	return c.do(ctx, http.MethodPost, vm_path(uuid) + "/runstate/pause", nil, nil)
+}
+
+func (c *Client) Resume(ctx context.Context, uuid string) error {
+	return c.do(ctx, http.MethodDelete, vm_path(uuid) + "/runstate/pause", nil, nil)
+}
+
+/* migrate a VM, returning the task which tracks the migration */
+func (c *Client) Migrate(ctx context.Context, uuid string, o *openapi.VmMigrateOptions) (*openapi.Task, error) {
+	var t openapi.Task
+	err := c.do(ctx, http.MethodPost, vm_path(uuid) + "/runstate/migrate", o, &t)
+	if (err != nil) {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (c *Client) GetMigration(ctx context.Context, uuid string) (*openapi.MigrationInfo, error) {
+	var info openapi.MigrationInfo
+	err := c.do(ctx, http.MethodGet, vm_path(uuid) + "/runstate/migrate", nil, &info)
+	if (err != nil) {
+		return nil, err
+	}
+	return &info, nil
+}
+
+func (c *Client) AbortMigration(ctx context.Context, uuid string) error {
+	return c.do(ctx, http.MethodDelete, vm_path(uuid) + "/runstate/migrate", nil, nil)
+}
+
+/* register a VM which desynced between virtx and libvirt */
+func (c *Client) RegisterVm(ctx context.Context, uuid string, o *openapi.VmRegisterOptions) error {
+	return c.do(ctx, http.MethodPut, vm_path(uuid) + "/register", o, nil)
+}
+
+/* create a new VM as a copy of the powered off VM uuid, the task refers to the new VM */
+func (c *Client) CloneVm(ctx context.Context, uuid string, o *openapi.VmCloneOptions) (*openapi.Task, error) {
+	var t openapi.Task
+	err := c.do(ctx, http.MethodPost, vm_path(uuid) + "/clone", o, &t)
+	if (err != nil) {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (c *Client) CreateTemplate(ctx context.Context, uuid string, o *openapi.VmTemplateOptions) (*openapi.Task, error) {
+	var t openapi.Task
+	err := c.do(ctx, http.MethodPost, vm_path(uuid) + "/template", o, &t)
+	if (err != nil) {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (c *Client) AttachDisk(ctx context.Context, uuid string, disk *openapi.Disk) (*openapi.Task, error) {
+	var t openapi.Task
+	err := c.do(ctx, http.MethodPost, vm_path(uuid) + "/disks", disk, &t)
+	if (err != nil) {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (c *Client) DetachDisk(ctx context.Context, uuid string, o *openapi.DiskDetachOptions) (*openapi.Task, error) {
+	var t openapi.Task
+	err := c.do(ctx, http.MethodDelete, vm_path(uuid) + "/disks", o, &t)
+	if (err != nil) {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (c *Client) AttachNet(ctx context.Context, uuid string, net *openapi.Net) (*openapi.Task, error) {
+	var t openapi.Task
+	err := c.do(ctx, http.MethodPost, vm_path(uuid) + "/nets", net, &t)
+	if (err != nil) {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (c *Client) DetachNet(ctx context.Context, uuid string, o *openapi.NetDetachOptions) (*openapi.Task, error) {
+	var t openapi.Task
+	err := c.do(ctx, http.MethodDelete, vm_path(uuid) + "/nets", o, &t)
+	if (err != nil) {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (c *Client) ResizeVm(ctx context.Context, uuid string, o *openapi.VmResizeOptions) (*openapi.Task, error) {
+	var t openapi.Task
+	err := c.do(ctx, http.MethodPost, vm_path(uuid) + "/resize", o, &t)
+	if (err != nil) {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (c *Client) ListVmTasks(ctx context.Context, uuid string) (*openapi.TaskList, error) {
+	var list openapi.TaskList
+	err := c.do(ctx, http.MethodGet, vm_path(uuid) + "/tasks", nil, &list)
+	if (err != nil) {
+		return nil, err
+	}
+	return &list, nil
+}
+
+func (c *Client) ListSnapshots(ctx context.Context, uuid string) (*openapi.SnapshotList, error) {
+	var list openapi.SnapshotList
+	err := c.do(ctx, http.MethodGet, vm_path(uuid) + "/snapshots", nil, &list)
+	if (err != nil) {
+		return nil, err
+	}
+	return &list, nil
+}
+
+func (c *Client) CreateSnapshot(ctx context.Context, uuid string, o *openapi.SnapshotCreateOptions) (*openapi.Task, error) {
+	var t openapi.Task
+	err := c.do(ctx, http.MethodPost, vm_path(uuid) + "/snapshots", o, &t)
+	if (err != nil) {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (c *Client) DeleteSnapshot(ctx context.Context, uuid string, name string) (*openapi.Task, error) {
+	var t openapi.Task
+	err := c.do(ctx, http.MethodDelete, vm_path(uuid) + "/snapshots/" + url.PathEscape(name), nil, &t)
+	if (err != nil) {
+		return nil, err
+	}
+	return &t, nil
+}
+
+/* revert the powered off VM to the snapshot, the later snapshots are deleted */
+func (c *Client) RevertSnapshot(ctx context.Context, uuid string, name string) (*openapi.Task, error) {
+	var t openapi.Task
+	err := c.do(ctx, http.MethodPost, vm_path(uuid) + "/snapshots/" + url.PathEscape(name) + "/revert", nil, &t)
+	if (err != nil) {
+		return nil, err
+	}
+	return &t, nil
+}
+
+/* take a screenshot of the primary display of the VM, returned as PNG */
+func (c *Client) Screenshot(ctx context.Context, uuid string) ([]byte, error) {
+	var (
+		err error
+		resp *http.Response
+		data []byte
+	)
+	resp, err = c.send(ctx, &c.http, http.MethodGet, vm_path(uuid) + "/screenshot", nil, nil)
+	if (err != nil) {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if (resp.StatusCode != http.StatusOK) {
+		return nil, client_error(resp)
+	}
+	/* read one byte more than the limit to detect a truncated image */
+	data, err = io.ReadAll(io.LimitReader(resp.Body, CLIENT_IMAGE_MAX + 1))
+	if (err != nil) {
+		return nil, errors.New("failed to read response: " + err.Error())
+	}
+	if (len(data) > CLIENT_IMAGE_MAX) {
+		return nil, errors.New("screenshot exceeds the maximum image size")
+	}
+	return data, nil
+}
+
+/*
+ * connect to the serial console of the VM. The result is a byte stream to and from
+ * the console, until either side closes it.
+ * If force is set, any other console session is disconnected.
+ */
+func (c *Client) Console(ctx context.Context, uuid string, force bool) (io.ReadWriteCloser, error) {
+	var path string = vm_path(uuid) + "/console"
+	if (force) {
+		path += "?force=true"
+	}
+	return c.websocket(ctx, path)
+}
+
+/* connect to the VNC server of the VM. The result is a byte stream carrying the RFB protocol. */
+func (c *Client) Vnc(ctx context.Context, uuid string) (io.ReadWriteCloser, error) {
+	return c.websocket(ctx, vm_path(uuid) + "/vnc")
+}
+
+/* open a WebSocket connection to path, used as a byte stream */
+func (c *Client) websocket(ctx context.Context, path string) (io.ReadWriteCloser, error) {
+	var (
+		err error
+		key string
+		header http.Header
+		resp *http.Response
+		rwc io.ReadWriteCloser
+		ok bool
+	)
+	key, header, err = websocket.Client_header()
+	if (err != nil) {
+		return nil, err
+	}
+	resp, err = c.send(ctx, &c.stream, http.MethodGet, path, header, nil)
+	if (err != nil) {
+		return nil, err
+	}
+	if (resp.StatusCode != http.StatusSwitchingProtocols) {
+		defer resp.Body.Close()
+		return nil, client_error(resp)
+	}
+	err = websocket.Check_response(resp, key)
+	if (err != nil) {
+		resp.Body.Close()
+		return nil, err
+	}
+	/* on a protocol switch the body is the connection */
+	rwc, ok = resp.Body.(io.ReadWriteCloser)
+	if (!ok) {
+		resp.Body.Close()
+		return nil, errors.New("websocket: connection is not writable")
+	}
+	return websocket.New_conn(rwc, true), nil
+}
@@ -29,8 +29,10 @@ const (
 	REG_DIR = "/vms/xml/"
 	DS_DIR = "/vms/ds/"
 	CI_DIR = "/vms/ds/ci/"
+	TEMPLATE_DIR = "/vms/ds/templates/"
 	LOCK_DIR = "/vms/lock/"
 	LOCK_SPACE = "__VIRTX__DISKS__"
+	AUTH_FILE = "/vms/auth/tokens"
 	DEV_DIR = "/dev/"
 
 	HTTP_MAX_BODY_LEN = 1048576
@@ -42,4 +44,22 @@ const (
 	NETS_MAX = 8
 	MAC_LEN = 17
 	VLAN_MAX = 4094
+	GUEST_AGENT_CHANNEL = "org.qemu.guest_agent.0"
+	GUEST_ADDRESSES_MAX = 8
+	/*
+	 * custom fields travel with the VM info in every serf message,
+	 * which must fit in SERF_MESSAGE_MAX together with the name,
+	 * the uuids and the guest addresses.
+	 */
+	SERF_MESSAGE_MAX = 1024
+	CUSTOM_FIELDS_MAX = 8
+	CUSTOM_NAME_MAX = 16
+	CUSTOM_VALUE_MAX = 48
+	VNC_PASSWORD_MAX = 8
+	VNC_PASSWORD_HIDDEN = "********"
+	SNAPSHOT_NAME_MAX = 32
+	SNAPSHOTS_MAX = 16
+	SNAPSHOT_SEP = "@"
+	TEMPLATE_NAME_MAX = 64
+	MEMORY_BLOCK = 128 /* MiB, granularity of memory hot-add */
 )
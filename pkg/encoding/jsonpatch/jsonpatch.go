@@ -0,0 +1,399 @@
+/*
+ * Copyright (c) 2024-2026 SUSE LLC
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, see
+ * <https://www.gnu.org/licenses/>
+ */
+package jsonpatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+const (
+	MERGE_CONTENT_TYPE = "application/merge-patch+json" /* RFC 7396 */
+	PATCH_CONTENT_TYPE = "application/json-patch+json" /* RFC 6902 */
+)
+
+/*
+ * Operation: an RFC 6902 JSON Patch operation.
+ * Value is nil if not present, and "null" for a JSON null value.
+ */
+type Operation struct {
+	Op string `json:"op"`
+	Path string `json:"path"`
+	From string `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+func jsonpatch_decode(data []byte) (any, error) {
+	var (
+		err error
+		v any
+		d *json.Decoder = json.NewDecoder(bytes.NewReader(data))
+	)
+	d.UseNumber()
+	err = d.Decode(&v)
+	if (err != nil) {
+		return nil, err
+	}
+	return v, nil
+}
+
+/* RFC 7396 JSON Merge Patch: apply patch to the JSON document doc */
+func Merge(doc []byte, patch []byte) ([]byte, error) {
+	var (
+		err error
+		d, p any
+	)
+	d, err = jsonpatch_decode(doc)
+	if (err != nil) {
+		return nil, errors.New("invalid document: " + err.Error())
+	}
+	p, err = jsonpatch_decode(patch)
+	if (err != nil) {
+		return nil, errors.New("invalid merge patch: " + err.Error())
+	}
+	return json.Marshal(jsonpatch_merge(d, p))
+}
+
+func jsonpatch_merge(target any, patch any) any {
+	var (
+		pm, tm map[string]any
+		ok bool
+		key string
+		value any
+	)
+	pm, ok = patch.(map[string]any)
+	if (!ok) {
+		return patch
+	}
+	tm, ok = target.(map[string]any)
+	if (!ok) {
+		tm = make(map[string]any)
+	}
+	for key, value = range pm {
+		if (value == nil) {
+			delete(tm, key)
+		} else {
+			tm[key] = jsonpatch_merge(tm[key], value)
+		}
+	}
+	return tm
+}
+
+/*
+ * RFC 6902 JSON Patch: apply the array of operations in patch to the JSON document doc.
+ * The operations are applied in order, and if any of them fails, an error is returned
+ * and no change is made.
+ */
+func Apply(doc []byte, patch []byte) ([]byte, error) {
+	var (
+		err error
+		d any
+		ops []Operation
+		i int
+	)
+	d, err = jsonpatch_decode(doc)
+	if (err != nil) {
+		return nil, errors.New("invalid document: " + err.Error())
+	}
+	err = json.Unmarshal(patch, &ops)
+	if (err != nil) {
+		return nil, errors.New("invalid JSON patch: " + err.Error())
+	}
+	for i = range ops {
+		d, err = jsonpatch_apply(d, &ops[i])
+		if (err != nil) {
+			return nil, errors.New("operation " + strconv.Itoa(i) + " (" + ops[i].Op + " " + ops[i].Path + "): " + err.Error())
+		}
+	}
+	return json.Marshal(d)
+}
+
+func jsonpatch_apply(doc any, op *Operation) (any, error) {
+	var (
+		err error
+		path, from []string
+		value any
+	)
+	path, err = jsonpatch_pointer(op.Path)
+	if (err != nil) {
+		return nil, err
+	}
+	switch (op.Op) {
+	case "add", "replace", "test":
+		if (op.Value == nil) {
+			return nil, errors.New("missing value")
+		}
+		value, err = jsonpatch_decode(op.Value)
+		if (err != nil) {
+			return nil, errors.New("invalid value: " + err.Error())
+		}
+	case "move", "copy":
+		from, err = jsonpatch_pointer(op.From)
+		if (err != nil) {
+			return nil, errors.New("from: " + err.Error())
+		}
+		value, err = jsonpatch_get(doc, from)
+		if (err != nil) {
+			return nil, errors.New("from: " + err.Error())
+		}
+	}
+	switch (op.Op) {
+	case "add":
+		return jsonpatch_add(doc, path, value)
+	case "remove":
+		return jsonpatch_remove(doc, path)
+	case "replace":
+		if (len(path) == 0) {
+			return value, nil
+		}
+		doc, err = jsonpatch_remove(doc, path)
+		if (err != nil) {
+			return nil, err
+		}
+		return jsonpatch_add(doc, path, value)
+	case "move":
+		if (strings.HasPrefix(op.Path + "/", op.From + "/") && op.Path != op.From) {
+			return nil, errors.New("cannot move a value into one of its children")
+		}
+		doc, err = jsonpatch_remove(doc, from)
+		if (err != nil) {
+			return nil, err
+		}
+		return jsonpatch_add(doc, path, value)
+	case "copy":
+		/* deep copy, so that later operations on the copy do not affect the source */
+		value, err = jsonpatch_copy(value)
+		if (err != nil) {
+			return nil, err
+		}
+		return jsonpatch_add(doc, path, value)
+	case "test":
+		var current any
+		current, err = jsonpatch_get(doc, path)
+		if (err != nil) {
+			return nil, err
+		}
+		if (!jsonpatch_equal(current, value)) {
+			return nil, errors.New("test failed")
+		}
+		return doc, nil
+	default:
+		return nil, errors.New("invalid op")
+	}
+}
+
+/* split an RFC 6901 JSON pointer into its unescaped reference tokens */
+func jsonpatch_pointer(pointer string) ([]string, error) {
+	var (
+		tokens []string
+		i int
+	)
+	if (pointer == "") {
+		return nil, nil
+	}
+	if (!strings.HasPrefix(pointer, "/")) {
+		return nil, errors.New("invalid path " + pointer)
+	}
+	tokens = strings.Split(pointer[1:], "/")
+	for i = range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(tokens[i], "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+/* parse an array index. "-" (past the end) is accepted only if end is true */
+func jsonpatch_index(token string, length int, end bool) (int, error) {
+	var (
+		err error
+		i int
+	)
+	if (token == "-" && end) {
+		return length, nil
+	}
+	if (token == "" || (len(token) > 1 && token[0] == '0') || strings.TrimLeft(token, "0123456789") != "") {
+		return 0, errors.New("invalid array index " + token)
+	}
+	i, err = strconv.Atoi(token)
+	if (err != nil || i > length || (i == length && !end)) {
+		return 0, errors.New("array index " + token + " out of range")
+	}
+	return i, nil
+}
+
+func jsonpatch_get(doc any, path []string) (any, error) {
+	var (
+		err error
+		i int
+		ok bool
+	)
+	for _, token := range path {
+		switch node := doc.(type) {
+		case map[string]any:
+			doc, ok = node[token]
+			if (!ok) {
+				return nil, errors.New("no such member " + token)
+			}
+		case []any:
+			i, err = jsonpatch_index(token, len(node), false)
+			if (err != nil) {
+				return nil, err
+			}
+			doc = node[i]
+		default:
+			return nil, errors.New("cannot index a scalar value with " + token)
+		}
+	}
+	return doc, nil
+}
+
+/*
+ * call fn on the container (object or array) holding the last token of path,
+ * and return the document with the container replaced by the result of fn.
+ */
+func jsonpatch_update(doc any, path []string, fn func(container any, token string) (any, error)) (any, error) {
+	var (
+		err error
+		child any
+		i int
+		ok bool
+	)
+	if (len(path) == 1) {
+		return fn(doc, path[0])
+	}
+	switch node := doc.(type) {
+	case map[string]any:
+		child, ok = node[path[0]]
+		if (!ok) {
+			return nil, errors.New("no such member " + path[0])
+		}
+		node[path[0]], err = jsonpatch_update(child, path[1:], fn)
+		return node, err
+	case []any:
+		i, err = jsonpatch_index(path[0], len(node), false)
+		if (err != nil) {
+			return nil, err
+		}
+		node[i], err = jsonpatch_update(node[i], path[1:], fn)
+		return node, err
+	default:
+		return nil, errors.New("cannot index a scalar value with " + path[0])
+	}
+}
+
+func jsonpatch_add(doc any, path []string, value any) (any, error) {
+	if (len(path) == 0) {
+		return value, nil
+	}
+	return jsonpatch_update(doc, path, func(container any, token string) (any, error) {
+		switch node := container.(type) {
+		case map[string]any:
+			node[token] = value
+			return node, nil
+		case []any:
+			i, err := jsonpatch_index(token, len(node), true)
+			if (err != nil) {
+				return nil, err
+			}
+			node = append(node, nil)
+			copy(node[i + 1:], node[i:])
+			node[i] = value
+			return node, nil
+		default:
+			return nil, errors.New("cannot add to a scalar value")
+		}
+	})
+}
+
+func jsonpatch_remove(doc any, path []string) (any, error) {
+	if (len(path) == 0) {
+		return nil, errors.New("cannot remove the whole document")
+	}
+	return jsonpatch_update(doc, path, func(container any, token string) (any, error) {
+		switch node := container.(type) {
+		case map[string]any:
+			_, ok := node[token]
+			if (!ok) {
+				return nil, errors.New("no such member " + token)
+			}
+			delete(node, token)
+			return node, nil
+		case []any:
+			i, err := jsonpatch_index(token, len(node), false)
+			if (err != nil) {
+				return nil, err
+			}
+			return append(node[:i], node[i + 1:]...), nil
+		default:
+			return nil, errors.New("cannot remove from a scalar value")
+		}
+	})
+}
+
+func jsonpatch_copy(value any) (any, error) {
+	var (
+		err error
+		data []byte
+	)
+	data, err = json.Marshal(value)
+	if (err != nil) {
+		return nil, err
+	}
+	return jsonpatch_decode(data)
+}
+
+/* compare JSON values, numbers are compared by value, f.e. 1 == 1.0 */
+func jsonpatch_equal(a any, b any) bool {
+	switch va := a.(type) {
+	case map[string]any:
+		vb, ok := b.(map[string]any)
+		if (!ok || len(va) != len(vb)) {
+			return false
+		}
+		for key, value := range va {
+			other, ok := vb[key]
+			if (!ok || !jsonpatch_equal(value, other)) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		vb, ok := b.([]any)
+		if (!ok || len(va) != len(vb)) {
+			return false
+		}
+		for i := range va {
+			if (!jsonpatch_equal(va[i], vb[i])) {
+				return false
+			}
+		}
+		return true
+	case json.Number:
+		vb, ok := b.(json.Number)
+		if (!ok) {
+			return false
+		}
+		fa, erra := va.Float64()
+		fb, errb := vb.Float64()
+		return erra == nil && errb == nil && fa == fb
+	default:
+		return a == b
+	}
+}
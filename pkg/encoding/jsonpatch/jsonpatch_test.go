@@ -0,0 +1,111 @@
+/*
+ * Copyright (c) 2024-2026 SUSE LLC
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, see
+ * <https://www.gnu.org/licenses/>
+ */
+package jsonpatch
+
+import (
+	"testing"
+)
+
+func Test_merge(t *testing.T) {
+	/* test cases from RFC 7396 Appendix A */
+	var tests = []struct {
+		doc, patch, result string
+	}{
+		{ `{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}` },
+		{ `{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}` },
+		{ `{"a":"b"}`, `{"a":null}`, `{}` },
+		{ `{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}` },
+		{ `{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}` },
+		{ `{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}` },
+		{ `{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}` },
+		{ `{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}` },
+		{ `["a","b"]`, `["c","d"]`, `["c","d"]` },
+		{ `{"a":"b"}`, `["c"]`, `["c"]` },
+		{ `{"e":null}`, `{"a":1}`, `{"a":1,"e":null}` },
+		{ `[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}` },
+		{ `{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}` },
+		{ `{"memory":{"total":1024}}`, `{"memory":{"total":12884901888}}`, `{"memory":{"total":12884901888}}` },
+	}
+	for _, test := range tests {
+		result, err := Merge([]byte(test.doc), []byte(test.patch))
+		if (err != nil) {
+			t.Errorf("Merge(%s, %s): %v", test.doc, test.patch, err)
+			continue
+		}
+		if (string(result) != test.result) {
+			t.Errorf("Merge(%s, %s) = %s, expected %s", test.doc, test.patch, result, test.result)
+		}
+	}
+}
+
+func Test_apply(t *testing.T) {
+	/* mostly from RFC 6902 Appendix A */
+	var tests = []struct {
+		doc, patch, result string
+	}{
+		{ `{"foo":"bar"}`, `[{"op":"add","path":"/baz","value":"qux"}]`, `{"baz":"qux","foo":"bar"}` },
+		{ `{"foo":["bar","baz"]}`, `[{"op":"add","path":"/foo/1","value":"qux"}]`, `{"foo":["bar","qux","baz"]}` },
+		{ `{"baz":"qux","foo":"bar"}`, `[{"op":"remove","path":"/baz"}]`, `{"foo":"bar"}` },
+		{ `{"foo":["bar","qux","baz"]}`, `[{"op":"remove","path":"/foo/1"}]`, `{"foo":["bar","baz"]}` },
+		{ `{"baz":"qux","foo":"bar"}`, `[{"op":"replace","path":"/baz","value":"boo"}]`, `{"baz":"boo","foo":"bar"}` },
+		{ `{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`, `[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`,
+		  `{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}` },
+		{ `{"foo":["all","grass","cows","eat"]}`, `[{"op":"move","from":"/foo/1","path":"/foo/3"}]`, `{"foo":["all","cows","eat","grass"]}` },
+		{ `{"baz":"qux","foo":["a",2,"c"]}`, `[{"op":"test","path":"/baz","value":"qux"},{"op":"test","path":"/foo/1","value":2.0}]`, `{"baz":"qux","foo":["a",2,"c"]}` },
+		{ `{"foo":"bar"}`, `[{"op":"add","path":"/child","value":{"grandchild":{}}}]`, `{"child":{"grandchild":{}},"foo":"bar"}` },
+		{ `{"foo":["bar"]}`, `[{"op":"add","path":"/foo/-","value":["abc","def"]}]`, `{"foo":["bar",["abc","def"]]}` },
+		{ `{"/":9,"~1":10}`, `[{"op":"test","path":"/~01","value":10},{"op":"remove","path":"/~1"}]`, `{"~1":10}` },
+		{ `{"foo":null}`, `[{"op":"test","path":"/foo","value":null}]`, `{"foo":null}` },
+		{ `{"a":{"b":[1]}}`, `[{"op":"copy","from":"/a","path":"/c"},{"op":"add","path":"/c/b/-","value":2}]`, `{"a":{"b":[1]},"c":{"b":[1,2]}}` },
+		{ `{"a":1}`, `[{"op":"replace","path":"","value":[1]}]`, `[1]` },
+	}
+	for _, test := range tests {
+		result, err := Apply([]byte(test.doc), []byte(test.patch))
+		if (err != nil) {
+			t.Errorf("Apply(%s, %s): %v", test.doc, test.patch, err)
+			continue
+		}
+		if (string(result) != test.result) {
+			t.Errorf("Apply(%s, %s) = %s, expected %s", test.doc, test.patch, result, test.result)
+		}
+	}
+}
+
+func Test_apply_error(t *testing.T) {
+	var tests = []struct {
+		doc, patch string
+	}{
+		{ `{"foo":"bar"}`, `[{"op":"add","path":"/baz/bat","value":"qux"}]` },
+		{ `{"baz":"qux"}`, `[{"op":"test","path":"/baz","value":"bar"}]` },
+		{ `{"foo":["bar","baz"]}`, `[{"op":"add","path":"/foo/3","value":"x"}]` },
+		{ `{"foo":["bar","baz"]}`, `[{"op":"remove","path":"/foo/01"}]` },
+		{ `{"foo":["bar","baz"]}`, `[{"op":"remove","path":"/foo/-"}]` },
+		{ `{"foo":"bar"}`, `[{"op":"replace","path":"/baz","value":1}]` },
+		{ `{"foo":"bar"}`, `[{"op":"add","path":"/baz"}]` },
+		{ `{"foo":"bar"}`, `[{"op":"frobnicate","path":"/foo"}]` },
+		{ `{"foo":"bar"}`, `[{"op":"remove","path":"foo"}]` },
+		{ `{"a":{"b":1}}`, `[{"op":"move","from":"/a","path":"/a/c"}]` },
+		{ `{"foo":"bar"}`, `{"op":"remove","path":"/foo"}` },
+	}
+	for _, test := range tests {
+		_, err := Apply([]byte(test.doc), []byte(test.patch))
+		if (err == nil) {
+			t.Errorf("Apply(%s, %s): expected error", test.doc, test.patch)
+		}
+	}
+}
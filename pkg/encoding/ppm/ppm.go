@@ -0,0 +1,156 @@
+/*
+ * Copyright (c) 2024-2026 SUSE LLC
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, see
+ * <https://www.gnu.org/licenses/>
+ */
+package ppm
+
+import (
+	"bufio"
+	"errors"
+	"image"
+	"image/color"
+	"io"
+	"strconv"
+)
+
+const (
+	CONTENT_TYPE = "image/x-portable-pixmap"
+	DIMENSION_MAX = 16384
+)
+
+/* read a header token, skipping whitespace and comments */
+func ppm_token(br *bufio.Reader) (string, error) {
+	var (
+		err error
+		b byte
+		token []byte
+	)
+	for {
+		b, err = br.ReadByte()
+		if (err != nil) {
+			return "", err
+		}
+		if (b == '#') {
+			_, err = br.ReadString('\n')
+			if (err != nil) {
+				return "", err
+			}
+			continue
+		}
+		if (b == ' ' || b == '\t' || b == '\r' || b == '\n') {
+			if (len(token) > 0) {
+				/* the single whitespace after maxval is consumed here too */
+				return string(token), nil
+			}
+			continue
+		}
+		token = append(token, b)
+		if (len(token) > 16) {
+			return "", errors.New("ppm: invalid header")
+		}
+	}
+}
+
+func ppm_number(br *bufio.Reader, max int) (int, error) {
+	var (
+		err error
+		token string
+		n int
+	)
+	token, err = ppm_token(br)
+	if (err != nil) {
+		return 0, errors.New("ppm: invalid header: " + err.Error())
+	}
+	n, err = strconv.Atoi(token)
+	if (err != nil || n < 1 || n > max) {
+		return 0, errors.New("ppm: invalid header value " + token)
+	}
+	return n, nil
+}
+
+/*
+ * decode a binary (P6) portable pixmap.
+ * The pixel data is read in full before the image is allocated, so that a header
+ * with large dimensions and little data does not cause a large allocation.
+ */
+func Decode(r io.Reader) (image.Image, error) {
+	var (
+		err error
+		br *bufio.Reader = bufio.NewReader(r)
+		magic string
+		width, height, maxval, sample_size int
+		pixels []byte
+	)
+	magic, err = ppm_token(br)
+	if (err != nil || magic != "P6") {
+		return nil, errors.New("ppm: not a binary portable pixmap")
+	}
+	width, err = ppm_number(br, DIMENSION_MAX)
+	if (err != nil) {
+		return nil, err
+	}
+	height, err = ppm_number(br, DIMENSION_MAX)
+	if (err != nil) {
+		return nil, err
+	}
+	maxval, err = ppm_number(br, 65535)
+	if (err != nil) {
+		return nil, err
+	}
+	sample_size = 1
+	if (maxval >= 256) {
+		sample_size = 2
+	}
+	/* the buffer grows with the data actually read, up to the size given by the header */
+	pixels, err = io.ReadAll(io.LimitReader(br, int64(width) * int64(height) * 3 * int64(sample_size)))
+	if (err != nil || len(pixels) < width * height * 3 * sample_size) {
+		return nil, errors.New("ppm: truncated pixel data")
+	}
+	if (sample_size == 1) {
+		var img *image.RGBA = image.NewRGBA(image.Rect(0, 0, width, height))
+		for y := 0; y < height; y++ {
+			var row []byte = pixels[y * width * 3:]
+			for x := 0; x < width; x++ {
+				if (int(row[x * 3]) > maxval || int(row[x * 3 + 1]) > maxval || int(row[x * 3 + 2]) > maxval) {
+					return nil, errors.New("ppm: sample value above maxval")
+				}
+				img.SetRGBA(x, y, color.RGBA{
+					R: uint8(int(row[x * 3]) * 255 / maxval),
+					G: uint8(int(row[x * 3 + 1]) * 255 / maxval),
+					B: uint8(int(row[x * 3 + 2]) * 255 / maxval),
+					A: 255,
+				})
+			}
+		}
+		return img, nil
+	}
+	var img *image.RGBA64 = image.NewRGBA64(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		var row []byte = pixels[y * width * 6:]
+		for x := 0; x < width; x++ {
+			var c [3]uint16
+			for i := range c {
+				var v int = int(row[x * 6 + i * 2]) << 8 | int(row[x * 6 + i * 2 + 1])
+				if (v > maxval) {
+					return nil, errors.New("ppm: sample value above maxval")
+				}
+				c[i] = uint16(v * 65535 / maxval)
+			}
+			img.SetRGBA64(x, y, color.RGBA64{ R: c[0], G: c[1], B: c[2], A: 65535 })
+		}
+	}
+	return img, nil
+}
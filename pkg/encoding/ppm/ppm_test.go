@@ -0,0 +1,90 @@
+/*
+ * Copyright (c) 2024-2026 SUSE LLC
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, see
+ * <https://www.gnu.org/licenses/>
+ */
+package ppm
+
+import (
+	"runtime"
+	"bytes"
+	"image/color"
+	"testing"
+)
+
+func Test_decode(t *testing.T) {
+	var data []byte = append([]byte("P6\n# screenshot\n2 1\n255\n"), 255, 0, 0, 0, 128, 255)
+	img, err := Decode(bytes.NewReader(data))
+	if (err != nil) {
+		t.Fatal(err)
+	}
+	if (img.Bounds().Dx() != 2 || img.Bounds().Dy() != 1) {
+		t.Fatalf("unexpected bounds %v", img.Bounds())
+	}
+	if (img.At(0, 0) != (color.RGBA{ R: 255, A: 255 })) {
+		t.Errorf("pixel 0: %v", img.At(0, 0))
+	}
+	if (img.At(1, 0) != (color.RGBA{ G: 128, B: 255, A: 255 })) {
+		t.Errorf("pixel 1: %v", img.At(1, 0))
+	}
+}
+
+func Test_decode_16bit(t *testing.T) {
+	var data []byte = append([]byte("P6 1 1 65535 "), 0xff, 0xff, 0x80, 0x00, 0, 0)
+	img, err := Decode(bytes.NewReader(data))
+	if (err != nil) {
+		t.Fatal(err)
+	}
+	if (img.At(0, 0) != (color.RGBA64{ R: 0xffff, G: 0x8000, A: 0xffff })) {
+		t.Errorf("pixel: %v", img.At(0, 0))
+	}
+}
+
+func Test_decode_invalid(t *testing.T) {
+	cases := []string{
+		"",
+		"P3\n1 1\n255\n\x00\x00\x00",
+		"P6\n0 1\n255\n",
+		"P6\n1 1\n70000\n",
+		"P6\n2 2\n255\n\x00\x00\x00",
+		"P6\n1 1\n100\n\x00\x65\x00",
+		"P6\n1 1\n1000\n\x00\x00\x03\xe9\x00\x00",
+	}
+	for _, c := range cases {
+		_, err := Decode(bytes.NewReader([]byte(c)))
+		if (err == nil) {
+			t.Errorf("%q: expected error", c)
+		}
+	}
+}
+
+/* a large header with truncated data must fail without allocating the image */
+func Test_decode_large_header(t *testing.T) {
+	var before, after runtime.MemStats
+	for _, c := range []string{
+		"P6\n16384 16384\n65535\n\x00\x00\x00\x00\x00\x00",
+		"P6\n16384 16384\n255\n\x00\x00\x00",
+	} {
+		runtime.ReadMemStats(&before)
+		_, err := Decode(bytes.NewReader([]byte(c)))
+		runtime.ReadMemStats(&after)
+		if (err == nil) {
+			t.Errorf("%q: expected error", c)
+		}
+		if (after.TotalAlloc - before.TotalAlloc > 1024 * 1024) {
+			t.Errorf("%q: allocated %d bytes", c, after.TotalAlloc - before.TotalAlloc)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+/*
+ * Copyright (c) 2024-2026 SUSE LLC
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, see
+ * <https://www.gnu.org/licenses/>
+ */
+/*
+ * events: publish/subscribe of inventory and task changes,
+ * used to stream them to the API clients.
+ */
+package events
+
+import (
+	"sync"
+)
+
+const (
+	TYPE_HOST = "host"          /* host information or cluster state changed */
+	TYPE_VM = "vm"              /* vm information or runstate changed, or vm deleted */
+	TYPE_TASK = "task"          /* task started, progressed or finished */
+
+	SUBSCRIBER_QUEUE_LEN = 256
+)
+
+type Event struct {
+	Type string
+	Host string                 /* uuid of the host the event refers to */
+	Vm string                   /* uuid of the vm the event refers to, "" for host events */
+	Data any                    /* the model object describing the new state */
+}
+
+/* Filter: empty fields match everything */
+type Filter struct {
+	Types []string
+	Host string
+	Vm string
+}
+
+type Subscriber struct {
+	C chan Event                /* closed if the subscriber could not keep up */
+	filter Filter
+}
+
+var subscribers = struct {
+	m sync.Mutex
+	list map[*Subscriber]struct{}
+}{
+	list: make(map[*Subscriber]struct{}),
+}
+
+func Subscribe(f Filter) *Subscriber {
+	subscribers.m.Lock()
+	defer subscribers.m.Unlock()
+	var s *Subscriber = &Subscriber{
+		C: make(chan Event, SUBSCRIBER_QUEUE_LEN),
+		filter: f,
+	}
+	subscribers.list[s] = struct{}{}
+	return s
+}
+
+func (s *Subscriber) Unsubscribe() {
+	subscribers.m.Lock()
+	defer subscribers.m.Unlock()
+	var present bool
+	_, present = subscribers.list[s]
+	if (present) {
+		delete(subscribers.list, s)
+		close(s.C)
+	}
+}
+
+/*
+ * send the event to all interested subscribers. Never blocks:
+ * a subscriber whose queue is full is dropped, and its channel closed,
+ * so that the client can reconnect and resync.
+ */
+func Publish(e Event) {
+	subscribers.m.Lock()
+	defer subscribers.m.Unlock()
+	var s *Subscriber
+	for s = range subscribers.list {
+		if (!s.filter.match(&e)) {
+			continue
+		}
+		select {
+		case s.C <- e:
+		default:
+			delete(subscribers.list, s)
+			close(s.C)
+		}
+	}
+}
+
+func (f *Filter) match(e *Event) bool {
+	if (f.Host != "" && f.Host != e.Host) {
+		return false
+	}
+	if (f.Vm != "" && f.Vm != e.Vm) {
+		return false
+	}
+	if (len(f.Types) == 0) {
+		return true
+	}
+	for _, t := range f.Types {
+		if (t == e.Type) {
+			return true
+		}
+	}
+	return false
+}
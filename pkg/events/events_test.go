@@ -0,0 +1,82 @@
+/*
+ * Copyright (c) 2024-2026 SUSE LLC
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, see
+ * <https://www.gnu.org/licenses/>
+ */
+package events
+
+import (
+	"testing"
+)
+
+func Test_filter_match(t *testing.T) {
+	vm_event := Event{Type: TYPE_VM, Host: "h1", Vm: "v1"}
+	host_event := Event{Type: TYPE_HOST, Host: "h2"}
+	cases := []struct {
+		filter Filter
+		e      Event
+		want   bool
+	}{
+		{Filter{}, vm_event, true},
+		{Filter{}, host_event, true},
+		{Filter{Vm: "v1"}, vm_event, true},
+		{Filter{Vm: "v2"}, vm_event, false},
+		{Filter{Vm: "v1"}, host_event, false},
+		{Filter{Host: "h2"}, host_event, true},
+		{Filter{Host: "h2"}, vm_event, false},
+		{Filter{Types: []string{TYPE_TASK, TYPE_VM}}, vm_event, true},
+		{Filter{Types: []string{TYPE_TASK}}, vm_event, false},
+		{Filter{Types: []string{TYPE_HOST}, Host: "h2"}, host_event, true},
+	}
+	for i, tc := range cases {
+		got := tc.filter.match(&tc.e)
+		if (got != tc.want) {
+			t.Errorf("case %d: match = %v, want %v", i, got, tc.want)
+		}
+	}
+}
+
+func Test_publish_subscribe(t *testing.T) {
+	s1 := Subscribe(Filter{Vm: "v1"})
+	s2 := Subscribe(Filter{Types: []string{TYPE_HOST}})
+	defer s1.Unsubscribe()
+	defer s2.Unsubscribe()
+
+	Publish(Event{Type: TYPE_VM, Host: "h1", Vm: "v1", Data: "x"})
+	Publish(Event{Type: TYPE_HOST, Host: "h1"})
+	if (len(s1.C) != 1 || len(s2.C) != 1) {
+		t.Fatalf("expected 1 event each, got %d and %d", len(s1.C), len(s2.C))
+	}
+	e := <-s1.C
+	if (e.Vm != "v1" || e.Data.(string) != "x") {
+		t.Errorf("unexpected event %v", e)
+	}
+}
+
+func Test_publish_overflow(t *testing.T) {
+	s := Subscribe(Filter{})
+	for i := 0; i < SUBSCRIBER_QUEUE_LEN + 1; i++ {
+		Publish(Event{Type: TYPE_HOST, Host: "h1"})
+	}
+	n := 0
+	for range s.C {
+		n++
+	}
+	if (n != SUBSCRIBER_QUEUE_LEN) {
+		t.Errorf("expected %d queued events before close, got %d", SUBSCRIBER_QUEUE_LEN, n)
+	}
+	/* already dropped, must not panic */
+	s.Unsubscribe()
+}
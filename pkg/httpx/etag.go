@@ -0,0 +1,37 @@
+/*
+ * Copyright (c) 2024-2026 SUSE LLC
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, see
+ * <https://www.gnu.org/licenses/>
+ */
+package httpx
+
+import (
+	"strings"
+)
+
+/*
+ * check whether the If-Match header value matches etag, using the strong comparison
+ * of RFC 9110: "*" matches any current representation, weak tags never match.
+ */
+func Etag_match(if_match string, etag string) bool {
+	var tag string
+	for _, tag = range strings.Split(if_match, ",") {
+		tag = strings.TrimSpace(tag)
+		if (tag == "*" || (tag == etag && !strings.HasPrefix(tag, "W/"))) {
+			return true
+		}
+	}
+	return false
+}
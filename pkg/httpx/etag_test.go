@@ -0,0 +1,43 @@
+/*
+ * Copyright (c) 2024-2026 SUSE LLC
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, see
+ * <https://www.gnu.org/licenses/>
+ */
+package httpx
+
+import (
+	"testing"
+)
+
+func Test_etag_match(t *testing.T) {
+	var tests = []struct {
+		if_match string
+		etag string
+		ok bool
+	}{
+		{ `"abc"`, `"abc"`, true },
+		{ `"xyz", "abc"`, `"abc"`, true },
+		{ `*`, `"abc"`, true },
+		{ `"abc"`, `"abd"`, false },
+		{ `W/"abc"`, `"abc"`, false },
+		{ `abc`, `"abc"`, false },
+		{ ``, `"abc"`, false },
+	}
+	for _, test := range tests {
+		if (Etag_match(test.if_match, test.etag) != test.ok) {
+			t.Errorf("Etag_match(%s, %s) != %v", test.if_match, test.etag, test.ok)
+		}
+	}
+}
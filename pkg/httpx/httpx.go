@@ -21,14 +21,19 @@ import (
 	"net"
 	"net/http"
 	"net/url"
+	"net/http/httputil"
 	"errors"
 	"encoding/json"
 	"bytes"
 	"io"
 	"time"
 	"strconv"
+	"os"
+	"crypto/tls"
+	"crypto/x509"
 
 	"suse.com/virtx/pkg/logger"
+	"suse.com/virtx/pkg/metrics"
 	. "suse.com/virtx/pkg/constants"
 )
 
@@ -42,10 +47,6 @@ type Request struct {
 	r *http.Request
 	body []byte
 }
-type Response struct {
-	r *http.Response
-	body []byte
-}
 
 const (
 	CLIENT_TIMEOUT = 10
@@ -57,6 +58,13 @@ const (
 	SERVER_TIMEOUT = 10
 )
 
+/*
+ * scheme used to contact the API servers, "https" after Init_tls.
+ * tls_config is the server side configuration, nil if TLS is not configured.
+ */
+var scheme string = "http"
+var tls_config *tls.Config
+
 var client http.Client = http.Client{
 	Timeout: CLIENT_TIMEOUT * time.Second,
 	Transport: &http.Transport{
@@ -67,44 +75,108 @@ var client http.Client = http.Client{
 	},
 }
 
-func Decode_request_body(r *http.Request, arg any) (Request, error) {
+/* load the PEM keypair (if cert or key are set) and the CA pool (if ca is set) */
+func httpx_tls_load(ca string, cert string, key string) ([]tls.Certificate, *x509.CertPool, error) {
 	var (
 		err error
-		vr Request
+		pem []byte
+		pool *x509.CertPool
+		certs []tls.Certificate
 	)
-	vr.r = r
-	defer r.Body.Close()
-	if (arg == nil) {
-		return vr, nil
+	if (cert != "" || key != "") {
+		var keypair tls.Certificate
+		keypair, err = tls.LoadX509KeyPair(cert, key)
+		if (err != nil) {
+			return nil, nil, errors.New("could not load certificate: " + err.Error())
+		}
+		certs = append(certs, keypair)
 	}
-	if (r.ContentLength <= 0) {
-		return vr, errors.New("Body expected but not found")
+	if (ca != "") {
+		pem, err = os.ReadFile(ca)
+		if (err != nil) {
+			return nil, nil, errors.New("could not read CA: " + err.Error())
+		}
+		pool = x509.NewCertPool()
+		if (!pool.AppendCertsFromPEM(pem)) {
+			return nil, nil, errors.New("no valid certificates found in CA " + ca)
+		}
 	}
-	if (r.ContentLength >= HTTP_MAX_BODY_LEN) {
-		return vr, errors.New("content-length exceeded")
+	return certs, pool, nil
+}
+
+/*
+ * return a client TLS configuration presenting the client certificate cert/key if set,
+ * and verifying the server with the CA bundle ca if set, or with the system roots.
+ */
+func Client_tls_config(ca string, cert string, key string) (*tls.Config, error) {
+	var (
+		err error
+		pool *x509.CertPool
+		certs []tls.Certificate
+	)
+	certs, pool, err = httpx_tls_load(ca, cert, key)
+	if (err != nil) {
+		return nil, err
 	}
-	vr.body, err = io.ReadAll(io.LimitReader(r.Body, HTTP_MAX_BODY_LEN))
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		Certificates: certs,
+		RootCAs: pool,
+	}, nil
+}
+
+/*
+ * Configure TLS for the server and for the client (CLI and host-to-host proxying).
+ * cert and key are the PEM certificate and private key of this endpoint,
+ * and are presented as client certificate too when connecting to other hosts.
+ * ca is a PEM bundle used to verify the peer: if set, the server requires and
+ * verifies client certificates (mutual TLS), and the client verifies the server with it
+ * instead of the system roots.
+ */
+func Init_tls(ca string, cert string, key string) error {
+	var (
+		err error
+		pool *x509.CertPool
+		certs []tls.Certificate
+		transport *http.Transport
+	)
+	certs, pool, err = httpx_tls_load(ca, cert, key)
 	if (err != nil) {
-		return vr, errors.New("failed to read body")
+		return err
 	}
-	if (int64(len(vr.body)) > r.ContentLength) {
-		return vr, errors.New("body len exceeds content-length")
+	transport = client.Transport.(*http.Transport)
+	transport.TLSClientConfig = &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		Certificates: certs,
+		RootCAs: pool,
 	}
-	err = json.NewDecoder(bytes.NewReader(vr.body)).Decode(arg)
-	if (err != nil) {
-		return vr, err
+	if (len(certs) > 0) {
+		tls_config = &tls.Config{
+			MinVersion: tls.VersionTLS12,
+			Certificates: certs,
+		}
+		if (pool != nil) {
+			tls_config.ClientCAs = pool
+			tls_config.ClientAuth = tls.RequireAndVerifyClientCert
+		}
 	}
-	return vr, nil
+	scheme = "https"
+	return nil
+}
+
+/* return the server TLS configuration, or nil if TLS is not configured */
+func Tls_config() *tls.Config {
+	return tls_config
 }
 
-func Decode_response_body(r *http.Response, result any) (Response, error) {
+func Decode_request_body(r *http.Request, arg any) (Request, error) {
 	var (
 		err error
-		vr Response
+		vr Request
 	)
 	vr.r = r
 	defer r.Body.Close()
-	if (result == nil) {
+	if (arg == nil) {
 		return vr, nil
 	}
 	if (r.ContentLength <= 0) {
@@ -113,40 +185,18 @@ func Decode_response_body(r *http.Response, result any) (Response, error) {
 	if (r.ContentLength >= HTTP_MAX_BODY_LEN) {
 		return vr, errors.New("content-length exceeded")
 	}
-	if (r.StatusCode >= 200 && r.StatusCode <= 299) {
-		vr.body, err = io.ReadAll(io.LimitReader(r.Body, HTTP_MAX_BODY_LEN))
-		if (err != nil) {
-			return vr, errors.New("failed to read body")
-		}
-		if (int64(len(vr.body)) > r.ContentLength) {
-			return vr, errors.New("body len exceeds content-length")
-		}
-		err = json.NewDecoder(bytes.NewReader(vr.body)).Decode(result)
-	}
-	return vr, err
-}
-
-func Do_request(api_server string, method string, path string, arg any) (*http.Response, error) {
-	var (
-		addr url.URL
-		buf bytes.Buffer
-		err error
-	)
-	addr.Path = path
-	addr.Host = api_server + ":8080"
-	addr.Scheme = "http"
-	err = json.NewEncoder(&buf).Encode(arg)
+	vr.body, err = io.ReadAll(io.LimitReader(r.Body, HTTP_MAX_BODY_LEN))
 	if (err != nil) {
-		return nil, err
+		return vr, errors.New("failed to read body")
 	}
-	req, err := http.NewRequest(method, addr.String(), &buf)
+	if (int64(len(vr.body)) > r.ContentLength) {
+		return vr, errors.New("body len exceeds content-length")
+	}
+	err = json.NewDecoder(bytes.NewReader(vr.body)).Decode(arg)
 	if (err != nil) {
-		return nil, err
+		return vr, err
 	}
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := client.Do(req)
-	return resp, err
+	return vr, nil
 }
 
 func Proxy_request(api_server string, w http.ResponseWriter, vr Request) {
@@ -156,27 +206,24 @@ func Proxy_request(api_server string, w http.ResponseWriter, vr Request) {
 	)
 	if (vr.r.Header.Get("X-VirtX-Loop") != "") {
 		logger.Log("proxy_request loop detected")
-		http.Error(w, "loop detected", http.StatusLoopDetected)
+		Do_error(w, http.StatusLoopDetected, ERR_LOOP, "loop detected")
 		return
 	}
+	metrics.Inc(metrics.PROXY_REQUESTS)
 	newaddr = *vr.r.URL
 	newaddr.Host = api_server + ":8080"
-	if (vr.r.TLS != nil) {
-		newaddr.Scheme = "https"
-	} else {
-		newaddr.Scheme = "http"
-	}
+	newaddr.Scheme = scheme
 	proxyreq, err := http.NewRequest(vr.r.Method, newaddr.String(), bytes.NewReader(vr.body))
 	if (err != nil) {
 		logger.Log("proxy_request http.NewRequest failed: %s", err.Error())
-		http.Error(w, "failed to forward request", http.StatusBadGateway)
+		Do_error(w, http.StatusBadGateway, ERR_PROXY, "failed to forward request: " + err.Error())
 		return
 	}
 	proxyreq.Header = vr.r.Header.Clone()
 	client_ip, _, err := net.SplitHostPort(vr.r.RemoteAddr)
 	if (err != nil) {
 		logger.Log("proxy_request could not decode client address")
-		http.Error(w, "failed to forward request", http.StatusBadGateway)
+		Do_error(w, http.StatusBadGateway, ERR_PROXY, "failed to forward request: invalid client address")
 		return
 	}
 	xff := proxyreq.Header.Get("X-Forwarded-For")
@@ -191,7 +238,7 @@ func Proxy_request(api_server string, w http.ResponseWriter, vr Request) {
 	resp, err := client.Do(proxyreq)
 	if (err != nil) {
 		logger.Log("proxy_request failed: %s", err.Error())
-		http.Error(w, "failed to forward request", http.StatusBadGateway)
+		Do_error(w, http.StatusBadGateway, ERR_PROXY, "failed to forward request to " + api_server)
 		return
 	}
 	defer resp.Body.Close()
@@ -204,6 +251,50 @@ func Proxy_request(api_server string, w http.ResponseWriter, vr Request) {
 	io.Copy(w, resp.Body)
 }
 
+/*
+ * forward a request upgrading the connection (f.e. to a WebSocket) to api_server,
+ * and then copy the data in both directions until either side closes.
+ */
+func Proxy_upgrade(api_server string, w http.ResponseWriter, vr Request) {
+	var (
+		proxy *httputil.ReverseProxy
+		rc *http.ResponseController = http.NewResponseController(w)
+	)
+	if (vr.r.Header.Get("X-VirtX-Loop") != "") {
+		logger.Log("proxy_upgrade loop detected")
+		Do_error(w, http.StatusLoopDetected, ERR_LOOP, "loop detected")
+		return
+	}
+	metrics.Inc(metrics.PROXY_REQUESTS)
+	proxy = &httputil.ReverseProxy{
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			pr.Out.URL.Host = api_server + ":8080"
+			pr.Out.URL.Scheme = scheme
+			pr.Out.Header.Set("X-VirtX-Loop", "1")
+			/* as Proxy_request, append to the X-Forwarded-For of the client */
+			client_ip, _, err := net.SplitHostPort(pr.In.RemoteAddr)
+			if (err == nil) {
+				xff := pr.In.Header.Get("X-Forwarded-For")
+				if (xff != "") {
+					xff = xff + ", " + client_ip
+				} else {
+					xff = client_ip
+				}
+				pr.Out.Header.Set("X-Forwarded-For", xff)
+			}
+		},
+		Transport: client.Transport,
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			logger.Log("proxy_upgrade failed: %s", err.Error())
+			Do_error(w, http.StatusBadGateway, ERR_PROXY, "failed to forward request to " + api_server)
+		},
+	}
+	/* the server read and write timeouts do not apply to the stream */
+	_ = rc.SetReadDeadline(time.Time{})
+	_ = rc.SetWriteDeadline(time.Time{})
+	proxy.ServeHTTP(w, vr.r)
+}
+
 func Do_response(w http.ResponseWriter, http_status int, buf *bytes.Buffer) {
 	if (buf != nil) {
 		w.Header().Set("Content-Type", "application/json")
@@ -0,0 +1,134 @@
+/*
+ * Copyright (c) 2024-2026 SUSE LLC
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, see
+ * <https://www.gnu.org/licenses/>
+ */
+package httpx
+
+import (
+	"net/http"
+	"errors"
+	"encoding/json"
+	"bytes"
+	"io"
+	"strings"
+	"strconv"
+
+	"suse.com/virtx/pkg/model"
+	"suse.com/virtx/pkg/machine"
+	. "suse.com/virtx/pkg/constants"
+)
+
+/*
+ * machine-readable error codes returned in the "code" field of the
+ * problem+json body of all error responses.
+ */
+const (
+	ERR_INVALID_BODY = "invalid-body"
+	ERR_INVALID_UUID = "invalid-uuid"
+	ERR_INVALID_PARAMETER = "invalid-parameter"
+	ERR_INVALID_STATE = "invalid-state"
+	ERR_CONFLICT = "conflict"
+	ERR_PRECONDITION_FAILED = "precondition-failed"
+	ERR_PRECONDITION_REQUIRED = "precondition-required"
+	ERR_NOT_FOUND = "not-found"
+	ERR_NOT_IMPLEMENTED = "not-implemented"
+	ERR_UNSUPPORTED_MEDIA_TYPE = "unsupported-media-type"
+	ERR_UPGRADE_REQUIRED = "upgrade-required"
+	ERR_UNAUTHORIZED = "unauthorized"
+	ERR_FORBIDDEN = "forbidden"
+	ERR_HYPERVISOR = "hypervisor"
+	ERR_HOST_UNAVAILABLE = "host-unavailable"
+	ERR_PROXY = "proxy"
+	ERR_LOOP = "loop-detected"
+	ERR_INTERNAL = "internal"
+)
+
+const (
+	PROBLEM_CONTENT_TYPE = "application/problem+json"
+	PROBLEM_TYPE_PREFIX = "urn:virtx:error:"
+)
+
+/* reply with a problem+json error body */
+func Do_error(w http.ResponseWriter, http_status int, code string, detail string) {
+	Do_error_field(w, http_status, code, detail, "")
+}
+
+/* reply with a problem+json error body about the request field at JSON path "field" */
+func Do_error_field(w http.ResponseWriter, http_status int, code string, detail string, field string) {
+	var (
+		p openapi.Problem
+		buf bytes.Buffer
+		err error
+	)
+	p = openapi.Problem{
+		Type: PROBLEM_TYPE_PREFIX + code,
+		Title: http.StatusText(http_status),
+		Status: int32(http_status),
+		Detail: detail,
+		Code: code,
+		Field: field,
+		Host: machine.Uuid(),
+	}
+	err = json.NewEncoder(&buf).Encode(&p)
+	if (err != nil) {
+		http.Error(w, detail, http_status)
+		return
+	}
+	w.Header().Set("Content-Type", PROBLEM_CONTENT_TYPE)
+	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http_status)
+	w.Write(buf.Bytes())
+}
+
+/*
+ * return the JSON path of the field which caused a Decode_request_body error,
+ * or "" if the error is not about a specific field.
+ */
+func Error_field(err error) string {
+	var te *json.UnmarshalTypeError
+	if (errors.As(err, &te)) {
+		return te.Field
+	}
+	return ""
+}
+
+/*
+ * read the error details from a non-2xx response.
+ * If the server did not provide a problem+json body (f.e. an older version),
+ * fill in what we can from the status and the plain text body.
+ */
+func Decode_problem(r *http.Response) openapi.Problem {
+	var (
+		p openapi.Problem
+		body []byte
+		err error
+	)
+	defer r.Body.Close()
+	body, err = io.ReadAll(io.LimitReader(r.Body, HTTP_MAX_BODY_LEN))
+	if (err == nil && strings.HasPrefix(r.Header.Get("Content-Type"), PROBLEM_CONTENT_TYPE)) {
+		err = json.Unmarshal(body, &p)
+		if (err == nil) {
+			return p
+		}
+	}
+	p = openapi.Problem{
+		Title: http.StatusText(r.StatusCode),
+		Status: int32(r.StatusCode),
+		Detail: strings.TrimSpace(string(body)),
+	}
+	return p
+}
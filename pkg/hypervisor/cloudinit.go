@@ -22,7 +22,6 @@ import (
 	"fmt"
 	"errors"
 
-	"encoding/xml" /* XXX necessary due to missing Marshal() for libvirtxml.DomainLease XXX */
 	"libvirt.org/go/libvirt"
 	"libvirt.org/go/libvirtxml"
 
@@ -132,7 +131,6 @@ func get_iso_xml(disk *openapi.Disk) (string, string, error) {
 		domain_leases []libvirtxml.DomainLease
 		domain_controllers []libvirtxml.DomainController /* ignored, controller 0 is already there */
 		order int = -1
-		lease_bytes []byte
 		lease_xml, disk_xml string
 	)
 	/* disk_count["scsi"] = 0 */
@@ -143,24 +141,10 @@ func get_iso_xml(disk *openapi.Disk) (string, string, error) {
 	if (len(domain_disks) != 1 || len(domain_leases) != 1) {
 		return "", "", errors.New("failed to convert Disk to XML")
 	}
-	/*
-	 * XXX
-	 * libvirtxml package is missing the necessary Marshal() method for leases:
-	 * domain_leases[0].Marshal()
-	 * https://gitlab.com/libvirt/libvirt-go-module/-/work_items/25
-	 * XXX
-	 */
-	s := struct {
-		XMLName xml.Name `xml:"lease"`
-		*libvirtxml.DomainLease
-	}{
-		DomainLease: &domain_leases[0],
-	}
-	lease_bytes, err = xml.Marshal(&s)
+	lease_xml, err = vmdef.Lease_to_xml(&domain_leases[0])
 	if (err != nil) {
-		return "", "", fmt.Errorf("marshalling lease XML: %w", err)
+		return "", "", err
 	}
-	lease_xml = string(lease_bytes)
 	disk_xml, err = domain_disks[0].Marshal()
 	if (err != nil) {
 		return "", "", fmt.Errorf("marshalling disk XML: %w", err)
@@ -0,0 +1,152 @@
+/*
+ * Copyright (c) 2024-2026 SUSE LLC
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, see
+ * <https://www.gnu.org/licenses/>
+ */
+package hypervisor
+
+import (
+	"io"
+	"sync"
+
+	"libvirt.org/go/libvirt"
+)
+
+/*
+ * Console: a stream connected to a character device of a running domain,
+ * used as an io.ReadWriteCloser. It keeps its own libvirt connection,
+ * since it can stay open for a long time.
+ * Close can be called while a Read or Write is blocked, which then fails;
+ * the resources are freed when the last of them returns.
+ */
+type Console struct {
+	conn *libvirt.Connect
+	domain *libvirt.Domain
+	stream *libvirt.Stream
+
+	m sync.Mutex
+	ops int                     /* Read and Write in progress */
+	closed bool
+}
+
+/*
+ * open the console of the domain (the first serial console).
+ * If force is set, a console session which is already open is disconnected,
+ * otherwise opening the console fails.
+ */
+func Open_console(uuid string, force bool) (*Console, error) {
+	var (
+		err error
+		c Console
+		flags libvirt.DomainConsoleFlags = libvirt.DOMAIN_CONSOLE_SAFE
+	)
+	if (force) {
+		flags |= libvirt.DOMAIN_CONSOLE_FORCE
+	}
+	c.conn, err = libvirt.NewConnect(LIBVIRT_URI)
+	if (err != nil) {
+		return nil, err
+	}
+	c.domain, err = c.conn.LookupDomainByUUIDString(uuid)
+	if (err != nil) {
+		c.conn.Close()
+		return nil, err
+	}
+	c.stream, err = c.conn.NewStream(0)
+	if (err != nil) {
+		c.domain.Free()
+		c.conn.Close()
+		return nil, err
+	}
+	err = c.domain.OpenConsole("", c.stream, flags)
+	if (err != nil) {
+		c.stream.Free()
+		c.domain.Free()
+		c.conn.Close()
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (c *Console) free() {
+	c.stream.Free()
+	c.domain.Free()
+	c.conn.Close()
+}
+
+func (c *Console) op_begin() bool {
+	c.m.Lock()
+	defer c.m.Unlock()
+	if (c.closed) {
+		return false
+	}
+	c.ops++
+	return true
+}
+
+func (c *Console) op_end() {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.ops--
+	if (c.closed && c.ops == 0) {
+		c.free()
+	}
+}
+
+/* read the console output, io.EOF when the console is closed */
+func (c *Console) Read(p []byte) (int, error) {
+	if (len(p) == 0) {
+		return 0, nil
+	}
+	if (!c.op_begin()) {
+		return 0, io.EOF
+	}
+	defer c.op_end()
+	return c.stream.Recv(p)
+}
+
+/* write all of p to the console input */
+func (c *Console) Write(p []byte) (int, error) {
+	var (
+		err error
+		n, total int
+	)
+	if (!c.op_begin()) {
+		return 0, io.ErrClosedPipe
+	}
+	defer c.op_end()
+	for (total < len(p)) {
+		n, err = c.stream.Send(p[total:])
+		if (err != nil) {
+			return total, err
+		}
+		total += n
+	}
+	return total, nil
+}
+
+func (c *Console) Close() error {
+	c.m.Lock()
+	defer c.m.Unlock()
+	if (c.closed) {
+		return nil
+	}
+	c.closed = true
+	_ = c.stream.Abort() /* fails the blocked Read and Write */
+	if (c.ops == 0) {
+		c.free()
+	}
+	return nil
+}
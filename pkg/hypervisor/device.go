@@ -0,0 +1,101 @@
+/*
+ * Copyright (c) 2024-2026 SUSE LLC
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, see
+ * <https://www.gnu.org/licenses/>
+ */
+package hypervisor
+
+import (
+	"errors"
+	"time"
+
+	"libvirt.org/go/libvirt"
+	"libvirt.org/go/libvirtxml"
+
+	"suse.com/virtx/pkg/logger"
+	"suse.com/virtx/pkg/vmreg"
+	"suse.com/virtx/pkg/machine"
+)
+
+/*
+ * device hot-plug: the devices are attached and detached both in the persistent definition
+ * and, if the domain is running, in the live one. The registry is then updated,
+ * so that the change is kept when the domain is migrated or registered again.
+ */
+
+/* get the device modification flags affecting the persistent definition, and the live one if active */
+func device_modify_flags(domain *libvirt.Domain) (libvirt.DomainDeviceModifyFlags, bool, error) {
+	var (
+		err error
+		active bool
+		flags libvirt.DomainDeviceModifyFlags = libvirt.DOMAIN_DEVICE_MODIFY_CONFIG
+	)
+	active, err = domain.IsActive()
+	if (err != nil) {
+		return flags, false, err
+	}
+	if (active) {
+		flags |= libvirt.DOMAIN_DEVICE_MODIFY_LIVE
+	}
+	return flags, active, nil
+}
+
+/* store the persistent definition of the domain into the registry */
+func device_save(domain *libvirt.Domain, uuid string) error {
+	var (
+		err error
+		xmlstr string
+	)
+	xmlstr, err = domain.GetXMLDesc(libvirt.DOMAIN_XML_INACTIVE | libvirt.DOMAIN_XML_SECURE)
+	if (err != nil) {
+		return err
+	}
+	err = vmreg.Save(machine.Uuid(), uuid, xmlstr)
+	if (err != nil) {
+		logger.Log("device_save: failed to vmreg.Save(%s, %s)", machine.Uuid(), uuid)
+		return errors.New("failed to save the VM definition: " + err.Error())
+	}
+	return nil
+}
+
+/*
+ * wait until the running domain does not have the device anymore according to has,
+ * which is called with the live definition. Returns false on timeout.
+ */
+func device_wait_removed(domain *libvirt.Domain, has func(d *libvirtxml.Domain) bool) bool {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	deadline := time.After(time.Duration(DEVICE_DETACH_SECONDS) * time.Second)
+	for {
+		select {
+		case <- ticker.C:
+			var (
+				err error
+				xmlstr string
+				d libvirtxml.Domain
+			)
+			xmlstr, err = domain.GetXMLDesc(0)
+			if (err == nil) {
+				err = d.Unmarshal(xmlstr)
+			}
+			if (err == nil && d.Devices != nil && !has(&d)) {
+				return true
+			}
+			/* unknown, keep waiting */
+		case <- deadline:
+			return false
+		}
+	}
+}
@@ -0,0 +1,179 @@
+/*
+ * Copyright (c) 2024-2026 SUSE LLC
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, see
+ * <https://www.gnu.org/licenses/>
+ */
+package hypervisor
+
+import (
+	"errors"
+
+	"libvirt.org/go/libvirt"
+	"libvirt.org/go/libvirtxml"
+
+	"suse.com/virtx/pkg/model"
+	"suse.com/virtx/pkg/logger"
+	"suse.com/virtx/pkg/vmdef"
+)
+
+/* attach the disk to the domain, also while running. The storage must be ready */
+func Attach_disk(uuid string, disk *openapi.Disk) error {
+	var (
+		err error
+		conn *libvirt.Connect
+		domain *libvirt.Domain
+		flags libvirt.DomainDeviceModifyFlags
+		active bool
+		xmlstr string
+		xmlstrs []string
+		hd vmdef.HotplugDisk
+		attached []string
+	)
+	conn, err = libvirt.NewConnect(LIBVIRT_URI)
+	if (err != nil) {
+		return err
+	}
+	defer conn.Close()
+	domain, err = conn.LookupDomainByUUIDString(uuid)
+	if (err != nil) {
+		return err
+	}
+	defer domain.Free()
+	flags, active, err = device_modify_flags(domain)
+	if (err != nil) {
+		return err
+	}
+	xmlstr, err = domain.GetXMLDesc(libvirt.DOMAIN_XML_INACTIVE | libvirt.DOMAIN_XML_SECURE)
+	if (err != nil) {
+		return err
+	}
+	xmlstrs = append(xmlstrs, xmlstr)
+	if (active) {
+		xmlstr, err = domain.GetXMLDesc(libvirt.DOMAIN_XML_SECURE)
+		if (err != nil) {
+			return err
+		}
+		xmlstrs = append(xmlstrs, xmlstr)
+	}
+	hd, err = vmdef.Attach_disk_xml(xmlstrs, disk)
+	if (err != nil) {
+		return err
+	}
+	if (hd.Iothread > 0) {
+		err = domain.AddIOThread(hd.Iothread, libvirt.DomainModificationImpact(flags))
+		if (err != nil) {
+			return errors.New("adding iothread: " + err.Error())
+		}
+	}
+	/* the lease first, so that the disk is never attached without it */
+	for _, device := range []string{ hd.Lease, hd.Controller, hd.Disk } {
+		if (device == "") {
+			continue
+		}
+		err = domain.AttachDeviceFlags(device, flags)
+		if (err != nil) {
+			break
+		}
+		attached = append(attached, device)
+	}
+	if (err == nil) {
+		/* the registry must match the domain, otherwise the disk is detached again */
+		err = device_save(domain, uuid)
+		if (err == nil) {
+			logger.Debug("attached disk %s as %s", disk.Path, hd.Target)
+			return nil
+		}
+	}
+	for i := len(attached) - 1; i >= 0; i-- {
+		if (domain.DetachDeviceFlags(attached[i], flags) != nil) {
+			logger.Log("Attach_disk: failed to detach device after error")
+		}
+	}
+	if (hd.Iothread > 0 && domain.DelIOThread(hd.Iothread, libvirt.DomainModificationImpact(flags)) != nil) {
+		logger.Log("Attach_disk: failed to delete iothread %d after error", hd.Iothread)
+	}
+	return err
+}
+
+/*
+ * detach the disk with path from the domain, also while running.
+ * The guest needs to release the disk: if it does not do it in time, the lease is kept
+ * until the domain is powered off, and an error is returned.
+ */
+func Detach_disk(uuid string, path string) error {
+	var (
+		err error
+		conn *libvirt.Connect
+		domain *libvirt.Domain
+		flags libvirt.DomainDeviceModifyFlags
+		active bool
+		xmlstr string
+		hd vmdef.HotplugDisk
+	)
+	conn, err = libvirt.NewConnect(LIBVIRT_URI)
+	if (err != nil) {
+		return err
+	}
+	defer conn.Close()
+	domain, err = conn.LookupDomainByUUIDString(uuid)
+	if (err != nil) {
+		return err
+	}
+	defer domain.Free()
+	flags, active, err = device_modify_flags(domain)
+	if (err != nil) {
+		return err
+	}
+	xmlstr, err = domain.GetXMLDesc(libvirt.DOMAIN_XML_INACTIVE | libvirt.DOMAIN_XML_SECURE)
+	if (err != nil) {
+		return err
+	}
+	hd, err = vmdef.Detach_disk_xml(xmlstr, path)
+	if (err != nil) {
+		return err
+	}
+	err = domain.DetachDeviceFlags(hd.Disk, flags)
+	if (err != nil) {
+		return err
+	}
+	if (active && !device_wait_removed(domain, func(d *libvirtxml.Domain) bool {
+		return disk_has_target(d, hd.Target)
+	})) {
+		_ = device_save(domain, uuid)
+		return errors.New("the guest did not release disk " + hd.Target)
+	}
+	for _, device := range []string{ hd.Controller, hd.Lease } {
+		if (device == "") {
+			continue
+		}
+		err = domain.DetachDeviceFlags(device, flags)
+		if (err != nil) {
+			_ = device_save(domain, uuid)
+			return err
+		}
+	}
+	logger.Debug("detached disk %s from %s", path, hd.Target)
+	return device_save(domain, uuid)
+}
+
+/* check whether the live definition of the domain has the target disk */
+func disk_has_target(d *libvirtxml.Domain, target string) bool {
+	for _, disk := range d.Devices.Disks {
+		if (disk.Target != nil && disk.Target.Dev == target) {
+			return true
+		}
+	}
+	return false
+}
@@ -21,12 +21,14 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
 	"libvirt.org/go/libvirt"
 
 	"suse.com/virtx/pkg/model"
 	"suse.com/virtx/pkg/logger"
 	"suse.com/virtx/pkg/vmreg"
+	"suse.com/virtx/pkg/metadata"
 	"suse.com/virtx/pkg/machine"
 	"suse.com/virtx/pkg/inventory"
 	"suse.com/virtx/pkg/ts"
@@ -116,12 +118,21 @@ out:
 }
 
 func Define_domain(xml string, uuid string) error {
+	return define_domain(LIBVIRT_URI, machine.Uuid(), xml, uuid)
+}
+
+/* as Define_domain, but define the domain in the libvirt of another host */
+func Define_domain_remote(hostname string, host_uuid string, xml string, uuid string) error {
+	return define_domain("qemu+tcp://" + hostname + "/system", host_uuid, xml, uuid)
+}
+
+func define_domain(uri string, host_uuid string, xml string, uuid string) error {
 	var (
 		err error
 		conn *libvirt.Connect
 		domain *libvirt.Domain
 	)
-	conn, err = libvirt.NewConnect(LIBVIRT_URI)
+	conn, err = libvirt.NewConnect(uri)
 	if (err != nil) {
 		return err
 	}
@@ -131,15 +142,89 @@ func Define_domain(xml string, uuid string) error {
 		return err
 	}
 	defer domain.Free()
-	xml, err = domain.GetXMLDesc(libvirt.DOMAIN_XML_INACTIVE)
+	xml, err = domain.GetXMLDesc(libvirt.DOMAIN_XML_INACTIVE | libvirt.DOMAIN_XML_SECURE)
 	if (err != nil) {
 		return err
 	}
 	/* store the processed XML in /vms/xml/host-uuid/vm-uuid.xml */
+	err = vmreg.Save(host_uuid, uuid, xml)
+	if (err != nil) {
+		logger.Log("Define_domain: failed to vmreg.Save(%s, %s)", host_uuid, uuid)
+	}
+	return nil
+}
+
+/*
+ * change the name (title and description) and the custom fields of the domain,
+ * also while it is running, without redefining it.
+ * The registry and the inventory of all hosts are updated immediately.
+ */
+func Set_metadata(uuid string, name string, custom []openapi.CustomField) error {
+	var (
+		err error
+		conn *libvirt.Connect
+		domain *libvirt.Domain
+		active bool
+		impact libvirt.DomainModificationImpact = libvirt.DOMAIN_AFFECT_CONFIG
+		meta metadata.Vm
+		meta_xml, xml string
+		ve inventory.VmEvent
+	)
+	meta_xml, err = meta.To_xml(custom)
+	if (err != nil) {
+		return err
+	}
+	conn, err = libvirt.NewConnect(LIBVIRT_URI)
+	if (err != nil) {
+		return err
+	}
+	defer conn.Close()
+	domain, err = conn.LookupDomainByUUIDString(uuid)
+	if (err != nil) {
+		return err
+	}
+	defer domain.Free()
+	active, err = domain.IsActive()
+	if (err != nil) {
+		return err
+	}
+	if (active) {
+		impact |= libvirt.DOMAIN_AFFECT_LIVE
+	}
+	err = domain.SetMetadata(libvirt.DOMAIN_METADATA_TITLE, name, "", "", impact)
+	if (err != nil) {
+		return err
+	}
+	err = domain.SetMetadata(libvirt.DOMAIN_METADATA_DESCRIPTION, name, "", "", impact)
+	if (err != nil) {
+		return err
+	}
+	err = domain.SetMetadata(libvirt.DOMAIN_METADATA_ELEMENT, meta_xml, meta.XMLNS, meta.XMLName.Space, impact)
+	if (err != nil) {
+		return err
+	}
+	xml, err = domain.GetXMLDesc(libvirt.DOMAIN_XML_INACTIVE | libvirt.DOMAIN_XML_SECURE)
+	if (err != nil) {
+		return err
+	}
 	err = vmreg.Save(machine.Uuid(), uuid, xml)
 	if (err != nil) {
-		logger.Log("Define_domain: failed to vmreg.Save(%s, %s)", machine.Uuid(), uuid)
+		logger.Log("Set_metadata: failed to vmreg.Save(%s, %s)", machine.Uuid(), uuid)
+		return errors.New("failed to save the VM definition: " + err.Error())
 	}
+	/* no lifecycle event is generated for metadata changes, so send the VmInfo now */
+	hv.m.RLock()
+	defer hv.m.RUnlock()
+	if (hv.system_info_ch == nil) {
+		return nil
+	}
+	ve, name, err = get_domain_info(domain)
+	if (err != nil) {
+		logger.Log("Set_metadata: get_domain_info failed: %s", err.Error())
+		return nil
+	}
+	ve.Ts = ts.Now()
+	send_domain_info(domain, ve, name)
 	return nil
 }
 
@@ -356,7 +441,8 @@ func Dumpxml(uuid string) (string, error) {
 		return "", err
 	}
 	defer domain.Free()
-	xml, err = domain.GetXMLDesc(0)
+	/* include the VNC password, so that redefining the domain from this XML keeps it */
+	xml, err = domain.GetXMLDesc(libvirt.DOMAIN_XML_SECURE)
 	if (err != nil) {
 		return "", err
 	}
@@ -452,7 +538,15 @@ func Resume_domain(uuid string) error {
 	return nil
 }
 
-func Shutdown_domain(uuid string, force int16) error {
+/* the shutdown flags for a graceful shutdown, with ACPI or with the guest agent */
+func shutdown_flags(agent bool) libvirt.DomainShutdownFlags {
+	if (agent) {
+		return libvirt.DOMAIN_SHUTDOWN_GUEST_AGENT
+	}
+	return libvirt.DOMAIN_SHUTDOWN_ACPI_POWER_BTN
+}
+
+func Shutdown_domain(uuid string, force int16, agent bool) error {
 	var (
 		err error
 		conn *libvirt.Connect
@@ -470,10 +564,10 @@ func Shutdown_domain(uuid string, force int16) error {
 	}
 	defer domain.Free()
 	started := ts.Now()
-	msg := fmt.Sprintf("shutdown force=%d.", force)
+	msg := fmt.Sprintf("shutdown force=%d agent=%t.", force, agent)
 	_ = oplog_record(domain, op, openapi.OPERATION_STARTED, msg, started, 0)
 	if (force == 0) {
-		err = domain.Shutdown()
+		err = domain.ShutdownFlags(shutdown_flags(agent))
 	} else if (force == 1) {
 		err = domain.DestroyFlags(libvirt.DOMAIN_DESTROY_GRACEFUL)
 	} else {
@@ -550,3 +644,128 @@ func Log_domain(uuid string, list *openapi.OplogList) error {
 	err = oplog_load_list(domain, list)
 	return err
 }
+
+/*
+ * shutdown the domain with ACPI, and if it is still running after timeout seconds,
+ * escalate to SIGTERM and then to SIGKILL. Returns the method that powered off the domain.
+ */
+func Shutdown_domain_timeout(uuid string, timeout int32, agent bool) (string, error) {
+	var (
+		err error
+		conn *libvirt.Connect
+		domain *libvirt.Domain
+		op openapi.Operation = openapi.OpVmShutdown
+		method string = "ACPI"
+	)
+	if (agent) {
+		method = "guest agent"
+	}
+	conn, err = libvirt.NewConnect(LIBVIRT_URI)
+	if (err != nil) {
+		return "", err
+	}
+	defer conn.Close()
+	domain, err = conn.LookupDomainByUUIDString(uuid)
+	if (err != nil) {
+		return "", err
+	}
+	defer domain.Free()
+	started := ts.Now()
+	msg := fmt.Sprintf("shutdown force=0 agent=%t timeout=%d.", agent, timeout)
+	_ = oplog_record(domain, op, openapi.OPERATION_STARTED, msg, started, 0)
+	err = domain.ShutdownFlags(shutdown_flags(agent))
+	if (err != nil) {
+		_ = oplog_record(domain, op, openapi.OPERATION_FAILED, msg + " " + err.Error(), started, ts.Now())
+		return "", err
+	}
+	if (shutdown_wait(domain, time.Duration(timeout) * time.Second)) {
+		return method, nil
+	}
+	/* the guest ignored the request, record the escalation before the lifecycle event completes the op */
+	msg += " timeout, escalating to SIGTERM."
+	_ = oplog_record(domain, op, openapi.OPERATION_STARTED, msg, started, 0)
+	err = domain.DestroyFlags(libvirt.DOMAIN_DESTROY_GRACEFUL)
+	if (err == nil) {
+		return "SIGTERM", nil
+	}
+	/* the guest may have powered off by itself right after the timeout */
+	if (shutdown_done(domain)) {
+		return method, nil
+	}
+	msg += " " + err.Error() + ", escalating to SIGKILL."
+	_ = oplog_record(domain, op, openapi.OPERATION_STARTED, msg, started, 0)
+	err = domain.DestroyFlags(0)
+	if (err == nil) {
+		return "SIGKILL", nil
+	}
+	if (shutdown_done(domain)) {
+		return "SIGTERM", nil
+	}
+	_ = oplog_record(domain, op, openapi.OPERATION_FAILED, msg + " " + err.Error(), started, ts.Now())
+	return "", err
+}
+
+/* check whether the domain is shut off */
+func shutdown_done(domain *libvirt.Domain) bool {
+	var (
+		err error
+		state libvirt.DomainState
+	)
+	state, _, err = domain.GetState()
+	return err == nil && (state == libvirt.DOMAIN_SHUTOFF || state == libvirt.DOMAIN_CRASHED)
+}
+
+/* wait until the domain is shut off, returns false on timeout */
+func shutdown_wait(domain *libvirt.Domain, timeout time.Duration) bool {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	deadline := time.After(timeout)
+	for {
+		select {
+		case <- ticker.C:
+			if (shutdown_done(domain)) {
+				return true
+			}
+		case <- deadline:
+			/* last check, the guest may have completed the shutdown since the last tick */
+			return shutdown_done(domain)
+		}
+	}
+}
+
+func Reboot_domain(uuid string, force bool, agent bool) error {
+	var (
+		err error
+		conn *libvirt.Connect
+		domain *libvirt.Domain
+		op openapi.Operation = openapi.OpVmReboot
+	)
+	conn, err = libvirt.NewConnect(LIBVIRT_URI)
+	if (err != nil) {
+		return err
+	}
+	defer conn.Close()
+	domain, err = conn.LookupDomainByUUIDString(uuid)
+	if (err != nil) {
+		return err
+	}
+	defer domain.Free()
+	started := ts.Now()
+	msg := fmt.Sprintf("reboot force=%t agent=%t.", force, agent)
+	_ = oplog_record(domain, op, openapi.OPERATION_STARTED, msg, started, 0)
+	if (force) {
+		err = domain.Reset(0)
+	} else if (agent) {
+		err = domain.Reboot(libvirt.DOMAIN_REBOOT_GUEST_AGENT)
+	} else {
+		err = domain.Reboot(libvirt.DOMAIN_REBOOT_ACPI_POWER_BTN)
+	}
+	if (err != nil) {
+		_ = oplog_record(domain, op, openapi.OPERATION_FAILED, msg + " " + err.Error(), started, ts.Now())
+	} else if (force) {
+		_ = oplog_record(domain, op, openapi.OPERATION_COMPLETED, msg, started, ts.Now())
+	} else {
+		/* we will wait for the reboot event to set the operation to completed */
+	}
+	return err
+}
@@ -28,11 +28,13 @@ import (
 	"strconv"
 	"fmt"
 	"errors"
+	"sort"
 
 	"libvirt.org/go/libvirt"
 
 	"suse.com/virtx/pkg/model"
 	"suse.com/virtx/pkg/logger"
+	"suse.com/virtx/pkg/metrics"
 	"suse.com/virtx/pkg/vmreg"
 	"suse.com/virtx/pkg/machine"
 	"suse.com/virtx/pkg/inventory"
@@ -48,6 +50,8 @@ const (
 	LIBVIRT_RECONNECT_SECONDS = 5
 	SYSTEM_INFO_LOOP_SECONDS = 15
 	WAIT_SYSTEM_INFO_SECONDS = 10
+	DEVICE_DETACH_SECONDS = 30
+	SNAPSHOT_COMMIT_SECONDS = 3600
 )
 
 type Hypervisor struct {
@@ -56,6 +60,7 @@ type Hypervisor struct {
 
 	conn *libvirt.Connect
 	lifecycle_id int
+	reboot_id int
 	vm_event_ch chan inventory.VmEvent
 	system_info_ch chan SystemInfo
 	system_info_loop_done bool
@@ -66,6 +71,7 @@ type Hypervisor struct {
 var hv = Hypervisor{
 	m: sync.RWMutex{},
 	lifecycle_id: -1,
+	reboot_id: -1,
 }
 
 /*
@@ -91,6 +97,11 @@ func Connect() error {
 	return err
 }
 
+/* report whether the libvirt connection is currently up */
+func Is_connected() bool {
+	return hv.is_connected.Load()
+}
+
 /*
  * the system_info_loop is going to initialize important information,
  * including the host / libvirt Uuid and Cpuarch.
@@ -131,6 +142,29 @@ func Shutdown() {
 	logger.Debug("shutdown complete.")
 }
 
+/*
+ * send the VmInfo of a single domain to the cluster without waiting for the next
+ * system info loop, f.e. after it has been (re)defined. Must be called with hv.m held.
+ */
+func send_domain_info(d *libvirt.Domain, ve inventory.VmEvent, name string) {
+	var (
+		err error
+		si SystemInfo
+		vm SystemInfoVm
+	)
+	si.Host.Uuid = "" /* not necessary, but for documentation, do not send Host Data */
+	si.Vms = make(SystemInfoVms)
+	vm.VmInfo.VmEvent = ve
+	vm.Name = name
+	err = get_domain_stats(d, &vm, nil, &si.imm)
+	if (err != nil) {
+		logger.Log("send_domain_info: failed to get_domain_stats for uuid %s", ve.Uuid)
+		return
+	}
+	si.Vms[vm.Uuid] = vm
+	hv.system_info_ch <- si
+}
+
 func lifecycle_cb(_ *libvirt.Connect, d *libvirt.Domain, e *libvirt.DomainEventLifecycle) {
 	/* e.Detail: see all DomainEvent*DetailType types */
 	var (
@@ -179,21 +213,7 @@ func lifecycle_cb(_ *libvirt.Connect, d *libvirt.Domain, e *libvirt.DomainEventL
 		logger.Log("lifecycle_cb: event %d: %s:", e.Event, err.Error())
 	}
 	if (e.Event == libvirt.DOMAIN_EVENT_DEFINED) {
-		var (
-			si SystemInfo
-			vm SystemInfoVm
-		)
-		si.Host.Uuid = "" /* not necessary, but for documentation, do not send Host Data */
-		si.Vms = make(SystemInfoVms)
-		vm.VmInfo.VmEvent = ve
-		vm.Name = name
-		err = get_domain_stats(d, &vm, nil, &si.imm)
-		if (err != nil) {
-			logger.Log("lifecycle_cb: failed to get_domain_stats for uuid %s", ve.Uuid)
-		} else {
-			si.Vms[vm.Uuid] = vm
-			hv.system_info_ch <- si
-		}
+		send_domain_info(d, ve, name)
 	} else if (ve.Runstate != openapi.RUNSTATE_NONE) {
 		logger.Debug("[VmEvent] %s/%s: %v state: %d", name, ve.Uuid, e, ve.Runstate)
 		hv.vm_event_ch <- ve
@@ -231,6 +251,14 @@ func lifecycle_cb(_ *libvirt.Connect, d *libvirt.Domain, e *libvirt.DomainEventL
 	}
 }
 
+/*
+ * the guest has rebooted or the VM has been reset. The domain stays running,
+ * so there is no lifecycle event and the cloud-init disk is kept.
+ */
+func reboot_cb(_ *libvirt.Connect, d *libvirt.Domain) {
+	_ = oplog_complete(d, openapi.OpVmReboot, "guest rebooted")
+}
+
 /*
  * Start listening for domain events and collecting system information.
  * Sets the lifecycle_id, vm_event_ch and system_info_ch fields of the Hypervisor struct.
@@ -243,6 +271,12 @@ func start_listening() error {
 	if (err != nil) {
 		return err
 	}
+	hv.reboot_id, err = hv.conn.DomainEventRebootRegister(nil, reboot_cb)
+	if (err != nil) {
+		_ = hv.conn.DomainEventDeregister(hv.lifecycle_id)
+		hv.lifecycle_id = -1
+		return err
+	}
 	return nil
 }
 
@@ -254,6 +288,10 @@ func stop_listening() {
 	}
 	_ = hv.conn.DomainEventDeregister(hv.lifecycle_id)
 	hv.lifecycle_id = -1
+	if (hv.reboot_id >= 0) {
+		_ = hv.conn.DomainEventDeregister(hv.reboot_id)
+		hv.reboot_id = -1
+	}
 }
 
 /* Return the libvirt domain Events Channel */
@@ -291,6 +329,7 @@ func init_system_info_loop() {
 		for ; err != nil; err = Connect() {
 			time.Sleep(time.Duration(LIBVIRT_RECONNECT_SECONDS) * time.Second)
 		}
+		metrics.Inc(metrics.LIBVIRT_RECONNECTS)
 	}
 }
 
@@ -444,6 +483,31 @@ func Get_Vmstats(uuid string) (openapi.Vmstats, error) {
 	return stats, nil
 }
 
+/* VmStats: the VM information together with the statistics collected on this host */
+type VmStats struct {
+	inventory.VmInfo
+	Stats openapi.Vmstats
+}
+
+/* Return the information and statistics of all the VMs on this host, sorted by uuid */
+func Get_vms_stats() []VmStats {
+	hv.m.RLock()
+	defer hv.m.RUnlock()
+	var (
+		list []VmStats
+	)
+	if (hv.si == nil) {
+		return list
+	}
+	for _, vm := range hv.si.Vms {
+		list = append(list, VmStats{ VmInfo: vm.VmInfo, Stats: vm.stats })
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Uuid < list[j].Uuid
+	})
+	return list
+}
+
 func Get_host() openapi.Host {
 	hv.m.RLock()
 	defer hv.m.RUnlock()
@@ -0,0 +1,149 @@
+/*
+ * Copyright (c) 2024-2026 SUSE LLC
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, see
+ * <https://www.gnu.org/licenses/>
+ */
+package hypervisor
+
+import (
+	"errors"
+	"strings"
+
+	"libvirt.org/go/libvirt"
+	"libvirt.org/go/libvirtxml"
+
+	"suse.com/virtx/pkg/model"
+	"suse.com/virtx/pkg/logger"
+	"suse.com/virtx/pkg/vmdef"
+)
+
+/*
+ * attach the net to the domain, also while running, with the VLAN of the VM.
+ * If net has no Mac, the generated one is set.
+ */
+func Attach_net(uuid string, net *openapi.Net, vlanid int16) error {
+	var (
+		err error
+		conn *libvirt.Connect
+		domain *libvirt.Domain
+		flags libvirt.DomainDeviceModifyFlags
+		active bool
+		xmlstr string
+		xmlstrs []string
+	)
+	conn, err = libvirt.NewConnect(LIBVIRT_URI)
+	if (err != nil) {
+		return err
+	}
+	defer conn.Close()
+	domain, err = conn.LookupDomainByUUIDString(uuid)
+	if (err != nil) {
+		return err
+	}
+	defer domain.Free()
+	flags, active, err = device_modify_flags(domain)
+	if (err != nil) {
+		return err
+	}
+	xmlstr, err = domain.GetXMLDesc(libvirt.DOMAIN_XML_INACTIVE | libvirt.DOMAIN_XML_SECURE)
+	if (err != nil) {
+		return err
+	}
+	xmlstrs = append(xmlstrs, xmlstr)
+	if (active) {
+		xmlstr, err = domain.GetXMLDesc(libvirt.DOMAIN_XML_SECURE)
+		if (err != nil) {
+			return err
+		}
+		xmlstrs = append(xmlstrs, xmlstr)
+	}
+	xmlstr, err = vmdef.Attach_net_xml(xmlstrs, net, vlanid)
+	if (err != nil) {
+		return err
+	}
+	err = domain.AttachDeviceFlags(xmlstr, flags)
+	if (err != nil) {
+		return err
+	}
+	/* the registry must match the domain, otherwise the net is detached again */
+	err = device_save(domain, uuid)
+	if (err != nil) {
+		if (domain.DetachDeviceFlags(xmlstr, flags) != nil) {
+			logger.Log("Attach_net: failed to detach device after error")
+		}
+		return err
+	}
+	logger.Debug("attached net %s with mac %s", net.Name, net.Mac)
+	return nil
+}
+
+/*
+ * detach the net with mac from the domain, also while running.
+ * The guest needs to release the interface: if it does not do it in time, an error is returned.
+ */
+func Detach_net(uuid string, mac string) error {
+	var (
+		err error
+		conn *libvirt.Connect
+		domain *libvirt.Domain
+		flags libvirt.DomainDeviceModifyFlags
+		active bool
+		xmlstr string
+	)
+	conn, err = libvirt.NewConnect(LIBVIRT_URI)
+	if (err != nil) {
+		return err
+	}
+	defer conn.Close()
+	domain, err = conn.LookupDomainByUUIDString(uuid)
+	if (err != nil) {
+		return err
+	}
+	defer domain.Free()
+	flags, active, err = device_modify_flags(domain)
+	if (err != nil) {
+		return err
+	}
+	xmlstr, err = domain.GetXMLDesc(libvirt.DOMAIN_XML_INACTIVE | libvirt.DOMAIN_XML_SECURE)
+	if (err != nil) {
+		return err
+	}
+	xmlstr, err = vmdef.Detach_net_xml(xmlstr, mac)
+	if (err != nil) {
+		return err
+	}
+	err = domain.DetachDeviceFlags(xmlstr, flags)
+	if (err != nil) {
+		return err
+	}
+	if (active && !device_wait_removed(domain, func(d *libvirtxml.Domain) bool {
+		return net_has_mac(d, mac)
+	})) {
+		_ = device_save(domain, uuid)
+		return errors.New("the guest did not release net " + mac)
+	}
+	logger.Debug("detached net %s", mac)
+	return device_save(domain, uuid)
+}
+
+/* check whether the live definition of the domain has the interface with mac */
+func net_has_mac(d *libvirtxml.Domain, mac string) bool {
+	for _, i := range d.Devices.Interfaces {
+		if (i.MAC != nil && strings.EqualFold(i.MAC.Address, mac)) {
+			return true
+		}
+	}
+	return false
+}
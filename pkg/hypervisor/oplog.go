@@ -67,7 +67,10 @@ func oplog_load(domain *libvirt.Domain, op openapi.Operation, state *openapi.Ope
 	return nil
 }
 
-/* record completion of a long running op when it finally completes */
+/*
+ * record completion of a long running op when it finally completes.
+ * msg is appended to the message recorded when the op was started.
+ */
 func oplog_complete(domain *libvirt.Domain, op openapi.Operation, msg string) error {
 	var (
 		state openapi.OperationState
@@ -82,6 +85,9 @@ func oplog_complete(domain *libvirt.Domain, op openapi.Operation, msg string) er
 	if (state != openapi.OPERATION_STARTED) {
 		return errors.New("operation is not in state: started")
 	}
+	if (oldmsg != "") {
+		msg = oldmsg + " " + msg
+	}
 	return oplog_record(domain, op, openapi.OPERATION_COMPLETED, msg, started, ts.Now())
 }
 
@@ -89,7 +95,8 @@ func oplog_complete(domain *libvirt.Domain, op openapi.Operation, msg string) er
 func oplog_load_list(domain *libvirt.Domain, list *openapi.OplogList) error {
 	var (
 		err error
-		ops = [...]openapi.Operation{ openapi.OpVmBoot, openapi.OpVmMigrate, openapi.OpVmPause, openapi.OpVmResume, openapi.OpVmShutdown }
+		ops = [...]openapi.Operation{ openapi.OpVmBoot, openapi.OpVmMigrate, openapi.OpVmPause, openapi.OpVmReboot, openapi.OpVmResume, openapi.OpVmShutdown,
+			openapi.OpVmSnapshotCreate, openapi.OpVmSnapshotDelete, openapi.OpVmSnapshotRevert }
 	)
 	list.Items = make([]openapi.OplogItem, 0, len(ops))
 	for i := 0; i < len(ops); i++ {
@@ -0,0 +1,115 @@
+/*
+ * Copyright (c) 2024-2026 SUSE LLC
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, see
+ * <https://www.gnu.org/licenses/>
+ */
+package hypervisor
+
+import (
+	"errors"
+	"fmt"
+
+	"libvirt.org/go/libvirt"
+
+	"suse.com/virtx/pkg/logger"
+	"suse.com/virtx/pkg/vmdef"
+)
+
+/*
+ * check that the host resources available for VMs (Hostresources.Availablevms)
+ * can accommodate vcpus more vcpus and memory more MiB, hugepages if hp is set.
+ */
+func Check_resources(vcpus int, memory int32, hp bool) error {
+	hv.m.RLock()
+	defer hv.m.RUnlock()
+	if (hv.si == nil) {
+		return errors.New("no host resources information")
+	}
+	res := &hv.si.Host.res
+	if (memory > 0) {
+		var available int32 = res.Memory.Availablevms
+		if (hp) {
+			available = res.Hp.Availablevms
+		}
+		if (memory > available) {
+			return fmt.Errorf("not enough memory available: %d MiB requested, %d MiB available", memory, available)
+		}
+	}
+	if (vcpus > 0) {
+		/* reserved the same way as system_info_get does for the vcpus of the domains */
+		mhz := int32(float64(uint(vcpus) * hv.si.imm.info.MHz) / 100.0 * hv.vcpu_load_factor)
+		if (mhz > res.Cpu.Availablevms) {
+			return fmt.Errorf("not enough cpu available: %d MHz requested, %d MHz available", mhz, res.Cpu.Availablevms)
+		}
+	}
+	return nil
+}
+
+/*
+ * change the number of online vcpus and the total memory in MiB of the domain,
+ * also while running. A value of 0 leaves it unchanged.
+ */
+func Resize_domain(uuid string, vcpus uint, memory int32) error {
+	var (
+		err error
+		conn *libvirt.Connect
+		domain *libvirt.Domain
+		flags libvirt.DomainDeviceModifyFlags
+		active bool
+		xmlstr string
+	)
+	conn, err = libvirt.NewConnect(LIBVIRT_URI)
+	if (err != nil) {
+		return err
+	}
+	defer conn.Close()
+	domain, err = conn.LookupDomainByUUIDString(uuid)
+	if (err != nil) {
+		return err
+	}
+	defer domain.Free()
+	flags, active, err = device_modify_flags(domain)
+	if (err != nil) {
+		return err
+	}
+	if (memory > 0) {
+		xmlstr, err = domain.GetXMLDesc(libvirt.DOMAIN_XML_INACTIVE | libvirt.DOMAIN_XML_SECURE)
+		if (err != nil) {
+			return err
+		}
+		xmlstr, err = vmdef.Memory_resize_xml(xmlstr, memory)
+		if (err != nil) {
+			return err
+		}
+		err = domain.UpdateDeviceFlags(xmlstr, flags)
+		if (err != nil) {
+			return errors.New("resizing memory: " + err.Error())
+		}
+		logger.Debug("resized memory of %s to %d MiB", uuid, memory)
+	}
+	if (vcpus > 0) {
+		var vcpu_flags libvirt.DomainVcpuFlags = libvirt.DOMAIN_VCPU_CONFIG
+		if (active) {
+			vcpu_flags |= libvirt.DOMAIN_VCPU_LIVE
+		}
+		err = domain.SetVcpusFlags(vcpus, vcpu_flags)
+		if (err != nil) {
+			_ = device_save(domain, uuid)
+			return errors.New("setting vcpus: " + err.Error())
+		}
+		logger.Debug("set %d vcpus for %s", vcpus, uuid)
+	}
+	return device_save(domain, uuid)
+}
@@ -0,0 +1,105 @@
+/*
+ * Copyright (c) 2024-2026 SUSE LLC
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, see
+ * <https://www.gnu.org/licenses/>
+ */
+package hypervisor
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/png"
+	"io"
+
+	"libvirt.org/go/libvirt"
+
+	"suse.com/virtx/pkg/encoding/ppm"
+)
+
+const (
+	SCREENSHOT_MAX = 256 * 1024 * 1024 /* limit of the image received from libvirt */
+)
+
+/* take a screenshot of the primary display of the running domain, returned as PNG */
+func Screenshot(uuid string) ([]byte, error) {
+	var (
+		err error
+		conn *libvirt.Connect
+		domain *libvirt.Domain
+		stream *libvirt.Stream
+		mime string
+		data bytes.Buffer
+		img image.Image
+		buf bytes.Buffer
+	)
+	conn, err = libvirt.NewConnect(LIBVIRT_URI)
+	if (err != nil) {
+		return nil, err
+	}
+	defer conn.Close()
+	domain, err = conn.LookupDomainByUUIDString(uuid)
+	if (err != nil) {
+		return nil, err
+	}
+	defer domain.Free()
+	stream, err = conn.NewStream(0)
+	if (err != nil) {
+		return nil, err
+	}
+	defer stream.Free()
+	mime, err = domain.Screenshot(stream, 0, 0)
+	if (err != nil) {
+		return nil, err
+	}
+	_, err = io.Copy(&data, io.LimitReader(screenshot_reader{ stream }, SCREENSHOT_MAX + 1))
+	if (err != nil) {
+		_ = stream.Abort()
+		return nil, err
+	}
+	if (data.Len() > SCREENSHOT_MAX) {
+		_ = stream.Abort()
+		return nil, errors.New("screenshot too large")
+	}
+	err = stream.Finish()
+	if (err != nil) {
+		return nil, err
+	}
+	switch (mime) {
+	case "image/png":
+		return data.Bytes(), nil
+	case ppm.CONTENT_TYPE:
+		img, err = ppm.Decode(&data)
+		if (err != nil) {
+			return nil, err
+		}
+	default:
+		return nil, errors.New("unsupported screenshot format " + mime)
+	}
+	err = png.Encode(&buf, img)
+	if (err != nil) {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+/* adapt the stream to an io.Reader */
+type screenshot_reader struct {
+	stream *libvirt.Stream
+}
+
+func (r screenshot_reader) Read(p []byte) (int, error) {
+	return r.stream.Recv(p)
+}
@@ -0,0 +1,568 @@
+/*
+ * Copyright (c) 2024-2026 SUSE LLC
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, see
+ * <https://www.gnu.org/licenses/>
+ */
+package hypervisor
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"libvirt.org/go/libvirt"
+	"libvirt.org/go/libvirtxml"
+
+	"suse.com/virtx/pkg/model"
+	"suse.com/virtx/pkg/logger"
+	"suse.com/virtx/pkg/vmreg"
+	"suse.com/virtx/pkg/vmdef"
+	"suse.com/virtx/pkg/storage"
+	"suse.com/virtx/pkg/metadata"
+	"suse.com/virtx/pkg/machine"
+	"suse.com/virtx/pkg/ts"
+	. "suse.com/virtx/pkg/constants"
+)
+
+/*
+ * External disk-only snapshots of the managed disks (see vmdef/snapshot.go for the layout).
+ *
+ * Libvirt snapshot metadata would prevent migrating the domain, so snapshots are created
+ * without it, and the snapshot list is kept in the domain XML metadata instead,
+ * which migrates together with the domain and is stored in the registry.
+ */
+
+/* load the snapshot list from the domain XML, a missing list is empty */
+func snapshot_load(domain *libvirt.Domain, list *[]openapi.Snapshot) error {
+	var (
+		err error
+		xmlstr string
+		meta metadata.Snapshots
+		libvirt_err libvirt.Error
+	)
+	*list = []openapi.Snapshot{}
+	xmlstr, err = domain.GetMetadata(libvirt.DOMAIN_METADATA_ELEMENT, "virtx-snap", libvirt.DOMAIN_AFFECT_CONFIG)
+	if (err != nil) {
+		if (errors.As(err, &libvirt_err) && libvirt_err.Code == libvirt.ERR_NO_DOMAIN_METADATA) {
+			return nil
+		}
+		return err
+	}
+	return meta.From_xml(xmlstr, list)
+}
+
+/* store the snapshot list into the domain XML, and the domain XML into the registry */
+func snapshot_save(domain *libvirt.Domain, uuid string, list []openapi.Snapshot) error {
+	var (
+		err error
+		xmlstr string
+		meta metadata.Snapshots
+		active bool
+		impact libvirt.DomainModificationImpact = libvirt.DOMAIN_AFFECT_CONFIG
+	)
+	xmlstr, err = meta.To_xml(list)
+	if (err != nil) {
+		return err
+	}
+	active, err = domain.IsActive()
+	if (err != nil) {
+		return err
+	}
+	if (active) {
+		impact |= libvirt.DOMAIN_AFFECT_LIVE
+	}
+	err = domain.SetMetadata(libvirt.DOMAIN_METADATA_ELEMENT, xmlstr, meta.XMLName.Local, meta.XMLName.Space, impact)
+	if (err != nil) {
+		return err
+	}
+	xmlstr, err = domain.GetXMLDesc(libvirt.DOMAIN_XML_INACTIVE | libvirt.DOMAIN_XML_SECURE)
+	if (err != nil) {
+		return err
+	}
+	err = vmreg.Save(machine.Uuid(), uuid, xmlstr)
+	if (err != nil) {
+		logger.Log("snapshot_save: failed to vmreg.Save(%s, %s)", machine.Uuid(), uuid)
+	}
+	return nil
+}
+
+/* find the snapshot name in the list, or return -1 */
+func snapshot_find(list []openapi.Snapshot, name string) int {
+	for i := range list {
+		if (list[i].Name == name) {
+			return i
+		}
+	}
+	return -1
+}
+
+/* the file at position i of the snapshot chain of root: the root itself for 0, then the overlays */
+func snapshot_chain_file(list []openapi.Snapshot, root string, i int) string {
+	if (i == 0) {
+		return root
+	}
+	return vmdef.Snapshot_path(root, list[i - 1].Name)
+}
+
+/* the snapshot chain of root from the file at position i down to the root itself */
+func snapshot_chain(list []openapi.Snapshot, root string, i int) []string {
+	var chain []string
+	for ; i >= 0; i-- {
+		chain = append(chain, snapshot_chain_file(list, root, i))
+	}
+	return chain
+}
+
+/*
+ * get the domain XML and the disks taking part in snapshots,
+ * checking that they are all at the latest snapshot of the list.
+ */
+func snapshot_disks(domain *libvirt.Domain, list []openapi.Snapshot, active bool) (string, []vmdef.SnapshotDisk, []string, error) {
+	var (
+		err error
+		xmlstr string
+		flags libvirt.DomainXMLFlags = libvirt.DOMAIN_XML_SECURE
+		disks []vmdef.SnapshotDisk
+		excluded []string
+	)
+	if (!active) {
+		flags |= libvirt.DOMAIN_XML_INACTIVE
+	}
+	xmlstr, err = domain.GetXMLDesc(flags)
+	if (err != nil) {
+		return "", nil, nil, err
+	}
+	disks, excluded, err = vmdef.Snapshot_disks(xmlstr)
+	if (err != nil) {
+		return "", nil, nil, err
+	}
+	for _, disk := range disks {
+		if (disk.Active != snapshot_chain_file(list, disk.Root, len(list))) {
+			return "", nil, nil, errors.New("disk " + disk.Target + " is not at the latest snapshot")
+		}
+	}
+	return xmlstr, disks, excluded, nil
+}
+
+/* redefine the powered off domain after changing the disk sources */
+func snapshot_define(conn *libvirt.Connect, xmlstr string) error {
+	var (
+		err error
+		domain *libvirt.Domain
+	)
+	domain, err = conn.DomainDefineXML(xmlstr)
+	if (err != nil) {
+		return err
+	}
+	domain.Free()
+	return nil
+}
+
+func Snapshot_list(uuid string, list *openapi.SnapshotList) error {
+	var (
+		err error
+		conn *libvirt.Connect
+		domain *libvirt.Domain
+	)
+	conn, err = libvirt.NewConnect(LIBVIRT_URI)
+	if (err != nil) {
+		return err
+	}
+	defer conn.Close()
+	domain, err = conn.LookupDomainByUUIDString(uuid)
+	if (err != nil) {
+		return err
+	}
+	defer domain.Free()
+	return snapshot_load(domain, &list.Items)
+}
+
+func Create_snapshot(uuid string, o *openapi.SnapshotCreateOptions) error {
+	var (
+		err error
+		conn *libvirt.Connect
+		domain *libvirt.Domain
+		op openapi.Operation = openapi.OpVmSnapshotCreate
+		msg string = "snapshot " + o.Name
+	)
+	conn, err = libvirt.NewConnect(LIBVIRT_URI)
+	if (err != nil) {
+		return err
+	}
+	defer conn.Close()
+	domain, err = conn.LookupDomainByUUIDString(uuid)
+	if (err != nil) {
+		return err
+	}
+	defer domain.Free()
+	started := ts.Now()
+	_ = oplog_record(domain, op, openapi.OPERATION_STARTED, msg, started, 0)
+
+	err = snapshot_create(conn, domain, uuid, o)
+	if (err != nil) {
+		_ = oplog_record(domain, op, openapi.OPERATION_FAILED, msg + " " + err.Error(), started, ts.Now())
+		return err
+	}
+	_ = oplog_record(domain, op, openapi.OPERATION_COMPLETED, msg, started, ts.Now())
+	return nil
+}
+
+func snapshot_create(conn *libvirt.Connect, domain *libvirt.Domain, uuid string, o *openapi.SnapshotCreateOptions) error {
+	var (
+		err error
+		list []openapi.Snapshot
+		active bool
+		xmlstr string
+		disks []vmdef.SnapshotDisk
+		excluded []string
+	)
+	err = snapshot_load(domain, &list)
+	if (err != nil) {
+		return err
+	}
+	if (snapshot_find(list, o.Name) >= 0) {
+		return errors.New("snapshot already exists")
+	}
+	if (len(list) >= SNAPSHOTS_MAX) {
+		return errors.New("too many snapshots")
+	}
+	active, err = domain.IsActive()
+	if (err != nil) {
+		return err
+	}
+	xmlstr, disks, excluded, err = snapshot_disks(domain, list, active)
+	if (err != nil) {
+		return err
+	}
+	snapshot := openapi.Snapshot{
+		Name: o.Name,
+		Description: o.Description,
+		Ts: ts.Now(),
+		Live: active,
+	}
+	if (active) {
+		/* the running domain holds the disk leases, and libvirt creates the overlays */
+		err = snapshot_create_live(domain, disks, excluded, o)
+		if (err != nil) {
+			return err
+		}
+	} else {
+		err = storage.Snapshot_create(disks, o.Name, uuid)
+		if (err != nil) {
+			return err
+		}
+		for _, disk := range disks {
+			chain := append([]string{ vmdef.Snapshot_path(disk.Root, o.Name) }, snapshot_chain(list, disk.Root, len(list))...)
+			xmlstr, err = vmdef.Set_disk_source(xmlstr, disk.Target, chain)
+			if (err != nil) {
+				return err
+			}
+		}
+		err = snapshot_define(conn, xmlstr)
+		if (err != nil) {
+			return err
+		}
+	}
+	return snapshot_save(domain, uuid, append(list, snapshot))
+}
+
+func snapshot_create_live(domain *libvirt.Domain, disks []vmdef.SnapshotDisk, excluded []string, o *openapi.SnapshotCreateOptions) error {
+	var (
+		err error
+		xmlstr string
+		snapshot *libvirt.DomainSnapshot
+	)
+	def := libvirtxml.DomainSnapshot{
+		Name: o.Name,
+		Description: o.Description,
+		Disks: &libvirtxml.DomainSnapshotDisks{},
+	}
+	for _, disk := range disks {
+		def.Disks.Disks = append(def.Disks.Disks, libvirtxml.DomainSnapshotDisk{
+			Name: disk.Target,
+			Snapshot: "external",
+			Driver: &libvirtxml.DomainDiskDriver{ Type: "qcow2" },
+			Source: &libvirtxml.DomainDiskSource{
+				File: &libvirtxml.DomainDiskSourceFile{ File: vmdef.Snapshot_path(disk.Root, o.Name) },
+			},
+		})
+	}
+	for _, target := range excluded {
+		def.Disks.Disks = append(def.Disks.Disks, libvirtxml.DomainSnapshotDisk{
+			Name: target,
+			Snapshot: "no",
+		})
+	}
+	xmlstr, err = def.Marshal()
+	if (err != nil) {
+		return err
+	}
+	snapshot, err = domain.CreateSnapshotXML(xmlstr, libvirt.DOMAIN_SNAPSHOT_CREATE_DISK_ONLY |
+		libvirt.DOMAIN_SNAPSHOT_CREATE_ATOMIC | libvirt.DOMAIN_SNAPSHOT_CREATE_NO_METADATA)
+	if (err != nil) {
+		return err
+	}
+	snapshot.Free()
+	return nil
+}
+
+func Delete_snapshot(uuid string, name string) error {
+	var (
+		err error
+		conn *libvirt.Connect
+		domain *libvirt.Domain
+		op openapi.Operation = openapi.OpVmSnapshotDelete
+		msg string = "snapshot " + name
+	)
+	conn, err = libvirt.NewConnect(LIBVIRT_URI)
+	if (err != nil) {
+		return err
+	}
+	defer conn.Close()
+	domain, err = conn.LookupDomainByUUIDString(uuid)
+	if (err != nil) {
+		return err
+	}
+	defer domain.Free()
+	started := ts.Now()
+	_ = oplog_record(domain, op, openapi.OPERATION_STARTED, msg, started, 0)
+
+	err = snapshot_delete(conn, domain, uuid, name)
+	if (err != nil) {
+		_ = oplog_record(domain, op, openapi.OPERATION_FAILED, msg + " " + err.Error(), started, ts.Now())
+		return err
+	}
+	_ = oplog_record(domain, op, openapi.OPERATION_COMPLETED, msg, started, ts.Now())
+	return nil
+}
+
+/*
+ * delete a snapshot by committing its overlay into the parent file.
+ * The snapshot after it (or the current state, for the latest one) then builds on the parent.
+ */
+func snapshot_delete(conn *libvirt.Connect, domain *libvirt.Domain, uuid string, name string) error {
+	var (
+		err error
+		list []openapi.Snapshot
+		active bool
+		xmlstr string
+		disks []vmdef.SnapshotDisk
+		i int
+	)
+	err = snapshot_load(domain, &list)
+	if (err != nil) {
+		return err
+	}
+	i = snapshot_find(list, name)
+	if (i < 0) {
+		return errors.New("snapshot not found")
+	}
+	active, err = domain.IsActive()
+	if (err != nil) {
+		return err
+	}
+	xmlstr, disks, _, err = snapshot_disks(domain, list, active)
+	if (err != nil) {
+		return err
+	}
+	last := (i == len(list) - 1)
+	for _, disk := range disks {
+		var (
+			top string = snapshot_chain_file(list, disk.Root, i + 1)
+			base string = snapshot_chain_file(list, disk.Root, i)
+			child string
+		)
+		if (!last) {
+			child = snapshot_chain_file(list, disk.Root, i + 2)
+		}
+		if (active) {
+			err = snapshot_commit_live(domain, disk.Target, top, base, last)
+			if (err == nil) {
+				/*
+				 * the overlays are covered by the lease of the root disk, which the running
+				 * domain holds exclusively: no other host can open the chain, and lockman.Run
+				 * cannot acquire it here. The overlay is not in the chain of the domain anymore.
+				 * A leftover file does not affect the domain, so only log the failure.
+				 */
+				var rerr error = os.Remove(top)
+				if (rerr != nil) {
+					logger.Log("snapshot_delete: failed to remove overlay %s: %s", top, rerr.Error())
+				}
+			}
+		} else {
+			err = storage.Snapshot_commit(disk.Root, top, base, child, uuid)
+			if (err == nil && last) {
+				xmlstr, err = vmdef.Set_disk_source(xmlstr, disk.Target, snapshot_chain(list, disk.Root, i))
+			}
+		}
+		if (err != nil) {
+			return errors.New("disk " + disk.Target + ": " + err.Error())
+		}
+	}
+	if (!active && last) {
+		err = snapshot_define(conn, xmlstr)
+		if (err != nil) {
+			return err
+		}
+	}
+	return snapshot_save(domain, uuid, append(list[:i], list[i + 1:]...))
+}
+
+/*
+ * commit the overlay top of the running domain into base, pivoting to base if top is active.
+ * If the job does not complete within SNAPSHOT_COMMIT_SECONDS, it is aborted without pivot,
+ * which leaves the domain on its current chain.
+ */
+func snapshot_commit_live(domain *libvirt.Domain, target string, top string, base string, last bool) error {
+	var (
+		err error
+		info *libvirt.DomainBlockJobInfo
+		job_type libvirt.DomainBlockJobType = libvirt.DOMAIN_BLOCK_JOB_TYPE_COMMIT
+	)
+	if (last) {
+		job_type = libvirt.DOMAIN_BLOCK_JOB_TYPE_ACTIVE_COMMIT
+		err = domain.BlockCommit(target, base, "", 0, libvirt.DOMAIN_BLOCK_COMMIT_ACTIVE)
+	} else {
+		err = domain.BlockCommit(target, base, top, 0, 0)
+	}
+	if (err != nil) {
+		return err
+	}
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	deadline := time.After(SNAPSHOT_COMMIT_SECONDS * time.Second)
+	for {
+		select {
+		case <- ticker.C:
+			info, err = domain.GetBlockJobInfo(target, 0)
+			if (err != nil) {
+				return err
+			}
+			if (info.Type == libvirt.DOMAIN_BLOCK_JOB_TYPE_UNKNOWN) {
+				/* no job anymore: a commit of an intermediate overlay completed */
+				if (last) {
+					return errors.New("block commit job ended before pivot")
+				}
+				return nil
+			}
+			if (info.Type != job_type) {
+				/* our job is gone, and another one was started on the disk meanwhile */
+				return fmt.Errorf("unexpected block job type %d", info.Type)
+			}
+			if (last && info.End > 0 && info.Cur == info.End) {
+				/* the active commit is ready, switch the disk to base */
+				err = domain.BlockJobAbort(target, libvirt.DOMAIN_BLOCK_JOB_ABORT_PIVOT)
+				if (err != nil) {
+					return err
+				}
+				last = false
+			}
+		case <- deadline:
+			err = domain.BlockJobAbort(target, 0)
+			if (err != nil) {
+				logger.Log("snapshot_commit_live: failed to abort block job on %s: %s", target, err.Error())
+			}
+			return errors.New("block commit timed out")
+		}
+	}
+}
+
+func Revert_snapshot(uuid string, name string) error {
+	var (
+		err error
+		conn *libvirt.Connect
+		domain *libvirt.Domain
+		op openapi.Operation = openapi.OpVmSnapshotRevert
+		msg string = "snapshot " + name
+	)
+	conn, err = libvirt.NewConnect(LIBVIRT_URI)
+	if (err != nil) {
+		return err
+	}
+	defer conn.Close()
+	domain, err = conn.LookupDomainByUUIDString(uuid)
+	if (err != nil) {
+		return err
+	}
+	defer domain.Free()
+	started := ts.Now()
+	_ = oplog_record(domain, op, openapi.OPERATION_STARTED, msg, started, 0)
+
+	err = snapshot_revert(conn, domain, uuid, name)
+	if (err != nil) {
+		_ = oplog_record(domain, op, openapi.OPERATION_FAILED, msg + " " + err.Error(), started, ts.Now())
+		return err
+	}
+	_ = oplog_record(domain, op, openapi.OPERATION_COMPLETED, msg, started, ts.Now())
+	return nil
+}
+
+/*
+ * revert the powered off domain to a snapshot, discarding the current state of the disks.
+ * The snapshots taken after it are deleted, as their overlays build on the discarded state.
+ */
+func snapshot_revert(conn *libvirt.Connect, domain *libvirt.Domain, uuid string, name string) error {
+	var (
+		err error
+		list []openapi.Snapshot
+		active bool
+		xmlstr string
+		disks []vmdef.SnapshotDisk
+		i int
+	)
+	err = snapshot_load(domain, &list)
+	if (err != nil) {
+		return err
+	}
+	i = snapshot_find(list, name)
+	if (i < 0) {
+		return errors.New("snapshot not found")
+	}
+	active, err = domain.IsActive()
+	if (err != nil) {
+		return err
+	}
+	if (active) {
+		return errors.New("domain is not powered off")
+	}
+	xmlstr, disks, _, err = snapshot_disks(domain, list, active)
+	if (err != nil) {
+		return err
+	}
+	for _, disk := range disks {
+		var (
+			overlay string = snapshot_chain_file(list, disk.Root, i + 1)
+			base string = snapshot_chain_file(list, disk.Root, i)
+			discarded []string
+		)
+		for j := i + 1; j <= len(list); j++ {
+			discarded = append(discarded, snapshot_chain_file(list, disk.Root, j))
+		}
+		err = storage.Snapshot_revert(disk.Root, discarded, base, overlay, uuid)
+		if (err != nil) {
+			return errors.New("disk " + disk.Target + ": " + err.Error())
+		}
+		xmlstr, err = vmdef.Set_disk_source(xmlstr, disk.Target, snapshot_chain(list, disk.Root, i + 1))
+		if (err != nil) {
+			return err
+		}
+	}
+	err = snapshot_define(conn, xmlstr)
+	if (err != nil) {
+		return err
+	}
+	return snapshot_save(domain, uuid, list[:i + 1])
+}
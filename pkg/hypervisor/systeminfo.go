@@ -26,6 +26,7 @@ import (
 	"bufio"
 	"strings"
 	"strconv"
+	"net/netip"
 
 	"libvirt.org/go/libvirt"
 	"libvirt.org/go/libvirtxml"
@@ -34,6 +35,7 @@ import (
 	"suse.com/virtx/pkg/logger"
 	"suse.com/virtx/pkg/inventory"
 	"suse.com/virtx/pkg/lockman"
+	"suse.com/virtx/pkg/metadata"
 	"suse.com/virtx/pkg/ts"
 	"suse.com/virtx/pkg/machine"
 
@@ -542,16 +544,86 @@ type xmlInterface struct {
 	} `xml:"vlan"`
 }
 
+type xmlChannel struct {
+	Target struct {
+		Name string `xml:"name,attr"`
+		State string `xml:"state,attr"` /* only in the live XML */
+	} `xml:"target"`
+}
+
 type xmlDomain struct {
 	MemoryBacking *libvirtxml.DomainMemoryBacking `xml:"memoryBacking"`
 	Devices struct {
 		Disks []xmlDisk `xml:"disk"`
 		Interfaces []xmlInterface `xml:"interface"`
+		Channels []xmlChannel `xml:"channel"`
 	} `xml:"devices"`
 }
 
+/*
+ * get the hostname, OS and IP addresses reported by the guest agent.
+ * Errors are ignored, the guest agent can stop responding at any time.
+ */
+func get_guest_info(d *libvirt.Domain, guest *openapi.GuestInfo) {
+	var (
+		err error
+		info *libvirt.DomainGuestInfo
+		ifaces []libvirt.DomainInterface
+	)
+	info, err = d.GetGuestInfo(libvirt.DOMAIN_GUEST_INFO_OS | libvirt.DOMAIN_GUEST_INFO_HOSTNAME, 0)
+	if (err == nil) {
+		guest.Hostname = info.Hostname
+		if (info.OS != nil) {
+			guest.Osid = info.OS.ID
+			guest.Osname = info.OS.PrettyName
+			guest.Osv = info.OS.VersionID
+		}
+	}
+	ifaces, err = d.ListAllInterfaceAddresses(libvirt.DOMAIN_INTERFACE_ADDRESSES_SRC_AGENT)
+	if (err != nil) {
+		return
+	}
+	for _, iface := range ifaces {
+		for _, ip := range iface.Addrs {
+			var addr netip.Addr
+			addr, err = netip.ParseAddr(ip.Addr)
+			if (err != nil || addr.IsLoopback() || addr.IsLinkLocalUnicast()) {
+				continue
+			}
+			if (len(guest.Addresses) >= GUEST_ADDRESSES_MAX) {
+				return
+			}
+			/* drop the zone to keep the address within the serf message budget */
+			guest.Addresses = append(guest.Addresses, addr.WithZone("").String())
+		}
+	}
+}
+
 func get_domain_stats(d *libvirt.Domain, vm *SystemInfoVm, old *SystemInfoVm, imm *SystemInfoImm) error {
 	var err error
+	{
+		/* custom fields, for the inventory search. Domains not defined by virtx have none */
+		var xmlstr string
+		xmlstr, err = d.GetMetadata(libvirt.DOMAIN_METADATA_ELEMENT, "virtx-vm", libvirt.DOMAIN_AFFECT_CONFIG)
+		if (err == nil) {
+			err = metadata.Fields_from_metadata(xmlstr, &vm.Custom)
+			if (err != nil) {
+				logger.Log("get_domain_stats: invalid custom fields for uuid %s: %s", vm.Uuid, err.Error())
+			}
+			/* definitions that predate the limits must not overflow the serf message */
+			if (len(vm.Custom) > CUSTOM_FIELDS_MAX) {
+				vm.Custom = vm.Custom[:CUSTOM_FIELDS_MAX]
+			}
+			for i := range vm.Custom {
+				if (len(vm.Custom[i].Name) > CUSTOM_NAME_MAX) {
+					vm.Custom[i].Name = vm.Custom[i].Name[:CUSTOM_NAME_MAX]
+				}
+				if (len(vm.Custom[i].Value) > CUSTOM_VALUE_MAX) {
+					vm.Custom[i].Value = vm.Custom[i].Value[:CUSTOM_VALUE_MAX]
+				}
+			}
+		}
+	}
 	{
 		// Retrieve the necessary info from domain's XML description
 		var (
@@ -609,6 +681,13 @@ func get_domain_stats(d *libvirt.Domain, vm *SystemInfoVm, old *SystemInfoVm, im
 				vm.Vlanid = int16(net.Vlan.Tags[0].Id) /* XXX only one VlandID for each VM is recognized XXX */
 			}
 		}
+		for _, channel := range xd.Devices.Channels {
+			/* do not wait for the agent timeout if the guest agent is not running */
+			if (channel.Target.Name == GUEST_AGENT_CHANNEL && channel.Target.State == "connected") {
+				get_guest_info(d, &vm.stats.Guest)
+				vm.Addresses = vm.stats.Guest.Addresses
+			}
+		}
 	}
 	{
 		/* now retrieve the necessary info from GetInfo() */
@@ -0,0 +1,95 @@
+/*
+ * Copyright (c) 2024-2026 SUSE LLC
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, see
+ * <https://www.gnu.org/licenses/>
+ */
+package hypervisor
+
+import (
+	"errors"
+	"net"
+	"strconv"
+
+	"libvirt.org/go/libvirt"
+	"libvirt.org/go/libvirtxml"
+)
+
+/*
+ * return the network ("tcp" or "unix") and address of the VNC server of the running domain.
+ * The port is assigned by libvirt when the domain starts (autoport),
+ * so it is read from the live XML every time.
+ */
+func Vnc_address(uuid string) (string, string, error) {
+	var (
+		err error
+		conn *libvirt.Connect
+		domain *libvirt.Domain
+		xml string
+		domain_xml libvirtxml.Domain
+		vnc *libvirtxml.DomainGraphicVNC
+		host string
+	)
+	conn, err = libvirt.NewConnect(LIBVIRT_URI)
+	if (err != nil) {
+		return "", "", err
+	}
+	defer conn.Close()
+	domain, err = conn.LookupDomainByUUIDString(uuid)
+	if (err != nil) {
+		return "", "", err
+	}
+	defer domain.Free()
+	xml, err = domain.GetXMLDesc(0)
+	if (err != nil) {
+		return "", "", err
+	}
+	err = domain_xml.Unmarshal(xml)
+	if (err != nil) {
+		return "", "", err
+	}
+	if (domain_xml.Devices != nil) {
+		for i := range domain_xml.Devices.Graphics {
+			if (domain_xml.Devices.Graphics[i].VNC != nil) {
+				vnc = domain_xml.Devices.Graphics[i].VNC
+				break
+			}
+		}
+	}
+	if (vnc == nil) {
+		return "", "", errors.New("no VNC graphics")
+	}
+	if (vnc.Socket != "") {
+		return "unix", vnc.Socket, nil
+	}
+	if (vnc.Port <= 0) {
+		return "", "", errors.New("no VNC port assigned")
+	}
+	host = vnc.Listen
+	for _, listener := range vnc.Listeners {
+		if (listener.Socket != nil && listener.Socket.Socket != "") {
+			return "unix", listener.Socket.Socket, nil
+		}
+		if (listener.Address != nil && listener.Address.Address != "") {
+			host = listener.Address.Address
+		}
+	}
+	/* listening on all addresses (or default), connect via loopback */
+	if (host == "" || host == "0.0.0.0") {
+		host = "127.0.0.1"
+	} else if (host == "::") {
+		host = "::1"
+	}
+	return "tcp", net.JoinHostPort(host, strconv.Itoa(vnc.Port)), nil
+}
@@ -20,9 +20,11 @@ package inventory
 import (
 	"fmt"
 	"sync"
+	"reflect"
 
 	"suse.com/virtx/pkg/logger"
 	"suse.com/virtx/pkg/model"
+	"suse.com/virtx/pkg/events"
 )
 
 type nothing struct {
@@ -66,6 +68,7 @@ type VmInfo struct {
 	Vlanid int16                /* XXX need requirements engineering for Vlans XXX */
 	Custom []openapi.CustomField
 	Vcpus int16                 /* total number of vcpus in this VM */
+	Addresses []string          /* IP addresses reported by the guest agent */
 }
 
 type HostsInventory map[string]Hostdata
@@ -138,7 +141,7 @@ func Update_host(hostinfo *HostInfo) {
 
 func update_host(hostinfo *HostInfo) {
 	var (
-		present bool
+		present, changed bool
 		hostdata Hostdata
 	)
 	hostdata, present = inventory.hosts[hostinfo.Uuid]
@@ -148,6 +151,7 @@ func update_host(hostinfo *HostInfo) {
 				hostdata.Info.Name, hostdata.Info.Ts, hostinfo.Ts)
 			return
 		}
+		changed = !inventory_host_equal(&hostdata.Info, hostinfo)
 		hostdata.Info = *hostinfo
 	} else {
 		/* this is the first time we see this host. */
@@ -155,8 +159,12 @@ func update_host(hostinfo *HostInfo) {
 			Info: *hostinfo,
 			Vms: make(map[string]nothing),
 		}
+		changed = true
 	}
 	inventory.hosts[hostinfo.Uuid] = hostdata
+	if (changed) {
+		inventory_publish_host(&hostdata.Info)
+	}
 }
 
 func Set_host_state(uuid string, newstate openapi.Cstate) error {
@@ -171,8 +179,12 @@ func set_host_state(uuid string, newstate openapi.Cstate) error {
 	if !ok {
 		return fmt.Errorf("no such host %s", uuid)
 	}
+	if (hostdata.Info.Cstate == newstate) {
+		return nil
+	}
 	hostdata.Info.Cstate = newstate
 	inventory.hosts[uuid] = hostdata
+	inventory_publish_host(&hostdata.Info)
 	return nil
 }
 
@@ -185,7 +197,7 @@ func Update_vm_state(e *VmEvent) error {
 func update_vm_state(uuid string, state openapi.Vmrunstate, host string, ts int64) error {
 	var (
 		vminfo VmInfo
-		present bool
+		present, changed bool
 	)
 	vminfo, present = inventory.vms[uuid]
 	if (!present) {
@@ -198,14 +210,21 @@ func update_vm_state(uuid string, state openapi.Vmrunstate, host string, ts int6
 	if (state == openapi.RUNSTATE_DELETED) {
 		delete_hostdata_vm(uuid, vminfo.Host, host)
 		delete(inventory.vms, uuid)
+		vminfo.Host = host
+		vminfo.Runstate = state
+		inventory_publish_vm(&vminfo)
 		return nil
 	}
 	update_hostdata_vm(uuid, vminfo.Host, host)
 
 	/* update the vms inventory data */
+	changed = (vminfo.Host != host || vminfo.Runstate != state)
 	vminfo.Host = host
 	vminfo.Runstate = state
 	inventory.vms[uuid] = vminfo
+	if (changed) {
+		inventory_publish_vm(&vminfo)
+	}
 	return nil
 }
 
@@ -230,6 +249,9 @@ func update_vm(vminfo *VmInfo) error {
 	}
 	update_hostdata_vm(vminfo.Uuid, old.Host, vminfo.Host)
 	inventory.vms[vminfo.Uuid] = *vminfo
+	if (!present || !inventory_vm_equal(&old, vminfo)) {
+		inventory_publish_vm(vminfo)
+	}
 	return nil
 }
 
@@ -272,3 +294,45 @@ func delete_hostdata_vm(uuid string, old_host string, new_host string) {
 		logger.Log("deleted VM %s in unknown new host %s", uuid, new_host)
 	}
 }
+
+/* compare ignoring the timestamps, which change with every update */
+func inventory_host_equal(a *HostInfo, b *HostInfo) bool {
+	var x, y HostInfo = *a, *b
+	x.Ts, y.Ts = 0, 0
+	return reflect.DeepEqual(x, y)
+}
+
+func inventory_vm_equal(a *VmInfo, b *VmInfo) bool {
+	var x, y VmInfo = *a, *b
+	x.Ts, y.Ts = 0, 0
+	return reflect.DeepEqual(x, y)
+}
+
+func inventory_publish_host(hostinfo *HostInfo) {
+	events.Publish(events.Event{
+		Type: events.TYPE_HOST,
+		Host: hostinfo.Uuid,
+		Data: openapi.HostListItem{
+			Uuid: hostinfo.Uuid,
+			Fields: hostinfo.HostListFields,
+		},
+	})
+}
+
+func inventory_publish_vm(vminfo *VmInfo) {
+	events.Publish(events.Event{
+		Type: events.TYPE_VM,
+		Host: vminfo.Host,
+		Vm: vminfo.Uuid,
+		Data: openapi.VmListItem{
+			Uuid: vminfo.Uuid,
+			Fields: openapi.VmListFields{
+				Name: vminfo.Name,
+				Host: vminfo.Host,
+				Runstate: vminfo.Runstate,
+				Vlanid: vminfo.Vlanid,
+				Ts: vminfo.Ts,
+			},
+		},
+	})
+}
@@ -0,0 +1,60 @@
+/*
+ * Copyright (c) 2024-2026 SUSE LLC
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, see
+ * <https://www.gnu.org/licenses/>
+ */
+package inventory
+
+import (
+	"testing"
+	"strings"
+	"encoding/binary"
+
+	"suse.com/virtx/pkg/model"
+	"suse.com/virtx/pkg/encoding/sbinary"
+
+	. "suse.com/virtx/pkg/constants"
+)
+
+/* the largest VmInfo that Validate and get_guest_info allow must fit in one message */
+func Test_vm_info_max_size(t *testing.T) {
+	var (
+		vminfo VmInfo
+		buf [SERF_MESSAGE_MAX]byte
+		size int
+		err error
+	)
+	vminfo.Uuid = strings.Repeat("u", GENID_LEN)
+	vminfo.Host = strings.Repeat("h", GENID_LEN)
+	vminfo.Runstate = openapi.RUNSTATE_RUNNING
+	vminfo.Ts = 1<<62
+	vminfo.Name = strings.Repeat("n", VM_NAME_MAX)
+	vminfo.Vlanid = VLAN_MAX
+	vminfo.Vcpus = 1<<14
+	for i := 0; i < CUSTOM_FIELDS_MAX; i++ {
+		vminfo.Custom = append(vminfo.Custom, openapi.CustomField{
+			Name: strings.Repeat("k", CUSTOM_NAME_MAX),
+			Value: strings.Repeat("v", CUSTOM_VALUE_MAX),
+		})
+	}
+	for i := 0; i < GUEST_ADDRESSES_MAX; i++ {
+		vminfo.Addresses = append(vminfo.Addresses, "ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff")
+	}
+	size, err = sbinary.Encode(buf[:], binary.LittleEndian, &vminfo)
+	if (err != nil) {
+		t.Fatalf("worst-case VmInfo does not fit in %d bytes: %v", SERF_MESSAGE_MAX, err)
+	}
+	t.Logf("worst-case VmInfo encodes to %d bytes", size)
+}
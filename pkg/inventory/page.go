@@ -0,0 +1,277 @@
+/*
+ * Copyright (c) 2024-2026 SUSE LLC
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, see
+ * <https://www.gnu.org/licenses/>
+ */
+package inventory
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+
+	"suse.com/virtx/pkg/model"
+)
+
+/*
+ * the sort key of an item. Ties are broken by the uuid,
+ * so that the order is stable across calls.
+ */
+type page_key struct {
+	S string           /* string sort key */
+	N int64            /* numeric sort key */
+	Uuid string
+}
+
+/* continuation token contents: the sort and the key of the last item returned */
+type page_token struct {
+	Sort string
+	Key page_key
+}
+
+func page_cmp(a *page_key, b *page_key, desc bool) int {
+	var c int
+	if (a.S != b.S) {
+		c = strings.Compare(a.S, b.S)
+	} else if (a.N != b.N) {
+		if (a.N < b.N) {
+			c = -1
+		} else {
+			c = 1
+		}
+	} else {
+		c = strings.Compare(a.Uuid, b.Uuid)
+	}
+	if (desc) {
+		return -c
+	}
+	return c
+}
+
+func page_vm_key(item *openapi.VmListItem, sort string) (page_key, error) {
+	var key page_key = page_key{ Uuid: item.Uuid }
+	switch (sort) {
+	case "", "uuid":
+	case "name":
+		key.S = item.Fields.Name
+	case "host":
+		key.S = item.Fields.Host
+	case "runstate":
+		key.N = int64(item.Fields.Runstate)
+	case "ts":
+		key.N = item.Fields.Ts
+	default:
+		return key, errors.New("invalid sort key " + sort)
+	}
+	return key, nil
+}
+
+func page_host_key(item *openapi.HostListItem, sort string) (page_key, error) {
+	var key page_key = page_key{ Uuid: item.Uuid }
+	switch (sort) {
+	case "", "uuid":
+	case "name":
+		key.S = item.Fields.Name
+	case "ts":
+		key.N = item.Fields.Ts
+	case "memoryavailable":
+		key.N = int64(item.Fields.Memoryavailable)
+	default:
+		return key, errors.New("invalid sort key " + sort)
+	}
+	return key, nil
+}
+
+func page_token_encode(t *page_token) string {
+	var (
+		err error
+		data []byte
+	)
+	data, err = json.Marshal(t)
+	if (err != nil) {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func page_token_decode(s string, t *page_token) error {
+	var (
+		err error
+		data []byte
+	)
+	data, err = base64.RawURLEncoding.DecodeString(s)
+	if (err != nil) {
+		return errors.New("invalid continuation token")
+	}
+	err = json.Unmarshal(data, t)
+	if (err != nil) {
+		return errors.New("invalid continuation token")
+	}
+	return nil
+}
+
+/*
+ * sort the keys, and select the indexes of the page to return.
+ * Returns the selected indexes (into keys) and the continuation token.
+ */
+func page_select(keys []page_key, sort_by string, page openapi.Page, cont string) ([]int, string, error) {
+	var (
+		err error
+		desc bool
+		order []int
+		start, end int
+		token page_token
+		next string
+	)
+	if (page.Index < 0 || page.Size < 0) {
+		return nil, "", errors.New("invalid page")
+	}
+	desc = strings.HasPrefix(sort_by, "-")
+	order = make([]int, len(keys))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool {
+		return page_cmp(&keys[order[i]], &keys[order[j]], desc) < 0
+	})
+	if (cont != "") {
+		err = page_token_decode(cont, &token)
+		if (err != nil) {
+			return nil, "", err
+		}
+		if (token.Sort != sort_by) {
+			return nil, "", errors.New("continuation token does not match sort")
+		}
+		/* start after the last item returned, even if it has been deleted meanwhile */
+		start = sort.Search(len(order), func(i int) bool {
+			return page_cmp(&keys[order[i]], &token.Key, desc) > 0
+		})
+	} else {
+		start = int(page.Index) * int(page.Size)
+	}
+	if (start > len(order)) {
+		start = len(order)
+	}
+	end = len(order)
+	if (page.Size > 0 && start + int(page.Size) < end) {
+		end = start + int(page.Size)
+	}
+	if (end < len(order) && end > start) {
+		token = page_token{ Sort: sort_by, Key: keys[order[end - 1]] }
+		next = page_token_encode(&token)
+	}
+	return order[start:end], next, nil
+}
+
+/* search VMs, then sort and paginate the results */
+func List_vms(o *openapi.VmListOptions) (openapi.VmList, error) {
+	var (
+		err error
+		all, list openapi.VmList
+		keys []page_key
+		selected []int
+	)
+	all = Search_vms(o.Filter)
+	keys = make([]page_key, len(all.Items))
+	for i := range all.Items {
+		keys[i], err = page_vm_key(&all.Items[i], strings.TrimPrefix(o.Sort, "-"))
+		if (err != nil) {
+			return list, err
+		}
+	}
+	selected, list.Continue, err = page_select(keys, o.Sort, o.Page, o.Continue)
+	if (err != nil) {
+		return list, err
+	}
+	list.Items = make([]openapi.VmListItem, 0, len(selected))
+	for _, i := range selected {
+		list.Items = append(list.Items, all.Items[i])
+	}
+	return list, nil
+}
+
+/* search hosts, then sort and paginate the results */
+func List_hosts(o *openapi.HostListOptions) (openapi.HostList, error) {
+	var (
+		err error
+		all, list openapi.HostList
+		keys []page_key
+		selected []int
+	)
+	all = Search_hosts(o.Filter)
+	keys = make([]page_key, len(all.Items))
+	for i := range all.Items {
+		keys[i], err = page_host_key(&all.Items[i], strings.TrimPrefix(o.Sort, "-"))
+		if (err != nil) {
+			return list, err
+		}
+	}
+	selected, list.Continue, err = page_select(keys, o.Sort, o.Page, o.Continue)
+	if (err != nil) {
+		return list, err
+	}
+	list.Items = make([]openapi.HostListItem, 0, len(selected))
+	for _, i := range selected {
+		list.Items = append(list.Items, all.Items[i])
+	}
+	return list, nil
+}
+
+/*
+ * select the fields (json names, f.e. "name") of each item in the list to return,
+ * so that only those are encoded. zero is an empty item Fields struct, used to validate the names.
+ */
+func Select_fields(list any, zero any, fields []string) (any, error) {
+	var (
+		err error
+		data []byte
+		valid, out map[string]any
+		items []any
+		present bool
+	)
+	data, err = json.Marshal(zero)
+	if (err == nil) {
+		err = json.Unmarshal(data, &valid)
+	}
+	if (err != nil) {
+		return nil, err
+	}
+	for _, field := range fields {
+		_, present = valid[field]
+		if (!present) {
+			return nil, errors.New("invalid field " + field)
+		}
+	}
+	data, err = json.Marshal(list)
+	if (err == nil) {
+		err = json.Unmarshal(data, &out)
+	}
+	if (err != nil) {
+		return nil, err
+	}
+	items, _ = out["items"].([]any)
+	for _, item := range items {
+		var all, selected map[string]any
+		all, _ = item.(map[string]any)["fields"].(map[string]any)
+		selected = make(map[string]any)
+		for _, field := range fields {
+			selected[field] = all[field]
+		}
+		item.(map[string]any)["fields"] = selected
+	}
+	return out, nil
+}
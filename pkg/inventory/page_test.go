@@ -0,0 +1,165 @@
+/*
+ * Copyright (c) 2024-2026 SUSE LLC
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, see
+ * <https://www.gnu.org/licenses/>
+ */
+package inventory
+
+import (
+	"testing"
+	"encoding/json"
+
+	"suse.com/virtx/pkg/model"
+)
+
+func test_keys() []page_key {
+	return []page_key{
+		{S: "charlie", Uuid: "3"},
+		{S: "alpha", Uuid: "1"},
+		{S: "echo", Uuid: "5"},
+		{S: "bravo", Uuid: "2"},
+		{S: "delta", Uuid: "4"},
+	}
+}
+
+func selected_uuids(keys []page_key, selected []int) string {
+	var s string
+	for _, i := range selected {
+		s += keys[i].Uuid
+	}
+	return s
+}
+
+func Test_page_select_sort(t *testing.T) {
+	keys := test_keys()
+	selected, next, err := page_select(keys, "name", openapi.Page{}, "")
+	if (err != nil) {
+		t.Fatalf("page_select: %v", err)
+	}
+	if (selected_uuids(keys, selected) != "12345" || next != "") {
+		t.Errorf("expected 12345 without token, got %s %q", selected_uuids(keys, selected), next)
+	}
+	selected, _, _ = page_select(keys, "-name", openapi.Page{}, "")
+	if (selected_uuids(keys, selected) != "54321") {
+		t.Errorf("expected 54321, got %s", selected_uuids(keys, selected))
+	}
+}
+
+func Test_page_select_index(t *testing.T) {
+	keys := test_keys()
+	cases := []struct {
+		page openapi.Page
+		want string
+	}{
+		{openapi.Page{Index: 0, Size: 2}, "12"},
+		{openapi.Page{Index: 1, Size: 2}, "34"},
+		{openapi.Page{Index: 2, Size: 2}, "5"},
+		{openapi.Page{Index: 3, Size: 2}, ""},
+	}
+	for _, tc := range cases {
+		selected, _, err := page_select(keys, "name", tc.page, "")
+		if (err != nil) {
+			t.Fatalf("page_select: %v", err)
+		}
+		if (selected_uuids(keys, selected) != tc.want) {
+			t.Errorf("page %v: expected %q, got %q", tc.page, tc.want, selected_uuids(keys, selected))
+		}
+	}
+	_, _, err := page_select(keys, "name", openapi.Page{Index: -1, Size: 2}, "")
+	if (err == nil) {
+		t.Errorf("expected error for negative page index")
+	}
+}
+
+func Test_page_select_continue(t *testing.T) {
+	keys := test_keys()
+	page := openapi.Page{Size: 2}
+	var got, next string
+	for i := 0; i < 5; i++ {
+		selected, token, err := page_select(keys, "-name", page, next)
+		if (err != nil) {
+			t.Fatalf("page_select: %v", err)
+		}
+		got += selected_uuids(keys, selected)
+		next = token
+		if (next == "") {
+			break
+		}
+		/* the last item returned is deleted: must not affect the next page */
+		last := keys[selected[len(selected) - 1]].Uuid
+		var remaining []page_key
+		for _, k := range keys {
+			if (k.Uuid != last) {
+				remaining = append(remaining, k)
+			}
+		}
+		keys = remaining
+	}
+	if (got != "54321") {
+		t.Errorf("expected 54321 across pages, got %s", got)
+	}
+	_, _, err := page_select(keys, "name", page, "not-a-token")
+	if (err == nil) {
+		t.Errorf("expected error for invalid token")
+	}
+	_, token, _ := page_select(keys, "name", page, "")
+	_, _, err = page_select(keys, "ts", page, token)
+	if (err == nil) {
+		t.Errorf("expected error for token with different sort")
+	}
+}
+
+func Test_page_vm_key_invalid(t *testing.T) {
+	item := openapi.VmListItem{Uuid: "1"}
+	_, err := page_vm_key(&item, "memoryavailable")
+	if (err == nil) {
+		t.Errorf("expected error for invalid VM sort key")
+	}
+	_, err = page_vm_key(&item, "runstate")
+	if (err != nil) {
+		t.Errorf("page_vm_key(runstate): %v", err)
+	}
+}
+
+func Test_select_fields(t *testing.T) {
+	list := openapi.VmList{
+		Items: []openapi.VmListItem{
+			{ Uuid: "1", Fields: openapi.VmListFields{ Name: "vm1", Host: "h1", Runstate: openapi.RUNSTATE_RUNNING } },
+		},
+		Continue: "c1",
+	}
+	out, err := Select_fields(&list, openapi.VmListFields{}, []string{ "name", "runstate" })
+	if (err != nil) {
+		t.Fatalf("Select_fields: %v", err)
+	}
+	data, _ := json.Marshal(out)
+	var got map[string]any
+	json.Unmarshal(data, &got)
+	item := got["items"].([]any)[0].(map[string]any)
+	fields := item["fields"].(map[string]any)
+	if (item["uuid"] != "1" || got["continue"] != "c1" || len(fields) != 2 || fields["name"] != "vm1") {
+		t.Errorf("unexpected selection %s", data)
+	}
+	/* the selected items still decode as a list */
+	var decoded openapi.VmList
+	err = json.Unmarshal(data, &decoded)
+	if (err != nil || decoded.Items[0].Fields.Name != "vm1" || decoded.Items[0].Fields.Host != "") {
+		t.Errorf("unexpected decoded list %+v: %v", decoded, err)
+	}
+	_, err = Select_fields(&list, openapi.VmListFields{}, []string{ "nosuchfield" })
+	if (err == nil) {
+		t.Error("invalid field: expected error")
+	}
+}
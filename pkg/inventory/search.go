@@ -18,7 +18,9 @@
 package inventory
 
 import (
+	"errors"
 	"strings"
+	"net/netip"
 
 	"suse.com/virtx/pkg/model"
 )
@@ -92,7 +94,17 @@ func Search_vms(f openapi.VmListFields) openapi.VmList {
 	var (
 		vm VmInfo
 		list openapi.VmList
+		address string
+		prefix netip.Prefix
+		err error
 	)
+	if (f.Address != "") {
+		/* the list handler rejects invalid filters, which match no VM here */
+		prefix, err = Parse_address_filter(f.Address)
+		if (err != nil) {
+			return list
+		}
+	}
 	for _, vm = range inventory.vms {
 		if (f.Name != "" && !strings.Contains(vm.Name, f.Name)) {
 			continue
@@ -120,6 +132,12 @@ func Search_vms(f openapi.VmListFields) openapi.VmList {
 				continue
 			}
 		}
+		if (f.Address != "") {
+			address = search_address(vm.Addresses, prefix)
+			if (address == "") {
+				continue
+			}
+		}
 		if (f.Ts != 0 && (vm.Ts > f.Ts)) { /* return only older entries */
 			continue
 		}
@@ -132,9 +150,49 @@ func Search_vms(f openapi.VmListFields) openapi.VmList {
 				Vlanid: vm.Vlanid,
 				Custom: f.Custom,
 				Ts: vm.Ts,
+				Address: address,
 			},
 		}
 		list.Items = append(list.Items, item)
 	}
 	return list
 }
+
+/*
+ * parse an address filter, either an address or a CIDR prefix.
+ * A single address is returned as the prefix containing only that address.
+ */
+func Parse_address_filter(filter string) (netip.Prefix, error) {
+	var (
+		err error
+		prefix netip.Prefix
+		addr netip.Addr
+	)
+	if (strings.Contains(filter, "/")) {
+		prefix, err = netip.ParsePrefix(filter)
+	} else {
+		addr, err = netip.ParseAddr(filter)
+		if (err == nil) {
+			prefix = netip.PrefixFrom(addr, addr.BitLen())
+		}
+	}
+	if (err != nil) {
+		return prefix, errors.New("invalid address filter " + filter)
+	}
+	return prefix, nil
+}
+
+/* return the first of the addresses which is contained in prefix, or "" if none matches */
+func search_address(addresses []string, prefix netip.Prefix) string {
+	var (
+		err error
+		addr netip.Addr
+	)
+	for _, address := range addresses {
+		addr, err = netip.ParseAddr(address)
+		if (err == nil && prefix.Contains(addr)) {
+			return address
+		}
+	}
+	return ""
+}
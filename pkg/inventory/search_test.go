@@ -0,0 +1,60 @@
+/*
+ * Copyright (c) 2024-2026 SUSE LLC
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, see
+ * <https://www.gnu.org/licenses/>
+ */
+package inventory
+
+import (
+	"testing"
+)
+
+func Test_search_address(t *testing.T) {
+	addresses := []string{"10.0.0.5", "fd00::5"}
+	tests := []struct {
+		filter string
+		want string
+	}{
+		{"10.0.0.5", "10.0.0.5"},
+		{"10.0.0.6", ""},
+		{"10.0.0.0/24", "10.0.0.5"},
+		{"10.0.1.0/24", ""},
+		{"fd00::/64", "fd00::5"},
+		{"fd00:0::5", "fd00::5"},
+	}
+	for _, test := range tests {
+		prefix, err := Parse_address_filter(test.filter)
+		if (err != nil) {
+			t.Fatalf("Parse_address_filter(%q): %v", test.filter, err)
+		}
+		got := search_address(addresses, prefix)
+		if (got != test.want) {
+			t.Errorf("search_address(%q) = %q, want %q", test.filter, got, test.want)
+		}
+	}
+	prefix, _ := Parse_address_filter("10.0.0.0/8")
+	if (search_address(nil, prefix) != "") {
+		t.Error("no addresses: expected no match")
+	}
+}
+
+func Test_parse_address_filter_invalid(t *testing.T) {
+	for _, filter := range []string{"not-an-address", "10.0.0.0/99", "10.0.0/8", "10.0.0.300"} {
+		_, err := Parse_address_filter(filter)
+		if (err == nil) {
+			t.Errorf("Parse_address_filter(%q): expected error", filter)
+		}
+	}
+}
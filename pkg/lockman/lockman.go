@@ -35,6 +35,7 @@ import (
 	"golang.org/x/sys/unix"
 
 	"suse.com/virtx/pkg/logger"
+	"suse.com/virtx/pkg/metrics"
 	"suse.com/virtx/pkg/model"
 	"suse.com/virtx/pkg/vmreg"
 
@@ -598,6 +599,7 @@ func Run(resource_name string, uuid string, args [][]string, no_disk bool) error
 	output, err = cmd.CombinedOutput()
 	if (err != nil) {
 		logger.Log("%s\n", string(output))
+		metrics.Inc(metrics.LOCKMAN_SPAWN_FAILED)
 		return err
 	}
 	return nil
@@ -657,6 +659,30 @@ func Get_resource_path(resource_name string) string {
 	return LOCK_DIR + resource_name + "/" + resource_name
 }
 
+/* check that this host is still a member of the lockspace with its host_id */
+func Check_lockspace() error {
+	var (
+		err error
+		host_id uint16
+		lockid uint16
+	)
+	lm.m.RLock()
+	lockid = lm.host_id
+	lm.m.RUnlock()
+
+	host_id, err = lm_inq_lockspace()
+	if (err != nil) {
+		return errors.New("lm_inq_lockspace: " + err.Error())
+	}
+	if (host_id == 0) {
+		return errors.New("not a member of the lockspace")
+	}
+	if (host_id != lockid) {
+		return fmt.Errorf("lockspace host_id %d, expected %d", host_id, lockid)
+	}
+	return nil
+}
+
 func Lockid() int16 {
 	lm.m.RLock()
 	defer lm.m.RUnlock()
@@ -68,6 +68,30 @@ func (vm *Vm) From_xml(xmlstr string, fields *[]openapi.CustomField) error {
 	return nil
 }
 
+/*
+ * read the custom fields from the Vm metadata element as returned by GetMetadata,
+ * which loses the namespace (see Operation below), so Vm.From_xml cannot be used.
+ */
+func Fields_from_metadata(xmlstr string, fields *[]openapi.CustomField) error {
+	var (
+		err error
+		data struct {
+			Fields []Field `xml:"field"`
+		}
+	)
+	err = xml.Unmarshal([]byte(xmlstr), &data)
+	if (err != nil) {
+		return err
+	}
+	for _, field := range data.Fields {
+		*fields = append(*fields, openapi.CustomField{
+			Name: field.Name,
+			Value: field.Value,
+		})
+	}
+	return nil
+}
+
 type Field struct {
 	Name string `xml:"name,attr"`
 	Value string `xml:",chardata"`
@@ -131,3 +155,61 @@ func (op *Operation) From_xml(xmlstr string, o *openapi.Operation, state *openap
 	*te = op.Te
 	return nil
 }
+
+/*
+ * the snapshot list is also kept in its own namespace "virtx-snap",
+ * and like Operation it has no default namespace, since GetMetadata loses it.
+ * The items are in creation order, which is also the order of the overlays.
+ */
+type Snapshots struct {
+	XMLName xml.Name `xml:""`
+	Items []Snapshot `xml:"snapshot"`
+}
+
+type Snapshot struct {
+	Name string `xml:"name,attr"`
+	Ts int64 `xml:"ts"`
+	Live bool `xml:"live"`
+	Description string `xml:"description"`
+}
+
+func (s *Snapshots) To_xml(list []openapi.Snapshot) (string, error) {
+	var (
+		err error
+		xmlstr []byte
+	)
+	*s = Snapshots{
+		XMLName: xml.Name{ Space: "virtx-snap", Local: "data-snap" },
+		Items: []Snapshot{},
+	}
+	for _, snapshot := range list {
+		s.Items = append(s.Items, Snapshot{
+			Name: snapshot.Name,
+			Ts: snapshot.Ts,
+			Live: snapshot.Live,
+			Description: snapshot.Description,
+		})
+	}
+	xmlstr, err = xml.Marshal(s)
+	if (err != nil) {
+		return "", err
+	}
+	return string(xmlstr), nil
+}
+
+func (s *Snapshots) From_xml(xmlstr string, list *[]openapi.Snapshot) error {
+	var err error
+	err = xml.Unmarshal([]byte(xmlstr), s)
+	if (err != nil) {
+		return err
+	}
+	for _, snapshot := range s.Items {
+		*list = append(*list, openapi.Snapshot{
+			Name: snapshot.Name,
+			Description: snapshot.Description,
+			Ts: snapshot.Ts,
+			Live: snapshot.Live,
+		})
+	}
+	return nil
+}
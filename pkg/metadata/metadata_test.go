@@ -184,3 +184,71 @@ func Test_operation_to_xml_from_xml_roundtrip(t *testing.T) {
 		})
 	}
 }
+
+/* *** Fields_from_metadata *** */
+
+func Test_fields_from_metadata(t *testing.T) {
+	var (
+		fields []openapi.CustomField
+		err error
+	)
+	/* as returned by GetMetadata, without namespace */
+	err = Fields_from_metadata(`<data-vm><field name="CID">1217</field><field name="ENV">prod</field></data-vm>`, &fields)
+	if (err != nil) {
+		t.Fatalf("Fields_from_metadata: %v", err)
+	}
+	if (len(fields) != 2 || fields[0].Name != "CID" || fields[0].Value != "1217" || fields[1].Name != "ENV") {
+		t.Errorf("unexpected fields %v", fields)
+	}
+	fields = nil
+	err = Fields_from_metadata(`<virtx-vm:data-vm xmlns:virtx-vm="virtx-vm"><virtx-vm:field name="CID">1217</virtx-vm:field></virtx-vm:data-vm>`, &fields)
+	if (err != nil || len(fields) != 1 || fields[0].Value != "1217") {
+		t.Errorf("with namespace: %v %v", fields, err)
+	}
+}
+
+/* *** Snapshots.To_xml / Snapshots.From_xml *** */
+
+func Test_snapshots_to_xml_from_xml_roundtrip(t *testing.T) {
+	list := []openapi.Snapshot{
+		{Name: "snap1", Description: "before upgrade", Ts: 1700000000000, Live: true},
+		{Name: "snap2", Description: "", Ts: 1700000060000, Live: false},
+	}
+	var s Snapshots
+	xmlstr, err := s.To_xml(list)
+	if (err != nil) {
+		t.Fatalf("To_xml: %v", err)
+	}
+	var (
+		s2 Snapshots
+		parsed []openapi.Snapshot
+	)
+	err = s2.From_xml(xmlstr, &parsed)
+	if (err != nil) {
+		t.Fatalf("From_xml: %v", err)
+	}
+	if (len(parsed) != len(list)) {
+		t.Fatalf("expected %d snapshots, got %d", len(list), len(parsed))
+	}
+	for i := range list {
+		if (parsed[i] != list[i]) {
+			t.Errorf("snapshot %d: expected %+v, got %+v", i, list[i], parsed[i])
+		}
+	}
+}
+
+func Test_snapshots_empty(t *testing.T) {
+	var s Snapshots
+	xmlstr, err := s.To_xml(nil)
+	if (err != nil) {
+		t.Fatalf("To_xml: %v", err)
+	}
+	var parsed []openapi.Snapshot
+	err = s.From_xml(xmlstr, &parsed)
+	if (err != nil) {
+		t.Fatalf("From_xml: %v", err)
+	}
+	if (len(parsed) != 0) {
+		t.Errorf("expected no snapshots, got %d", len(parsed))
+	}
+}
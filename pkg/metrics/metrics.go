@@ -0,0 +1,145 @@
+/*
+ * Copyright (c) 2024-2026 SUSE LLC
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, see
+ * <https://www.gnu.org/licenses/>
+ */
+/*
+ * metrics: internal counters, and helpers to write metrics
+ * in the Prometheus text exposition format.
+ */
+package metrics
+
+import (
+	"io"
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+type Counter int
+
+const (
+	SERF_SENT Counter = iota    /* serf user events sent */
+	SERF_SEND_FAILED            /* serf user events which could not be encoded or sent */
+	SERF_DECODED                /* serf user events received and decoded */
+	SERF_DECODE_FAILED          /* serf user events received which could not be decoded */
+	PROXY_REQUESTS              /* API requests proxied to another host */
+	LOCKMAN_SPAWN_FAILED        /* sanlock client spawn commands which failed */
+	LIBVIRT_RECONNECTS          /* reconnections to libvirt after a connection failure */
+	COUNTER_MAX
+)
+
+var counter_info = [COUNTER_MAX]struct {
+	name string
+	help string
+}{
+	SERF_SENT: { "virtx_serf_messages_sent_total", "Serf user events sent." },
+	SERF_SEND_FAILED: { "virtx_serf_messages_send_failed_total", "Serf user events which could not be sent." },
+	SERF_DECODED: { "virtx_serf_messages_decoded_total", "Serf user events received and decoded." },
+	SERF_DECODE_FAILED: { "virtx_serf_messages_decode_failed_total", "Serf user events received which could not be decoded." },
+	PROXY_REQUESTS: { "virtx_proxy_requests_total", "API requests proxied to another host." },
+	LOCKMAN_SPAWN_FAILED: { "virtx_lockman_spawn_failed_total", "sanlock client spawn commands which failed." },
+	LIBVIRT_RECONNECTS: { "virtx_libvirt_reconnects_total", "Reconnections to libvirt." },
+}
+
+var counters [COUNTER_MAX]atomic.Uint64
+
+type Label struct {
+	Name string
+	Value string
+}
+
+func Inc(c Counter) {
+	counters[c].Add(1)
+}
+
+func Get(c Counter) uint64 {
+	return counters[c].Load()
+}
+
+/* write the HELP and TYPE lines introducing the metric "name" */
+func Write_header(w io.Writer, name string, help string, typ string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+/* write one sample of the metric "name" */
+func Write_sample(w io.Writer, name string, labels []Label, value int64) {
+	var sb strings.Builder
+	sb.WriteString(name)
+	if (len(labels) > 0) {
+		sb.WriteByte('{')
+		for i, l := range labels {
+			if (i > 0) {
+				sb.WriteByte(',')
+			}
+			sb.WriteString(l.Name)
+			sb.WriteString("=\"")
+			sb.WriteString(metrics_escape(l.Value))
+			sb.WriteByte('"')
+		}
+		sb.WriteByte('}')
+	}
+	fmt.Fprintf(w, "%s %d\n", sb.String(), value)
+}
+
+/* write all internal counters */
+func Write_counters(w io.Writer) {
+	for i := Counter(0); i < COUNTER_MAX; i++ {
+		Write_header(w, counter_info[i].name, counter_info[i].help, "counter")
+		fmt.Fprintf(w, "%s %d\n", counter_info[i].name, counters[i].Load())
+	}
+}
+
+/*
+ * return a valid label name made of prefix and s, replacing invalid characters of s with '_'.
+ * Used to turn custom field names into labels.
+ */
+func Label_name(prefix string, s string) string {
+	var sb strings.Builder
+	sb.WriteString(prefix)
+	for _, c := range s {
+		/* a digit is only invalid as the first character of the name */
+		if ((c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c == '_' || (sb.Len() > 0 && c >= '0' && c <= '9')) {
+			sb.WriteRune(c)
+		} else {
+			sb.WriteByte('_')
+		}
+	}
+	return sb.String()
+}
+
+/*
+ * append the label to labels, unless a label with the same name is already present:
+ * a sample with duplicate label names makes the whole scrape fail.
+ */
+func Add_label(labels []Label, name string, value string) []Label {
+	for _, label := range labels {
+		if (label.Name == name) {
+			return labels
+		}
+	}
+	return append(labels, Label{ Name: name, Value: value })
+}
+
+/* escape a label value: backslash, double-quote and line feed */
+func metrics_escape(s string) string {
+	if (!strings.ContainsAny(s, "\\\"\n")) {
+		return s
+	}
+	s = strings.ReplaceAll(s, "\\", "\\\\")
+	s = strings.ReplaceAll(s, "\"", "\\\"")
+	s = strings.ReplaceAll(s, "\n", "\\n")
+	return s
+}
@@ -0,0 +1,87 @@
+/*
+ * Copyright (c) 2024-2026 SUSE LLC
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, see
+ * <https://www.gnu.org/licenses/>
+ */
+package metrics
+
+import (
+	"testing"
+	"strings"
+	"bytes"
+)
+
+func Test_write_sample(t *testing.T) {
+	var buf bytes.Buffer
+	Write_sample(&buf, "virtx_test", nil, 42)
+	Write_sample(&buf, "virtx_test", []Label{{"vm", "v1"}, {"name", "a \"b\"\\c\nd"}}, -1)
+	want := "virtx_test 42\n" +
+		"virtx_test{vm=\"v1\",name=\"a \\\"b\\\"\\\\c\\nd\"} -1\n"
+	if (buf.String() != want) {
+		t.Errorf("got\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func Test_label_name(t *testing.T) {
+	cases := []struct {
+		prefix string
+		in   string
+		want string
+	}{
+		{"", "CID", "CID"},
+		{"", "cost_center", "cost_center"},
+		{"", "1abc", "_abc"},
+		{"", "a-b.c", "a_b_c"},
+		{"custom_", "1abc", "custom_1abc"},
+		{"custom_", "a-b", "custom_a_b"},
+	}
+	for _, tc := range cases {
+		got := Label_name(tc.prefix, tc.in)
+		if (got != tc.want) {
+			t.Errorf("Label_name(%q, %q) = %q, want %q", tc.prefix, tc.in, got, tc.want)
+		}
+	}
+}
+
+func Test_add_label_collision(t *testing.T) {
+	var (
+		buf bytes.Buffer
+		labels []Label = []Label{{"vm", "v1"}}
+	)
+	/* duplicate names, and names which differ only in invalid characters */
+	for _, custom := range []Label{{"CID", "1"}, {"CID", "2"}, {"a-b", "3"}, {"a_b", "4"}} {
+		labels = Add_label(labels, Label_name("custom_", custom.Name), custom.Value)
+	}
+	Write_sample(&buf, "virtx_test", labels, 1)
+	want := "virtx_test{vm=\"v1\",custom_CID=\"1\",custom_a_b=\"3\"} 1\n"
+	if (buf.String() != want) {
+		t.Errorf("got\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func Test_counters(t *testing.T) {
+	var buf bytes.Buffer
+	before := Get(PROXY_REQUESTS)
+	Inc(PROXY_REQUESTS)
+	if (Get(PROXY_REQUESTS) != before + 1) {
+		t.Fatalf("counter not incremented")
+	}
+	Write_counters(&buf)
+	for i := Counter(0); i < COUNTER_MAX; i++ {
+		if (!strings.Contains(buf.String(), "# TYPE " + counter_info[i].name + " counter\n")) {
+			t.Errorf("counter %s missing", counter_info[i].name)
+		}
+	}
+}
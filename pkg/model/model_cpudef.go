@@ -32,6 +32,8 @@ type Cpudef struct {
 	Cores int16 `json:"cores"`
 	// number of threads per core. 0 -> not set
 	Threads int16 `json:"threads"`
+	// maximum number of sockets per node for vCPU hot-add. 0 -> same as sockets
+	Maxsockets int16 `json:"maxsockets"`
 }
 
 type _Cpudef Cpudef
@@ -40,7 +42,7 @@ type _Cpudef Cpudef
 // This constructor will assign default values to properties that have it defined,
 // and makes sure properties required by API are set, but the set of arguments
 // will change when the set of required properties is changed
-func NewCpudef(model string, nodes int16, sockets int16, cores int16, threads int16) *Cpudef {
+func NewCpudef(model string, nodes int16, sockets int16, cores int16, threads int16, maxsockets int16) *Cpudef {
 	this := Cpudef{}
     // XXX these two lines are here to silence errors about unused imports
     var _ = fmt.Println
@@ -51,6 +53,7 @@ func NewCpudef(model string, nodes int16, sockets int16, cores int16, threads in
 	this.Sockets = sockets
 	this.Cores = cores
 	this.Threads = threads
+	this.Maxsockets = maxsockets
 	return &this
 }
 
@@ -182,6 +185,30 @@ func (o *Cpudef) SetThreads(v int16) {
 	o.Threads = v
 }
 
+// GetMaxsockets returns the Maxsockets field value
+func (o *Cpudef) GetMaxsockets() int16 {
+	if o == nil {
+		var ret int16
+		return ret
+	}
+
+	return o.Maxsockets
+}
+
+// GetMaxsocketsOk returns a tuple with the Maxsockets field value
+// and a boolean to check if the value has been set.
+func (o *Cpudef) GetMaxsocketsOk() (*int16, bool) {
+	if o == nil {
+		return nil, false
+	}
+	return &o.Maxsockets, true
+}
+
+// SetMaxsockets sets field value
+func (o *Cpudef) SetMaxsockets(v int16) {
+	o.Maxsockets = v
+}
+
 func (o Cpudef) ToMap() (map[string]interface{}, error) {
 	toSerialize := map[string]interface{}{}
 	toSerialize["model"] = o.Model
@@ -189,6 +216,7 @@ func (o Cpudef) ToMap() (map[string]interface{}, error) {
 	toSerialize["sockets"] = o.Sockets
 	toSerialize["cores"] = o.Cores
 	toSerialize["threads"] = o.Threads
+	toSerialize["maxsockets"] = o.Maxsockets
 	return toSerialize, nil
 }
 
@@ -29,6 +29,8 @@ type Disk struct {
 	Prov DiskProvMode `json:"prov"`
 	// size in MiB. Provide 0 if disk should not be created (unmanaged or claiming existing disk)
 	Size int32 `json:"size"`
+	// for linked provisioning, the template image the disk is an overlay of, \"\" otherwise
+	Base string `json:"base"`
 }
 
 type _Disk Disk
@@ -37,7 +39,7 @@ type _Disk Disk
 // This constructor will assign default values to properties that have it defined,
 // and makes sure properties required by API are set, but the set of arguments
 // will change when the set of required properties is changed
-func NewDisk(path string, device DiskDevice, bus DiskBus, man DiskManMode, prov DiskProvMode, size int32) *Disk {
+func NewDisk(path string, device DiskDevice, bus DiskBus, man DiskManMode, prov DiskProvMode, size int32, base string) *Disk {
 	this := Disk{}
     // XXX these two lines are here to silence errors about unused imports
     var _ = fmt.Println
@@ -49,6 +51,7 @@ func NewDisk(path string, device DiskDevice, bus DiskBus, man DiskManMode, prov
 	this.Man = man
 	this.Prov = prov
 	this.Size = size
+	this.Base = base
 	return &this
 }
 
@@ -204,6 +207,30 @@ func (o *Disk) SetSize(v int32) {
 	o.Size = v
 }
 
+// GetBase returns the Base field value
+func (o *Disk) GetBase() string {
+	if o == nil {
+		var ret string
+		return ret
+	}
+
+	return o.Base
+}
+
+// GetBaseOk returns a tuple with the Base field value
+// and a boolean to check if the value has been set.
+func (o *Disk) GetBaseOk() (*string, bool) {
+	if o == nil {
+		return nil, false
+	}
+	return &o.Base, true
+}
+
+// SetBase sets field value
+func (o *Disk) SetBase(v string) {
+	o.Base = v
+}
+
 func (o Disk) ToMap() (map[string]interface{}, error) {
 	toSerialize := map[string]interface{}{}
 	toSerialize["path"] = o.Path
@@ -212,6 +239,7 @@ func (o Disk) ToMap() (map[string]interface{}, error) {
 	toSerialize["man"] = o.Man
 	toSerialize["prov"] = o.Prov
 	toSerialize["size"] = o.Size
+	toSerialize["base"] = o.Base
 	return toSerialize, nil
 }
 
@@ -16,7 +16,7 @@ import (
 	"fmt"
 )
 
-// DiskProvMode disk provisioning mode: thick or thin virtual disks (not relevant for iSCSI LUNs, use NONE) 
+// DiskProvMode disk provisioning mode: thick or thin virtual disks, or thin overlays linked to a template image (not relevant for iSCSI LUNs, use NONE) 
 type DiskProvMode int16
 
 // List of disk_prov_mode
@@ -24,6 +24,7 @@ const (
 	DISK_PROV_NONE DiskProvMode = 0
 	DISK_PROV_THIN DiskProvMode = 1
 	DISK_PROV_THICK DiskProvMode = 2
+	DISK_PROV_LINKED DiskProvMode = 3
 )
 
 // All allowed values of DiskProvMode enum
@@ -31,6 +32,7 @@ var AllowedDiskProvModeEnumValues = []DiskProvMode{
 	0,
 	1,
 	2,
+	3,
 }
 
 func (v *DiskProvMode) UnmarshalJSON(src []byte) error {
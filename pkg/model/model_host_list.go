@@ -23,6 +23,8 @@ var _ MappedNullable = &HostList{}
 // HostList struct for HostList
 type HostList struct {
 	Items []HostListItem `json:"items"`
+	// Continuation token to get the next page, empty if there are no more items.
+	Continue string `json:"continue"`
 }
 
 type _HostList HostList
@@ -31,13 +33,14 @@ type _HostList HostList
 // This constructor will assign default values to properties that have it defined,
 // and makes sure properties required by API are set, but the set of arguments
 // will change when the set of required properties is changed
-func NewHostList(items []HostListItem) *HostList {
+func NewHostList(items []HostListItem, continue_ string) *HostList {
 	this := HostList{}
     // XXX these two lines are here to silence errors about unused imports
     var _ = fmt.Println
     var _ = bytes.NewBuffer
 
 	this.Items = items
+	this.Continue = continue_
 	return &this
 }
 
@@ -73,9 +76,34 @@ func (o *HostList) SetItems(v []HostListItem) {
 	o.Items = v
 }
 
+// GetContinue returns the Continue field value
+func (o *HostList) GetContinue() string {
+	if o == nil {
+		var ret string
+		return ret
+	}
+
+	return o.Continue
+}
+
+// GetContinueOk returns a tuple with the Continue field value
+// and a boolean to check if the value has been set.
+func (o *HostList) GetContinueOk() (*string, bool) {
+	if o == nil {
+		return nil, false
+	}
+	return &o.Continue, true
+}
+
+// SetContinue sets field value
+func (o *HostList) SetContinue(v string) {
+	o.Continue = v
+}
+
 func (o HostList) ToMap() (map[string]interface{}, error) {
 	toSerialize := map[string]interface{}{}
 	toSerialize["items"] = o.Items
+	toSerialize["continue"] = o.Continue
 	return toSerialize, nil
 }
 
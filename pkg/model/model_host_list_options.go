@@ -23,6 +23,13 @@ var _ MappedNullable = &HostListOptions{}
 // HostListOptions struct for HostListOptions
 type HostListOptions struct {
 	Filter HostListFields `json:"filter"`
+	Page Page `json:"page"`
+	// Sort key: name, ts, memoryavailable or uuid (default). Prefix with - for descending order.
+	Sort string `json:"sort"`
+	// Continuation token returned by a previous list call, to get the next page. Overrides page.index.
+	Continue string `json:"continue"`
+	// Fields to return for each host (f.e. name, memoryavailable). Empty -> all fields.
+	Select []string `json:"select"`
 }
 
 type _HostListOptions HostListOptions
@@ -31,13 +38,17 @@ type _HostListOptions HostListOptions
 // This constructor will assign default values to properties that have it defined,
 // and makes sure properties required by API are set, but the set of arguments
 // will change when the set of required properties is changed
-func NewHostListOptions(filter HostListFields) *HostListOptions {
+func NewHostListOptions(filter HostListFields, page Page, sort string, continue_ string, select_ []string) *HostListOptions {
 	this := HostListOptions{}
     // XXX these two lines are here to silence errors about unused imports
     var _ = fmt.Println
     var _ = bytes.NewBuffer
 
 	this.Filter = filter
+	this.Page = page
+	this.Sort = sort
+	this.Continue = continue_
+	this.Select = select_
 	return &this
 }
 
@@ -73,9 +84,109 @@ func (o *HostListOptions) SetFilter(v HostListFields) {
 	o.Filter = v
 }
 
+// GetPage returns the Page field value
+func (o *HostListOptions) GetPage() Page {
+	if o == nil {
+		var ret Page
+		return ret
+	}
+
+	return o.Page
+}
+
+// GetPageOk returns a tuple with the Page field value
+// and a boolean to check if the value has been set.
+func (o *HostListOptions) GetPageOk() (*Page, bool) {
+	if o == nil {
+		return nil, false
+	}
+	return &o.Page, true
+}
+
+// SetPage sets field value
+func (o *HostListOptions) SetPage(v Page) {
+	o.Page = v
+}
+
+// GetSort returns the Sort field value
+func (o *HostListOptions) GetSort() string {
+	if o == nil {
+		var ret string
+		return ret
+	}
+
+	return o.Sort
+}
+
+// GetSortOk returns a tuple with the Sort field value
+// and a boolean to check if the value has been set.
+func (o *HostListOptions) GetSortOk() (*string, bool) {
+	if o == nil {
+		return nil, false
+	}
+	return &o.Sort, true
+}
+
+// SetSort sets field value
+func (o *HostListOptions) SetSort(v string) {
+	o.Sort = v
+}
+
+// GetContinue returns the Continue field value
+func (o *HostListOptions) GetContinue() string {
+	if o == nil {
+		var ret string
+		return ret
+	}
+
+	return o.Continue
+}
+
+// GetContinueOk returns a tuple with the Continue field value
+// and a boolean to check if the value has been set.
+func (o *HostListOptions) GetContinueOk() (*string, bool) {
+	if o == nil {
+		return nil, false
+	}
+	return &o.Continue, true
+}
+
+// SetContinue sets field value
+func (o *HostListOptions) SetContinue(v string) {
+	o.Continue = v
+}
+
+// GetSelect returns the Select field value
+func (o *HostListOptions) GetSelect() []string {
+	if o == nil {
+		var ret []string
+		return ret
+	}
+
+	return o.Select
+}
+
+// GetSelectOk returns a tuple with the Select field value
+// and a boolean to check if the value has been set.
+func (o *HostListOptions) GetSelectOk() ([]string, bool) {
+	if o == nil {
+		return nil, false
+	}
+	return o.Select, true
+}
+
+// SetSelect sets field value
+func (o *HostListOptions) SetSelect(v []string) {
+	o.Select = v
+}
+
 func (o HostListOptions) ToMap() (map[string]interface{}, error) {
 	toSerialize := map[string]interface{}{}
 	toSerialize["filter"] = o.Filter
+	toSerialize["page"] = o.Page
+	toSerialize["sort"] = o.Sort
+	toSerialize["continue"] = o.Continue
+	toSerialize["select"] = o.Select
 	return toSerialize, nil
 }
 
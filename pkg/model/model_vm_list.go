@@ -23,6 +23,8 @@ var _ MappedNullable = &VmList{}
 // VmList struct for VmList
 type VmList struct {
 	Items []VmListItem `json:"items"`
+	// Continuation token to get the next page, empty if there are no more items.
+	Continue string `json:"continue"`
 }
 
 type _VmList VmList
@@ -31,13 +33,14 @@ type _VmList VmList
 // This constructor will assign default values to properties that have it defined,
 // and makes sure properties required by API are set, but the set of arguments
 // will change when the set of required properties is changed
-func NewVmList(items []VmListItem) *VmList {
+func NewVmList(items []VmListItem, continue_ string) *VmList {
 	this := VmList{}
     // XXX these two lines are here to silence errors about unused imports
     var _ = fmt.Println
     var _ = bytes.NewBuffer
 
 	this.Items = items
+	this.Continue = continue_
 	return &this
 }
 
@@ -73,9 +76,34 @@ func (o *VmList) SetItems(v []VmListItem) {
 	o.Items = v
 }
 
+// GetContinue returns the Continue field value
+func (o *VmList) GetContinue() string {
+	if o == nil {
+		var ret string
+		return ret
+	}
+
+	return o.Continue
+}
+
+// GetContinueOk returns a tuple with the Continue field value
+// and a boolean to check if the value has been set.
+func (o *VmList) GetContinueOk() (*string, bool) {
+	if o == nil {
+		return nil, false
+	}
+	return &o.Continue, true
+}
+
+// SetContinue sets field value
+func (o *VmList) SetContinue(v string) {
+	o.Continue = v
+}
+
 func (o VmList) ToMap() (map[string]interface{}, error) {
 	toSerialize := map[string]interface{}{}
 	toSerialize["items"] = o.Items
+	toSerialize["continue"] = o.Continue
 	return toSerialize, nil
 }
 
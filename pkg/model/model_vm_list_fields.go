@@ -31,6 +31,8 @@ type VmListFields struct {
 	Custom CustomField `json:"custom"`
 	// 64bit UTC Unix timestamp in milliseconds since Epoc. A 0 value is used if the timestamp is not available.
 	Ts int64 `json:"ts"`
+	// IP address reported by the guest agent, or a CIDR prefix, f.e. 10.0.0.0/24
+	Address string `json:"address"`
 }
 
 type _VmListFields VmListFields
@@ -39,7 +41,7 @@ type _VmListFields VmListFields
 // This constructor will assign default values to properties that have it defined,
 // and makes sure properties required by API are set, but the set of arguments
 // will change when the set of required properties is changed
-func NewVmListFields(name string, host string, runstate Vmrunstate, vlanid int16, custom CustomField, ts int64) *VmListFields {
+func NewVmListFields(name string, host string, runstate Vmrunstate, vlanid int16, custom CustomField, ts int64, address string) *VmListFields {
 	this := VmListFields{}
     // XXX these two lines are here to silence errors about unused imports
     var _ = fmt.Println
@@ -51,6 +53,7 @@ func NewVmListFields(name string, host string, runstate Vmrunstate, vlanid int16
 	this.Vlanid = vlanid
 	this.Custom = custom
 	this.Ts = ts
+	this.Address = address
 	return &this
 }
 
@@ -206,6 +209,30 @@ func (o *VmListFields) SetTs(v int64) {
 	o.Ts = v
 }
 
+// GetAddress returns the Address field value
+func (o *VmListFields) GetAddress() string {
+	if o == nil {
+		var ret string
+		return ret
+	}
+
+	return o.Address
+}
+
+// GetAddressOk returns a tuple with the Address field value
+// and a boolean to check if the value has been set.
+func (o *VmListFields) GetAddressOk() (*string, bool) {
+	if o == nil {
+		return nil, false
+	}
+	return &o.Address, true
+}
+
+// SetAddress sets field value
+func (o *VmListFields) SetAddress(v string) {
+	o.Address = v
+}
+
 func (o VmListFields) ToMap() (map[string]interface{}, error) {
 	toSerialize := map[string]interface{}{}
 	toSerialize["name"] = o.Name
@@ -214,6 +241,7 @@ func (o VmListFields) ToMap() (map[string]interface{}, error) {
 	toSerialize["vlanid"] = o.Vlanid
 	toSerialize["custom"] = o.Custom
 	toSerialize["ts"] = o.Ts
+	toSerialize["address"] = o.Address
 	return toSerialize, nil
 }
 
@@ -23,6 +23,13 @@ var _ MappedNullable = &VmListOptions{}
 // VmListOptions struct for VmListOptions
 type VmListOptions struct {
 	Filter VmListFields `json:"filter"`
+	Page Page `json:"page"`
+	// Sort key: name, host, runstate, ts or uuid (default). Prefix with - for descending order.
+	Sort string `json:"sort"`
+	// Continuation token returned by a previous list call, to get the next page. Overrides page.index.
+	Continue string `json:"continue"`
+	// Fields to return for each VM (f.e. name, runstate). Empty -> all fields.
+	Select []string `json:"select"`
 }
 
 type _VmListOptions VmListOptions
@@ -31,13 +38,17 @@ type _VmListOptions VmListOptions
 // This constructor will assign default values to properties that have it defined,
 // and makes sure properties required by API are set, but the set of arguments
 // will change when the set of required properties is changed
-func NewVmListOptions(filter VmListFields) *VmListOptions {
+func NewVmListOptions(filter VmListFields, page Page, sort string, continue_ string, select_ []string) *VmListOptions {
 	this := VmListOptions{}
     // XXX these two lines are here to silence errors about unused imports
     var _ = fmt.Println
     var _ = bytes.NewBuffer
 
 	this.Filter = filter
+	this.Page = page
+	this.Sort = sort
+	this.Continue = continue_
+	this.Select = select_
 	return &this
 }
 
@@ -73,9 +84,109 @@ func (o *VmListOptions) SetFilter(v VmListFields) {
 	o.Filter = v
 }
 
+// GetPage returns the Page field value
+func (o *VmListOptions) GetPage() Page {
+	if o == nil {
+		var ret Page
+		return ret
+	}
+
+	return o.Page
+}
+
+// GetPageOk returns a tuple with the Page field value
+// and a boolean to check if the value has been set.
+func (o *VmListOptions) GetPageOk() (*Page, bool) {
+	if o == nil {
+		return nil, false
+	}
+	return &o.Page, true
+}
+
+// SetPage sets field value
+func (o *VmListOptions) SetPage(v Page) {
+	o.Page = v
+}
+
+// GetSort returns the Sort field value
+func (o *VmListOptions) GetSort() string {
+	if o == nil {
+		var ret string
+		return ret
+	}
+
+	return o.Sort
+}
+
+// GetSortOk returns a tuple with the Sort field value
+// and a boolean to check if the value has been set.
+func (o *VmListOptions) GetSortOk() (*string, bool) {
+	if o == nil {
+		return nil, false
+	}
+	return &o.Sort, true
+}
+
+// SetSort sets field value
+func (o *VmListOptions) SetSort(v string) {
+	o.Sort = v
+}
+
+// GetContinue returns the Continue field value
+func (o *VmListOptions) GetContinue() string {
+	if o == nil {
+		var ret string
+		return ret
+	}
+
+	return o.Continue
+}
+
+// GetContinueOk returns a tuple with the Continue field value
+// and a boolean to check if the value has been set.
+func (o *VmListOptions) GetContinueOk() (*string, bool) {
+	if o == nil {
+		return nil, false
+	}
+	return &o.Continue, true
+}
+
+// SetContinue sets field value
+func (o *VmListOptions) SetContinue(v string) {
+	o.Continue = v
+}
+
+// GetSelect returns the Select field value
+func (o *VmListOptions) GetSelect() []string {
+	if o == nil {
+		var ret []string
+		return ret
+	}
+
+	return o.Select
+}
+
+// GetSelectOk returns a tuple with the Select field value
+// and a boolean to check if the value has been set.
+func (o *VmListOptions) GetSelectOk() ([]string, bool) {
+	if o == nil {
+		return nil, false
+	}
+	return o.Select, true
+}
+
+// SetSelect sets field value
+func (o *VmListOptions) SetSelect(v []string) {
+	o.Select = v
+}
+
 func (o VmListOptions) ToMap() (map[string]interface{}, error) {
 	toSerialize := map[string]interface{}{}
 	toSerialize["filter"] = o.Filter
+	toSerialize["page"] = o.Page
+	toSerialize["sort"] = o.Sort
+	toSerialize["continue"] = o.Continue
+	toSerialize["select"] = o.Select
 	return toSerialize, nil
 }
 
@@ -24,6 +24,10 @@ var _ MappedNullable = &VmShutdownOptions{}
 type VmShutdownOptions struct {
 	// if 0, send ACPI signal for the guest to gracefully shutdown. If 1, SIGTERM, if 2, SIGKILL.
 	Force int16 `json:"force"`
+	// seconds to wait for the guest to shutdown with force 0, before escalating to SIGTERM and then to SIGKILL. If 0, wait for the guest indefinitely.
+	Timeout int32 `json:"timeout"`
+	// with force 0, ask the guest agent to shutdown instead of sending the ACPI signal
+	Agent bool `json:"agent"`
 }
 
 type _VmShutdownOptions VmShutdownOptions
@@ -32,13 +36,15 @@ type _VmShutdownOptions VmShutdownOptions
 // This constructor will assign default values to properties that have it defined,
 // and makes sure properties required by API are set, but the set of arguments
 // will change when the set of required properties is changed
-func NewVmShutdownOptions(force int16) *VmShutdownOptions {
+func NewVmShutdownOptions(force int16, timeout int32, agent bool) *VmShutdownOptions {
 	this := VmShutdownOptions{}
     // XXX these two lines are here to silence errors about unused imports
     var _ = fmt.Println
     var _ = bytes.NewBuffer
 
 	this.Force = force
+	this.Timeout = timeout
+	this.Agent = agent
 	return &this
 }
 
@@ -74,9 +80,59 @@ func (o *VmShutdownOptions) SetForce(v int16) {
 	o.Force = v
 }
 
+// GetTimeout returns the Timeout field value
+func (o *VmShutdownOptions) GetTimeout() int32 {
+	if o == nil {
+		var ret int32
+		return ret
+	}
+
+	return o.Timeout
+}
+
+// GetTimeoutOk returns a tuple with the Timeout field value
+// and a boolean to check if the value has been set.
+func (o *VmShutdownOptions) GetTimeoutOk() (*int32, bool) {
+	if o == nil {
+		return nil, false
+	}
+	return &o.Timeout, true
+}
+
+// SetTimeout sets field value
+func (o *VmShutdownOptions) SetTimeout(v int32) {
+	o.Timeout = v
+}
+
+// GetAgent returns the Agent field value
+func (o *VmShutdownOptions) GetAgent() bool {
+	if o == nil {
+		var ret bool
+		return ret
+	}
+
+	return o.Agent
+}
+
+// GetAgentOk returns a tuple with the Agent field value
+// and a boolean to check if the value has been set.
+func (o *VmShutdownOptions) GetAgentOk() (*bool, bool) {
+	if o == nil {
+		return nil, false
+	}
+	return &o.Agent, true
+}
+
+// SetAgent sets field value
+func (o *VmShutdownOptions) SetAgent(v bool) {
+	o.Agent = v
+}
+
 func (o VmShutdownOptions) ToMap() (map[string]interface{}, error) {
 	toSerialize := map[string]interface{}{}
 	toSerialize["force"] = o.Force
+	toSerialize["timeout"] = o.Timeout
+	toSerialize["agent"] = o.Agent
 	return toSerialize, nil
 }
 
@@ -39,6 +39,10 @@ type Vmdef struct {
 	Genid string `json:"genid"`
 	// Custom Fields
 	Custom []CustomField `json:"custom"`
+	// add a channel for the QEMU guest agent, which reports the IP addresses, hostname and OS of the guest and can shutdown or reboot it
+	GuestAgent bool `json:"guest_agent"`
+	// password for the VNC console, at most 8 characters, empty for none. Shown as ******** when reading the VM
+	VncPassword string `json:"vnc_password"`
 }
 
 type _Vmdef Vmdef
@@ -47,7 +51,7 @@ type _Vmdef Vmdef
 // This constructor will assign default values to properties that have it defined,
 // and makes sure properties required by API are set, but the set of arguments
 // will change when the set of required properties is changed
-func NewVmdef(name string, cpudef Cpudef, memory VmdefMemory, numa Numa, osdisk Disk, disks []Disk, nets []Net, vlanid int16, firmware FirmwareType, genid string, custom []CustomField) *Vmdef {
+func NewVmdef(name string, cpudef Cpudef, memory VmdefMemory, numa Numa, osdisk Disk, disks []Disk, nets []Net, vlanid int16, firmware FirmwareType, genid string, custom []CustomField, guestAgent bool, vncPassword string) *Vmdef {
 	this := Vmdef{}
     // XXX these two lines are here to silence errors about unused imports
     var _ = fmt.Println
@@ -64,6 +68,8 @@ func NewVmdef(name string, cpudef Cpudef, memory VmdefMemory, numa Numa, osdisk
 	this.Firmware = firmware
 	this.Genid = genid
 	this.Custom = custom
+	this.GuestAgent = guestAgent
+	this.VncPassword = vncPassword
 	return &this
 }
 
@@ -339,6 +345,54 @@ func (o *Vmdef) SetCustom(v []CustomField) {
 	o.Custom = v
 }
 
+// GetGuestAgent returns the GuestAgent field value
+func (o *Vmdef) GetGuestAgent() bool {
+	if o == nil {
+		var ret bool
+		return ret
+	}
+
+	return o.GuestAgent
+}
+
+// GetGuestAgentOk returns a tuple with the GuestAgent field value
+// and a boolean to check if the value has been set.
+func (o *Vmdef) GetGuestAgentOk() (*bool, bool) {
+	if o == nil {
+		return nil, false
+	}
+	return &o.GuestAgent, true
+}
+
+// SetGuestAgent sets field value
+func (o *Vmdef) SetGuestAgent(v bool) {
+	o.GuestAgent = v
+}
+
+// GetVncPassword returns the VncPassword field value
+func (o *Vmdef) GetVncPassword() string {
+	if o == nil {
+		var ret string
+		return ret
+	}
+
+	return o.VncPassword
+}
+
+// GetVncPasswordOk returns a tuple with the VncPassword field value
+// and a boolean to check if the value has been set.
+func (o *Vmdef) GetVncPasswordOk() (*string, bool) {
+	if o == nil {
+		return nil, false
+	}
+	return &o.VncPassword, true
+}
+
+// SetVncPassword sets field value
+func (o *Vmdef) SetVncPassword(v string) {
+	o.VncPassword = v
+}
+
 func (o Vmdef) ToMap() (map[string]interface{}, error) {
 	toSerialize := map[string]interface{}{}
 	toSerialize["name"] = o.Name
@@ -352,6 +406,8 @@ func (o Vmdef) ToMap() (map[string]interface{}, error) {
 	toSerialize["firmware"] = o.Firmware
 	toSerialize["genid"] = o.Genid
 	toSerialize["custom"] = o.Custom
+	toSerialize["guest_agent"] = o.GuestAgent
+	toSerialize["vnc_password"] = o.VncPassword
 	return toSerialize, nil
 }
 
@@ -26,6 +26,8 @@ type VmdefMemory struct {
 	Total int32 `json:"total"`
 	// whether hugepages are requested
 	Hp bool `json:"hp"`
+	// maximum memory in MiB for memory hot-add. 0 -> same as total
+	Max int32 `json:"max"`
 }
 
 type _VmdefMemory VmdefMemory
@@ -34,7 +36,7 @@ type _VmdefMemory VmdefMemory
 // This constructor will assign default values to properties that have it defined,
 // and makes sure properties required by API are set, but the set of arguments
 // will change when the set of required properties is changed
-func NewVmdefMemory(total int32, hp bool) *VmdefMemory {
+func NewVmdefMemory(total int32, hp bool, max int32) *VmdefMemory {
 	this := VmdefMemory{}
     // XXX these two lines are here to silence errors about unused imports
     var _ = fmt.Println
@@ -42,6 +44,7 @@ func NewVmdefMemory(total int32, hp bool) *VmdefMemory {
 
 	this.Total = total
 	this.Hp = hp
+	this.Max = max
 	return &this
 }
 
@@ -101,10 +104,35 @@ func (o *VmdefMemory) SetHp(v bool) {
 	o.Hp = v
 }
 
+// GetMax returns the Max field value
+func (o *VmdefMemory) GetMax() int32 {
+	if o == nil {
+		var ret int32
+		return ret
+	}
+
+	return o.Max
+}
+
+// GetMaxOk returns a tuple with the Max field value
+// and a boolean to check if the value has been set.
+func (o *VmdefMemory) GetMaxOk() (*int32, bool) {
+	if o == nil {
+		return nil, false
+	}
+	return &o.Max, true
+}
+
+// SetMax sets field value
+func (o *VmdefMemory) SetMax(v int32) {
+	o.Max = v
+}
+
 func (o VmdefMemory) ToMap() (map[string]interface{}, error) {
 	toSerialize := map[string]interface{}{}
 	toSerialize["total"] = o.Total
 	toSerialize["hp"] = o.Hp
+	toSerialize["max"] = o.Max
 	return toSerialize, nil
 }
 
@@ -41,6 +41,7 @@ type Vmstats struct {
 	// Net Tx KiB/s
 	NetTxBw int32 `json:"net_tx_bw"`
 	Oplog OplogList `json:"oplog"`
+	Guest GuestInfo `json:"guest"`
 }
 
 type _Vmstats Vmstats
@@ -49,7 +50,7 @@ type _Vmstats Vmstats
 // This constructor will assign default values to properties that have it defined,
 // and makes sure properties required by API are set, but the set of arguments
 // will change when the set of required properties is changed
-func NewVmstats(cpuUtilization int32, mhzUsed int32, memoryCapacity int64, memoryUsed int64, diskCapacity int64, diskAllocation int64, diskPhysical int64, netRxBw int32, netTxBw int32, oplog OplogList) *Vmstats {
+func NewVmstats(cpuUtilization int32, mhzUsed int32, memoryCapacity int64, memoryUsed int64, diskCapacity int64, diskAllocation int64, diskPhysical int64, netRxBw int32, netTxBw int32, oplog OplogList, guest GuestInfo) *Vmstats {
 	this := Vmstats{}
     // XXX these two lines are here to silence errors about unused imports
     var _ = fmt.Println
@@ -65,6 +66,7 @@ func NewVmstats(cpuUtilization int32, mhzUsed int32, memoryCapacity int64, memor
 	this.NetRxBw = netRxBw
 	this.NetTxBw = netTxBw
 	this.Oplog = oplog
+	this.Guest = guest
 	return &this
 }
 
@@ -316,6 +318,30 @@ func (o *Vmstats) SetOplog(v OplogList) {
 	o.Oplog = v
 }
 
+// GetGuest returns the Guest field value
+func (o *Vmstats) GetGuest() GuestInfo {
+	if o == nil {
+		var ret GuestInfo
+		return ret
+	}
+
+	return o.Guest
+}
+
+// GetGuestOk returns a tuple with the Guest field value
+// and a boolean to check if the value has been set.
+func (o *Vmstats) GetGuestOk() (*GuestInfo, bool) {
+	if o == nil {
+		return nil, false
+	}
+	return &o.Guest, true
+}
+
+// SetGuest sets field value
+func (o *Vmstats) SetGuest(v GuestInfo) {
+	o.Guest = v
+}
+
 func (o Vmstats) ToMap() (map[string]interface{}, error) {
 	toSerialize := map[string]interface{}{}
 	toSerialize["cpu_utilization"] = o.CpuUtilization
@@ -328,6 +354,7 @@ func (o Vmstats) ToMap() (map[string]interface{}, error) {
 	toSerialize["net_rx_bw"] = o.NetRxBw
 	toSerialize["net_tx_bw"] = o.NetTxBw
 	toSerialize["oplog"] = o.Oplog
+	toSerialize["guest"] = o.Guest
 	return toSerialize, nil
 }
 
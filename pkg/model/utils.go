@@ -366,60 +366,123 @@ type OperationState int16
 
 const (
     _ Operation = iota  // dummy first element to start iota at 1
+	OpEventList
 	OpHostGet
 	OpHostList
+	OpMetricsGet
+	OpTaskGet
+	OpTaskList
+	OpTemplateList
 	OpVmBoot
+	OpVmClone
+	OpVmConsole
 	OpVmCreate
 	OpVmDelete
+	OpVmDiskAttach
+	OpVmDiskDetach
 	OpVmGet
 	OpVmList
 	OpVmMigrate
 	OpVmMigrateAbort
 	OpVmMigrateGet
+	OpVmNetAttach
+	OpVmNetDetach
+	OpVmPatch
 	OpVmPause
+	OpVmReboot
 	OpVmRegister
+	OpVmResize
 	OpVmResume
 	OpVmRunstateGet
+	OpVmScreenshot
 	OpVmShutdown
+	OpVmSnapshotCreate
+	OpVmSnapshotDelete
+	OpVmSnapshotList
+	OpVmSnapshotRevert
+	OpVmTemplateCreate
 	OpVmUpdate
+	OpVmVnc
 )
 
 var OperationToString = map[Operation]string{
+	OpEventList: "EventList",
 	OpHostGet: "HostGet",
 	OpHostList: "HostList",
+	OpMetricsGet: "MetricsGet",
+	OpTaskGet: "TaskGet",
+	OpTaskList: "TaskList",
+	OpTemplateList: "TemplateList",
 	OpVmBoot: "VmBoot",
+	OpVmClone: "VmClone",
+	OpVmConsole: "VmConsole",
 	OpVmCreate: "VmCreate",
 	OpVmDelete: "VmDelete",
+	OpVmDiskAttach: "VmDiskAttach",
+	OpVmDiskDetach: "VmDiskDetach",
 	OpVmGet: "VmGet",
 	OpVmList: "VmList",
 	OpVmMigrate: "VmMigrate",
 	OpVmMigrateAbort: "VmMigrateAbort",
 	OpVmMigrateGet: "VmMigrateGet",
+	OpVmNetAttach: "VmNetAttach",
+	OpVmNetDetach: "VmNetDetach",
+	OpVmPatch: "VmPatch",
 	OpVmPause: "VmPause",
+	OpVmReboot: "VmReboot",
 	OpVmRegister: "VmRegister",
+	OpVmResize: "VmResize",
 	OpVmResume: "VmResume",
 	OpVmRunstateGet: "VmRunstateGet",
+	OpVmScreenshot: "VmScreenshot",
 	OpVmShutdown: "VmShutdown",
+	OpVmSnapshotCreate: "VmSnapshotCreate",
+	OpVmSnapshotDelete: "VmSnapshotDelete",
+	OpVmSnapshotList: "VmSnapshotList",
+	OpVmSnapshotRevert: "VmSnapshotRevert",
+	OpVmTemplateCreate: "VmTemplateCreate",
 	OpVmUpdate: "VmUpdate",
+	OpVmVnc: "VmVnc",
 }
 
 var OperationFromString = map[string]Operation{
+	"EventList": OpEventList,
 	"HostGet": OpHostGet,
 	"HostList": OpHostList,
+	"MetricsGet": OpMetricsGet,
+	"TaskGet": OpTaskGet,
+	"TaskList": OpTaskList,
+	"TemplateList": OpTemplateList,
 	"VmBoot": OpVmBoot,
+	"VmClone": OpVmClone,
+	"VmConsole": OpVmConsole,
 	"VmCreate": OpVmCreate,
 	"VmDelete": OpVmDelete,
+	"VmDiskAttach": OpVmDiskAttach,
+	"VmDiskDetach": OpVmDiskDetach,
 	"VmGet": OpVmGet,
 	"VmList": OpVmList,
 	"VmMigrate": OpVmMigrate,
 	"VmMigrateAbort": OpVmMigrateAbort,
 	"VmMigrateGet": OpVmMigrateGet,
+	"VmNetAttach": OpVmNetAttach,
+	"VmNetDetach": OpVmNetDetach,
+	"VmPatch": OpVmPatch,
 	"VmPause": OpVmPause,
+	"VmReboot": OpVmReboot,
 	"VmRegister": OpVmRegister,
+	"VmResize": OpVmResize,
 	"VmResume": OpVmResume,
 	"VmRunstateGet": OpVmRunstateGet,
+	"VmScreenshot": OpVmScreenshot,
 	"VmShutdown": OpVmShutdown,
+	"VmSnapshotCreate": OpVmSnapshotCreate,
+	"VmSnapshotDelete": OpVmSnapshotDelete,
+	"VmSnapshotList": OpVmSnapshotList,
+	"VmSnapshotRevert": OpVmSnapshotRevert,
+	"VmTemplateCreate": OpVmTemplateCreate,
 	"VmUpdate": OpVmUpdate,
+	"VmVnc": OpVmVnc,
 }
 
 const (
@@ -182,6 +182,8 @@ func (mode DiskProvMode) String() string {
 		return "t"
 	case DISK_PROV_THICK:
 		return "T"
+	case DISK_PROV_LINKED:
+		return "L"
 	}
 	return ""
 }
@@ -197,6 +199,9 @@ func (mode *DiskProvMode) Parse(c byte) error {
 	case 'T':
 		*mode = DISK_PROV_THICK
 		return nil
+	case 'L':
+		*mode = DISK_PROV_LINKED
+		return nil
 	}
 	return errors.New("could not parse disk provisioning mode")
 }
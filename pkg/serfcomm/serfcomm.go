@@ -20,22 +20,29 @@ package serfcomm
 import (
 	"errors"
 	"sync"
+	"sync/atomic"
 	"encoding/binary"
 	"time"
 	"github.com/hashicorp/serf/client"
 
 	"suse.com/virtx/pkg/hypervisor"
 	"suse.com/virtx/pkg/inventory"
+	"suse.com/virtx/pkg/task"
 	"suse.com/virtx/pkg/model"
 	"suse.com/virtx/pkg/logger"
+	"suse.com/virtx/pkg/metrics"
+	"suse.com/virtx/pkg/ts"
 	"suse.com/virtx/pkg/encoding/sbinary"
+
+	. "suse.com/virtx/pkg/constants"
 )
 
 const (
 	LABEL_HOST_INFO string = "HI"
 	LABEL_VM_INFO string = "VI"
 	LABEL_VM_EVENT string = "VE"
-	MAX_MESSAGE_SIZE uint = 1024
+	LABEL_TASK_EVENT string = "TE"
+	MAX_MESSAGE_SIZE uint = SERF_MESSAGE_MAX
 	RECONNECT_SECONDS = 5
 	RPC_ADDR = "127.0.0.1:7373"
 )
@@ -46,20 +53,34 @@ var serf = struct {
 	enc_buffer [MAX_MESSAGE_SIZE]byte
 	channel chan map[string]any
 	stream client.StreamHandle
+	broadcast_ts atomic.Int64   /* time of the last successful host info broadcast */
 }{}
 
 /* locking version of the serf.c == nil check */
-func is_connected() bool {
+func Is_connected() bool {
 	serf.m.RLock()
 	defer serf.m.RUnlock()
 	return serf.c != nil
 }
 
+/* return the time of the last successful system info broadcast, 0 if none yet */
+func Last_broadcast() int64 {
+	return serf.broadcast_ts.Load()
+}
+
 func send_user_event(label string, payload []byte) error {
+	var err error
 	if (serf.c == nil) {
-		return errors.New("RPC client closed")
+		err = errors.New("RPC client closed")
+	} else {
+		err = serf.c.UserEvent(label, payload, false)
 	}
-	return serf.c.UserEvent(label, payload, false)
+	if (err != nil) {
+		metrics.Inc(metrics.SERF_SEND_FAILED)
+	} else {
+		metrics.Inc(metrics.SERF_SENT)
+	}
+	return err
 }
 
 func update_tags(hostinfo *inventory.HostInfo) error {
@@ -119,6 +140,21 @@ func send_vm_event(e *inventory.VmEvent) error {
 	return send_user_event(LABEL_VM_EVENT, serf.enc_buffer[:eventsize])
 }
 
+func send_task_event(e *task.TaskEvent) error {
+	serf.m.Lock()
+	defer serf.m.Unlock()
+	var (
+		eventsize int
+		err error
+	)
+	eventsize, err = sbinary.Encode(serf.enc_buffer[:], binary.LittleEndian, e)
+	if (err != nil) {
+		return err
+	}
+	logger.Debug("send_task_event payload len=%d\n", eventsize)
+	return send_user_event(LABEL_TASK_EVENT, serf.enc_buffer[:eventsize])
+}
+
 func recv_serf_events() {
 	for {
 		logger.Debug("RecvSerfEvents loop start...")
@@ -191,8 +227,10 @@ func handle_user_event(e map[string]any) {
 		size, err = sbinary.Decode(payload, binary.LittleEndian, &hi)
 		if (err != nil) {
 			logger.Log("Decode %s: ERR '%s' at offset %d", name, err.Error(), size)
+			metrics.Inc(metrics.SERF_DECODE_FAILED)
 		} else {
 			logger.Debug("Decode %s: OK  %d %s %s", name, hi.Ts, hi.Uuid, hi.Name)
+			metrics.Inc(metrics.SERF_DECODED)
 			inventory.Update_host(&hi)
 		}
 	case LABEL_VM_EVENT:
@@ -203,8 +241,10 @@ func handle_user_event(e map[string]any) {
 		size, err = sbinary.Decode(payload, binary.LittleEndian, &ve)
 		if (err != nil) {
 			logger.Log("Decode %s: ERR '%s' at offset %d", name, err.Error(), size)
+			metrics.Inc(metrics.SERF_DECODE_FAILED)
 		} else {
 			logger.Debug("Decode %s: OK  %d %s %s", name, ve.Ts, ve.Uuid, ve.Runstate)
+			metrics.Inc(metrics.SERF_DECODED)
 			err = inventory.Update_vm_state(&ve)
 			if (err != nil) {
 				logger.Log(err.Error())
@@ -218,15 +258,32 @@ func handle_user_event(e map[string]any) {
 		size, err = sbinary.Decode(payload, binary.LittleEndian, &vm)
 		if (err != nil) {
 			logger.Log("Decode %s: ERR '%s' at offset %d", name, err.Error(), size)
+			metrics.Inc(metrics.SERF_DECODE_FAILED)
 		} else {
 			logger.Debug("Decode %s: OK  %d %s %s %d", name, vm.Ts, vm.Uuid, vm.Name, vm.Runstate)
+			metrics.Inc(metrics.SERF_DECODED)
 			err = inventory.Update_vm(&vm)
 			if (err != nil) {
 				logger.Log(err.Error())
 			}
 		}
+	case LABEL_TASK_EVENT:
+		var (
+			te task.TaskEvent
+			size int
+		)
+		size, err = sbinary.Decode(payload, binary.LittleEndian, &te)
+		if (err != nil) {
+			logger.Log("Decode %s: ERR '%s' at offset %d", name, err.Error(), size)
+			metrics.Inc(metrics.SERF_DECODE_FAILED)
+		} else {
+			logger.Debug("Decode %s: OK  %d %s %s %s", name, te.Ts, te.Uuid, te.Op, te.State)
+			metrics.Inc(metrics.SERF_DECODED)
+			task.Update(&te)
+		}
 	default:
 		logger.Log("[UNKNOWN-EVENT] %s %s", name, payload)
+		metrics.Inc(metrics.SERF_DECODE_FAILED)
 	}
 }
 
@@ -237,7 +294,7 @@ func send_system_info(ch <-chan hypervisor.SystemInfo) {
 	)
 	logger.Debug("SendSystemInfo loop start...")
 	for si = range ch {
-		if (!is_connected()) {
+		if (!Is_connected()) {
 			/* do nothing with the systeminfo if we are not connected */
 			continue
 		}
@@ -250,6 +307,8 @@ func send_system_info(ch <-chan hypervisor.SystemInfo) {
 			err = send_host_info(&si.Host.HostInfo)
 			if (err != nil) {
 				logger.Log("send_host_info: " + err.Error())
+			} else {
+				serf.broadcast_ts.Store(ts.Now())
 			}
 		}
 		for _, vm := range si.Vms {
@@ -265,7 +324,7 @@ func send_system_info(ch <-chan hypervisor.SystemInfo) {
 func send_vm_events(eventCh <-chan inventory.VmEvent) {
 	logger.Debug("SendVmEvents loop start...")
 	for e := range eventCh {
-		if (!is_connected()) {
+		if (!Is_connected()) {
 			/* do nothing with the vm events if we are not connected */
 			continue
 		}
@@ -276,6 +335,20 @@ func send_vm_events(eventCh <-chan inventory.VmEvent) {
 	logger.Debug("SendVmEvents loop exit")
 }
 
+func send_task_events(eventCh <-chan task.TaskEvent) {
+	logger.Debug("SendTaskEvents loop start...")
+	for e := range eventCh {
+		if (!Is_connected()) {
+			/* do nothing with the task events if we are not connected */
+			continue
+		}
+		if err := send_task_event(&e); err != nil {
+			logger.Log(err.Error())
+		}
+	}
+	logger.Debug("SendTaskEvents loop exit")
+}
+
 func Connect() error {
 	serf.m.Lock()
 	defer serf.m.Unlock()
@@ -297,9 +370,11 @@ func Connect() error {
 }
 
 func Start_listening(
-	vm_event_ch chan inventory.VmEvent, system_info_ch chan hypervisor.SystemInfo) {
+	vm_event_ch chan inventory.VmEvent, system_info_ch chan hypervisor.SystemInfo,
+	task_event_ch chan task.TaskEvent) {
 	/* create subroutines to send and process events */
 	go send_vm_events(vm_event_ch)
+	go send_task_events(task_event_ch)
 	go send_system_info(system_info_ch)
 	go recv_serf_events()
 }
@@ -0,0 +1,94 @@
+/*
+ * Copyright (c) 2024-2026 SUSE LLC
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, see
+ * <https://www.gnu.org/licenses/>
+ */
+package storage
+
+import (
+	"suse.com/virtx/pkg/model"
+	"suse.com/virtx/pkg/logger"
+	"suse.com/virtx/pkg/vmdef"
+	"suse.com/virtx/pkg/lockman"
+)
+
+/*
+ * file operations on the snapshot overlays of a powered off VM.
+ * They all run under the lease of the root disk, keyed on the root path as in the domain XML.
+ * While the VM is running, the hypervisor does the equivalent operations itself.
+ */
+
+func snapshot_resource_name(root string) string {
+	return lockman.Get_resource_name(openapi.DEVICE_DISK, root)
+}
+
+func snapshot_overlay_args(backing string, overlay string) []string {
+	return []string{ "/usr/bin/qemu-img", "create", "-f", "qcow2",
+		"-F", vmdef.Snapshot_driver(backing), "-b", backing, overlay }
+}
+
+/* create the overlays of snapshot name on top of the active files of the disks */
+func Snapshot_create(disks []vmdef.SnapshotDisk, name string, uuid string) error {
+	var (
+		err, rerr error
+		overlay string
+	)
+	for i, disk := range disks {
+		overlay = vmdef.Snapshot_path(disk.Root, name)
+		logger.Debug("creating overlay %s", overlay)
+		err = lockman.Run(snapshot_resource_name(disk.Root), uuid, [][]string{ snapshot_overlay_args(disk.Active, overlay) }, false)
+		if (err == nil) {
+			continue
+		}
+		/* remove the overlays already created */
+		for _, done := range disks[:i] {
+			overlay = vmdef.Snapshot_path(done.Root, name)
+			rerr = lockman.Run(snapshot_resource_name(done.Root), uuid, [][]string{ { "/usr/bin/rm", "-f", "--", overlay } }, false)
+			if (rerr != nil) {
+				logger.Log("Snapshot_create failed to remove overlay %s: %s", overlay, rerr.Error())
+			}
+		}
+		return err
+	}
+	return nil
+}
+
+/*
+ * merge the overlay top into its backing file base, and remove it.
+ * child is the overlay on top of top, which is rebased on base, or "" if top is the active file.
+ */
+func Snapshot_commit(root string, top string, base string, child string, uuid string) error {
+	var args [][]string = [][]string{
+		{ "/usr/bin/qemu-img", "commit", "-d", "-f", "qcow2", top },
+	}
+	if (child != "") {
+		args = append(args, []string{ "/usr/bin/qemu-img", "rebase", "-u", "-f", "qcow2",
+			"-F", vmdef.Snapshot_driver(base), "-b", base, child })
+	}
+	args = append(args, []string{ "/usr/bin/rm", "--", top })
+	logger.Debug("committing %s into %s", top, base)
+	return lockman.Run(snapshot_resource_name(root), uuid, args, false)
+}
+
+/* remove the overlays discarded, and recreate overlay empty on top of base */
+func Snapshot_revert(root string, discarded []string, base string, overlay string, uuid string) error {
+	var args [][]string
+	for _, path := range discarded {
+		args = append(args, []string{ "/usr/bin/rm", "-f", "--", path })
+	}
+	args = append(args, snapshot_overlay_args(base, overlay))
+	logger.Debug("reverting %s to %s", root, base)
+	return lockman.Run(snapshot_resource_name(root), uuid, args, false)
+}
@@ -35,6 +35,7 @@ type storage_ops struct {
 type created_resource struct {
 	disk *openapi.Disk
 	resource_name string
+	path string /* file to remove on rollback, or "" */
 }
 type CreatedResources []created_resource /* for rollback */
 
@@ -46,6 +47,12 @@ func Rollback(created CreatedResources, uuid string) {
 		c created_resource
 	)
 	for _, c = range created {
+		if (c.path != "") {
+			rerr = lockman.Run(c.resource_name, uuid, [][]string{ { "/usr/bin/rm", "-f", "--", c.path } }, false)
+			if (rerr != nil) {
+				logger.Log("Rollback failed to remove %s: %s", c.path, rerr.Error())
+			}
+		}
 		rerr = lockman.Delete_resource(c.resource_name, uuid)
 		if (rerr != nil) {
 			logger.Log("Rollback failed to delete resource %s: %s", c.resource_name, rerr.Error())
@@ -65,6 +72,10 @@ func Create(vm *openapi.Vmdef, old *openapi.Vmdef, uuid string) (CreatedResource
 	)
 	for _, disk := range vmdef.Disks(vm) {
 		if (old != nil && vmdef.Has_path(old, disk.Path)) {
+			/* the backing file is recorded in the existing image, it cannot be changed */
+			if (vmdef.Get_disk(old, disk.Path).Base != disk.Base) {
+				return created, errors.New("cannot change the base of existing disk " + disk.Path)
+			}
 			continue
 		}
 		if (storage_is_managed_disk(disk)) {
@@ -73,7 +84,7 @@ func Create(vm *openapi.Vmdef, old *openapi.Vmdef, uuid string) (CreatedResource
 			if (err != nil) {
 				return created, err
 			}
-			created = append(created, created_resource{ disk, resource_name })
+			created = append(created, created_resource{ disk, resource_name, "" })
 		}
 		if (storage_is_managed_disk(disk) && disk.Prov != openapi.DISK_PROV_NONE) {
 			err = storage_create_disk(disk, resource_name, uuid)
@@ -87,6 +98,44 @@ func Create(vm *openapi.Vmdef, old *openapi.Vmdef, uuid string) (CreatedResource
 	return created, nil
 }
 
+/*
+ * Create the managed storage of a clone of src, copying each managed disk from sources,
+ * which has the files to copy for each disk of vm in the order of vmdef.Disks.
+ * Each copy runs under the lease of the disk of src_uuid it is copied from.
+ * Unlike Create, the copies are also removed by Rollback.
+ */
+func Clone(vm *openapi.Vmdef, src *openapi.Vmdef, sources []string, src_uuid string, uuid string) (CreatedResources, error) {
+	var (
+		err error
+		resource_name string
+		created CreatedResources
+		src_disks []*openapi.Disk = vmdef.Disks(src)
+	)
+	for i, disk := range vmdef.Disks(vm) {
+		if (!storage_is_managed_disk(disk)) {
+			err = Detect(disk)
+			if (err != nil) {
+				return created, err
+			}
+			continue
+		}
+		if (disk.Device == openapi.DEVICE_LUN) {
+			return created, errors.New("managed LUNs cannot be cloned")
+		}
+		resource_name = lockman.Get_resource_name(disk.Device, disk.Path)
+		err = lockman.Create_resource(resource_name, uuid)
+		if (err != nil) {
+			return created, err
+		}
+		created = append(created, created_resource{ disk, resource_name, disk.Path })
+		err = vdisk_clone(disk, sources[i], lockman.Get_resource_name(src_disks[i].Device, src_disks[i].Path), src_uuid)
+		if (err != nil) {
+			return created, err
+		}
+	}
+	return created, nil
+}
+
 /*
  * Delete the managed storage.
  * If the operation is an update, do not delete a disk that is present in the new definition
@@ -0,0 +1,108 @@
+/*
+ * Copyright (c) 2024-2026 SUSE LLC
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, see
+ * <https://www.gnu.org/licenses/>
+ */
+package storage
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"suse.com/virtx/pkg/model"
+	"suse.com/virtx/pkg/logger"
+	"suse.com/virtx/pkg/vmdef"
+	"suse.com/virtx/pkg/lockman"
+	. "suse.com/virtx/pkg/constants"
+)
+
+/*
+ * template images are shared by the linked disks of many VMs, which only read them.
+ * They have no resource lease: only the overlays of the linked disks are owned by a VM,
+ * so templates are created read-only, and virtx never writes or deletes them afterwards.
+ * A template is not created again with the name of a missing one still used by linked disks.
+ */
+
+/* list the template images in TEMPLATE_DIR */
+func Template_list() ([]openapi.Template, error) {
+	var (
+		err error
+		entries []os.DirEntry
+		info qinfo
+		list []openapi.Template = []openapi.Template{}
+	)
+	entries, err = os.ReadDir(TEMPLATE_DIR)
+	if (err != nil) {
+		if (errors.Is(err, os.ErrNotExist)) {
+			return list, nil
+		}
+		return nil, err
+	}
+	for _, entry := range entries {
+		path := TEMPLATE_DIR + entry.Name()
+		if (!entry.Type().IsRegular() || vmdef.Validate_template_path(path) == "") {
+			continue
+		}
+		info, err = vdisk_info(path)
+		if (err != nil) {
+			continue
+		}
+		list = append(list, openapi.Template{
+			Name: strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name())),
+			Path: path,
+			Size: int32(info.VirtualSize / MiB),
+		})
+	}
+	return list, nil
+}
+
+/*
+ * create the template name as a full qcow2 copy of source, which is read under the lease
+ * of the disk root owned by VM uuid. The copy is made read-only before it appears as a template.
+ */
+func Template_create(source string, root string, name string, uuid string) error {
+	var (
+		err error
+		path, tmp, source_driver string
+	)
+	source_driver = vmdef.Snapshot_driver(source)
+	if (source_driver == "") {
+		return errors.New("invalid source Disk Path")
+	}
+	path = vmdef.Template_path(name)
+	tmp = path + "." + uuid + ".tmp"
+	err = os.MkdirAll(TEMPLATE_DIR, 0750)
+	if (err != nil) {
+		return err
+	}
+	args := []string{ "/usr/bin/qemu-img", "convert", "-f", source_driver, "-O", "qcow2",
+		"-o", "lazy_refcounts=off", source, tmp }
+	logger.Debug("qemu-img %v", args)
+	err = lockman.Run(lockman.Get_resource_name(openapi.DEVICE_DISK, root), uuid,
+		[][]string{ args, { "/usr/bin/chmod", "0444", "--", tmp } }, false)
+	if (err != nil) {
+		os.Remove(tmp)
+		return err
+	}
+	/* link fails if a template with the same name was created in the meantime */
+	err = os.Link(tmp, path)
+	os.Remove(tmp)
+	if (errors.Is(err, os.ErrExist)) {
+		return errors.New("template already exists")
+	}
+	return err
+}
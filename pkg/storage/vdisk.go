@@ -47,28 +47,75 @@ func vdisk_create(disk *openapi.Disk, resource_name string, uuid string) error {
 	if (err != nil) {
 		return fmt.Errorf("could not create path %s: %w", filepath.Dir(disk.Path), err)
 	}
-	prealloc = func () string {
-		if (disk_driver == "qcow2") {
-			if (disk.Prov == openapi.DISK_PROV_THIN) {
-				return "metadata"
-			} else {
-				return "falloc"
-			}
-		} else if (disk.Prov == openapi.DISK_PROV_THIN) {
-			return "off"
+	args := []string{ "/usr/bin/qemu-img", "create", "-f", disk_driver }
+	if (disk.Prov == openapi.DISK_PROV_LINKED) {
+		/* thin overlay of the template, which is only read and has no lease of its own */
+		args = append(args, "-F", vmdef.Disk_driver(disk.Base), "-b", disk.Base)
+	} else {
+		prealloc = vdisk_prealloc(disk_driver, disk.Prov)
+		args = append(args, "-o", "preallocation=" + prealloc)
+	}
+	if (disk_driver == "qcow2") {
+		args = append(args, "-o", "lazy_refcounts=off")
+	}
+	args = append(args, disk.Path)
+	/* a linked disk of size 0 gets the size of the template */
+	if (disk.Prov != openapi.DISK_PROV_LINKED || disk.Size > 0) {
+		args = append(args, fmt.Sprintf("%dM", disk.Size))
+	}
+	logger.Debug("qemu-img %v", args)
+
+	/* run provisioning under lease lock */
+	return lockman.Run(resource_name, uuid, [][]string{ args }, false)
+}
+
+/* get the qemu-img preallocation option for the disk driver and provisioning mode */
+func vdisk_prealloc(disk_driver string, prov openapi.DiskProvMode) string {
+	if (disk_driver == "qcow2") {
+		if (prov == openapi.DISK_PROV_THIN) {
+			return "metadata"
 		} else {
 			return "falloc"
 		}
-	}()
-	args := []string{ "/usr/bin/qemu-img", "create", "-f", disk_driver, "-o", "preallocation=" + prealloc }
+	} else if (prov == openapi.DISK_PROV_THIN) {
+		return "off"
+	} else {
+		return "falloc"
+	}
+}
+
+/* create the disk as a copy of the source file, which can be in a different format */
+func vdisk_clone(disk *openapi.Disk, source string, source_resource string, source_uuid string) error {
+	var (
+		err error
+		disk_driver, source_driver string
+	)
+	disk_driver = vmdef.Validate_disk_path(disk.Path)
+	if (disk_driver == "") {
+		return errors.New("invalid Disk Path")
+	}
+	source_driver = vmdef.Snapshot_driver(source)
+	if (source_driver == "") {
+		return errors.New("invalid source Disk Path")
+	}
+	err = os.MkdirAll(filepath.Dir(disk.Path), 0750)
+	if (err != nil) {
+		return fmt.Errorf("could not create path %s: %w", filepath.Dir(disk.Path), err)
+	}
+	args := []string{ "/usr/bin/qemu-img", "convert", "-f", source_driver, "-O", disk_driver,
+		"-o", "preallocation=" + vdisk_prealloc(disk_driver, disk.Prov) }
 	if (disk_driver == "qcow2") {
 		args = append(args, "-o", "lazy_refcounts=off")
 	}
-	args = append(args, disk.Path, fmt.Sprintf("%dM", disk.Size))
+	args = append(args, source, disk.Path)
 	logger.Debug("qemu-img %v", args)
 
-	/* run provisioning under lease lock */
-	return lockman.Run(resource_name, uuid, [][]string{ args }, false)
+	/*
+	 * copy under the lease of the source disk, which also covers its snapshot overlays,
+	 * so that the source VM cannot be started while its disk is read.
+	 * The new disk is not referenced by any domain yet.
+	 */
+	return lockman.Run(source_resource, source_uuid, [][]string{ args }, false)
 }
 
 func vdisk_delete(disk *openapi.Disk, resource_name string, uuid string) error {
@@ -83,9 +130,18 @@ func vdisk_delete(disk *openapi.Disk, resource_name string, uuid string) error {
 	resource_path := lockman.Get_resource_path(resource_name)
 	args := [][]string{
 		{ "/usr/bin/rm", "--", disk.Path },
-		{ "/usr/bin/rm", "--", resource_path },
-		{ "/usr/bin/rmdir", "--", filepath.Dir(resource_path) },
 	}
+	/* also delete the snapshot overlays of the disk */
+	overlays, _ := filepath.Glob(vmdef.Snapshot_path(disk.Path, "*"))
+	for _, overlay := range overlays {
+		if (vmdef.Snapshot_root(overlay) == disk.Path) {
+			args = append(args, []string{ "/usr/bin/rm", "--", overlay })
+		}
+	}
+	args = append(args,
+		[]string{ "/usr/bin/rm", "--", resource_path },
+		[]string{ "/usr/bin/rmdir", "--", filepath.Dir(resource_path) },
+	)
 	return lockman.Run(resource_name, uuid, args, true)
 }
 
@@ -104,12 +160,65 @@ func vdisk_detect(disk *openapi.Disk) error {
 		disk.Prov, disk.Size, err = vdisk_detect_raw_prov(disk.Path)
 	case "qcow2":
 		disk.Prov, disk.Size, err = vdisk_detect_qcow2_prov(disk.Path)
+		if (err == nil) {
+			err = vdisk_detect_backing(disk)
+		}
 	default:
 		return errors.New("invalid Disk Path")
 	}
 	return err
 }
 
+/*
+ * detect whether a qcow2 disk is an overlay of a template, and if so make it a linked disk.
+ * Other backing files are left for libvirt to probe, but they are not protected by any lease.
+ */
+func vdisk_detect_backing(disk *openapi.Disk) error {
+	var (
+		err error
+		info qinfo
+	)
+	info, err = vdisk_info(disk.Path)
+	if (err != nil) {
+		return err
+	}
+	if (info.Backing == "") {
+		return nil
+	}
+	if (vmdef.Validate_template_path(info.Backing) == "") {
+		logger.Log("disk %s is backed by %s, which is not a template", disk.Path, info.Backing)
+		return nil
+	}
+	disk.Prov = openapi.DISK_PROV_LINKED
+	disk.Base = info.Backing
+	return nil
+}
+
+type qinfo struct {
+	VirtualSize uint64 `json:"virtual-size"`
+	Format      string `json:"format"`
+	Backing     string `json:"full-backing-filename"`
+}
+
+/* get the image information of the file at path, also if it is in use (f.e. a template) */
+func vdisk_info(path string) (qinfo, error) {
+	var (
+		err error
+		info qinfo
+		output []byte
+	)
+	args := []string { "info", "-U", "--output=json", path }
+	logger.Debug("qemu-img %v", args)
+	var cmd *exec.Cmd = exec.Command("/usr/bin/qemu-img", args...)
+	output, err = cmd.Output()
+	if (err != nil) {
+		logger.Log("qemu-img info %s failed: %s", path, err.Error())
+		return info, err
+	}
+	err = json.NewDecoder(bytes.NewReader(output)).Decode(&info)
+	return info, err
+}
+
 func vdisk_detect_raw_prov(path string) (openapi.DiskProvMode, int32, error) {
 	var (
 		err error
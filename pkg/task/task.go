@@ -0,0 +1,264 @@
+/*
+ * Copyright (c) 2024-2026 SUSE LLC
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, see
+ * <https://www.gnu.org/licenses/>
+ */
+package task
+
+import (
+	"fmt"
+	"sync"
+
+	g_uuid "github.com/google/uuid"
+
+	"suse.com/virtx/pkg/model"
+	"suse.com/virtx/pkg/machine"
+	"suse.com/virtx/pkg/logger"
+	"suse.com/virtx/pkg/ts"
+	"suse.com/virtx/pkg/events"
+)
+
+const (
+	TASK_MSG_MAX = 200          /* keep the TaskEvent small enough for a serf user event */
+	TASK_EXPIRE = 3600          /* seconds after which finished tasks are forgotten */
+	TASK_EVENT_CH_SIZE = 64
+)
+
+/*
+ * TaskEvent: task state report, sent to all hosts in the cluster whenever a task
+ * starts, makes progress or finishes, so that all hosts can list all tasks.
+ */
+type TaskEvent struct {
+	Uuid string
+	Vm string
+	Host string
+	Op openapi.Operation
+	State openapi.OperationState
+	Progress int16              /* percentage 0-100 */
+	Ts int64                    /* started */
+	Te int64                    /* ended, 0 if still running */
+	Msg string
+}
+
+/* Task: handle passed to the function running the task, to report progress */
+type Task struct {
+	uuid string
+}
+
+var tasks = struct {
+	m sync.RWMutex
+	events map[string]TaskEvent
+	event_ch chan TaskEvent
+}{
+	events: make(map[string]TaskEvent),
+	event_ch: make(chan TaskEvent, TASK_EVENT_CH_SIZE),
+}
+
+func Get_event_channel() chan TaskEvent {
+	return tasks.event_ch
+}
+
+/*
+ * Start a new task for operation op on vm (can be ""), running fn in a new goroutine.
+ * Returns the task uuid. The task fails if fn returns an error, with the error as message;
+ * otherwise it completes, with the optional message returned by fn.
+ */
+func Start(op openapi.Operation, vm string, fn func(t *Task) (string, error)) (string, error) {
+	var (
+		err error
+		g g_uuid.UUID
+		e TaskEvent
+	)
+	g, err = g_uuid.NewRandom()
+	if (err != nil) {
+		return "", err
+	}
+	e = TaskEvent{
+		Uuid: g.String(),
+		Vm: vm,
+		Host: machine.Uuid(),
+		Op: op,
+		State: openapi.OPERATION_STARTED,
+		Ts: ts.Now(),
+	}
+	task_publish(&e)
+	go func() {
+		var (
+			err error
+			msg string
+			t Task = Task{ uuid: e.Uuid }
+		)
+		msg, err = fn(&t)
+		if (err != nil) {
+			logger.Log("task %s %s %s failed: %s", e.Uuid, op.String(), vm, err.Error())
+			t.finish(openapi.OPERATION_FAILED, err.Error())
+		} else {
+			logger.Debug("task %s %s %s completed", e.Uuid, op.String(), vm)
+			t.finish(openapi.OPERATION_COMPLETED, msg)
+		}
+	}()
+	return e.Uuid, nil
+}
+
+func (t *Task) Uuid() string {
+	return t.uuid
+}
+
+/* report the progress of the task, as a percentage 0-100 */
+func (t *Task) Set_progress(progress int16) {
+	var (
+		e TaskEvent
+		present bool
+	)
+	if (progress < 0) {
+		progress = 0
+	} else if (progress > 100) {
+		progress = 100
+	}
+	tasks.m.RLock()
+	e, present = tasks.events[t.uuid]
+	tasks.m.RUnlock()
+	if (!present || e.Progress == progress) {
+		return
+	}
+	e.Progress = progress
+	task_publish(&e)
+}
+
+func (t *Task) finish(state openapi.OperationState, msg string) {
+	var (
+		e TaskEvent
+		present bool
+	)
+	tasks.m.RLock()
+	e, present = tasks.events[t.uuid]
+	tasks.m.RUnlock()
+	if (!present) {
+		return
+	}
+	e.State = state
+	e.Te = ts.Now()
+	e.Msg = msg
+	if (state == openapi.OPERATION_COMPLETED) {
+		e.Progress = 100
+	}
+	task_publish(&e)
+}
+
+/* update the local registry with the event, and send it to the other hosts */
+func task_publish(e *TaskEvent) {
+	if (len(e.Msg) > TASK_MSG_MAX) {
+		e.Msg = e.Msg[:TASK_MSG_MAX]
+	}
+	Update(e)
+	select {
+	case tasks.event_ch <- *e:
+	default:
+		logger.Log("task %s: event channel full, event not sent", e.Uuid)
+	}
+}
+
+/*
+ * Update the task registry with an event received from any host.
+ * Events can arrive out of order, so never go back from a finished state
+ * to started, and never decrease the progress.
+ */
+func Update(e *TaskEvent) {
+	tasks.m.Lock()
+	defer tasks.m.Unlock()
+	var (
+		old TaskEvent
+		present bool
+	)
+	task_expire()
+	old, present = tasks.events[e.Uuid]
+	if (present) {
+		if (old == *e) {
+			return /* our own events come back from serf */
+		}
+		if (old.State != openapi.OPERATION_STARTED && e.State == openapi.OPERATION_STARTED) {
+			return
+		}
+		if (old.State == e.State && old.Progress > e.Progress) {
+			return
+		}
+	}
+	tasks.events[e.Uuid] = *e
+	events.Publish(events.Event{
+		Type: events.TYPE_TASK,
+		Host: e.Host,
+		Vm: e.Vm,
+		Data: task_to_model(e),
+	})
+}
+
+/* forget finished tasks older than TASK_EXPIRE. Called with tasks.m held. */
+func task_expire() {
+	var (
+		uuid string
+		e TaskEvent
+	)
+	for uuid, e = range tasks.events {
+		if (e.Te != 0 && ts.Since(e.Te).Seconds() > TASK_EXPIRE) {
+			delete(tasks.events, uuid)
+		}
+	}
+}
+
+func task_to_model(e *TaskEvent) openapi.Task {
+	return openapi.Task{
+		Uuid: e.Uuid,
+		Op: e.Op.String(),
+		Vm: e.Vm,
+		Host: e.Host,
+		Ts: e.Ts,
+		Te: e.Te,
+		Status: e.State.String(),
+		Progress: e.Progress,
+		Msg: e.Msg,
+	}
+}
+
+func Get(uuid string) (openapi.Task, error) {
+	tasks.m.RLock()
+	defer tasks.m.RUnlock()
+	var (
+		e TaskEvent
+		present bool
+	)
+	e, present = tasks.events[uuid]
+	if (!present) {
+		return openapi.Task{}, fmt.Errorf("task: no such task %s", uuid)
+	}
+	return task_to_model(&e), nil
+}
+
+/* list all tasks in the cluster, or only the tasks of the specified vm */
+func Search(vm string) openapi.TaskList {
+	tasks.m.RLock()
+	defer tasks.m.RUnlock()
+	var (
+		e TaskEvent
+		list openapi.TaskList
+	)
+	list.Items = make([]openapi.Task, 0)
+	for _, e = range tasks.events {
+		if (vm != "" && e.Vm != vm) {
+			continue
+		}
+		list.Items = append(list.Items, task_to_model(&e))
+	}
+	return list
+}
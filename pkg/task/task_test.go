@@ -0,0 +1,111 @@
+/*
+ * Copyright (c) 2024-2026 SUSE LLC
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, see
+ * <https://www.gnu.org/licenses/>
+ */
+package task
+
+import (
+	"encoding/binary"
+	"strings"
+	"testing"
+
+	"suse.com/virtx/pkg/model"
+	"suse.com/virtx/pkg/encoding/sbinary"
+	"suse.com/virtx/pkg/ts"
+)
+
+const test_uuid = "11111111-2222-3333-4444-555555555555"
+
+func test_reset() {
+	tasks.m.Lock()
+	tasks.events = make(map[string]TaskEvent)
+	tasks.m.Unlock()
+}
+
+func Test_update_out_of_order(t *testing.T) {
+	test_reset()
+	Update(&TaskEvent{Uuid: test_uuid, State: openapi.OPERATION_STARTED, Progress: 50, Ts: ts.Now()})
+	Update(&TaskEvent{Uuid: test_uuid, State: openapi.OPERATION_STARTED, Progress: 20, Ts: ts.Now()})
+	task, err := Get(test_uuid)
+	if (err != nil) {
+		t.Fatalf("Get: %v", err)
+	}
+	if (task.Progress != 50) {
+		t.Errorf("progress should not decrease: got %d", task.Progress)
+	}
+	Update(&TaskEvent{Uuid: test_uuid, State: openapi.OPERATION_COMPLETED, Progress: 100, Te: ts.Now()})
+	Update(&TaskEvent{Uuid: test_uuid, State: openapi.OPERATION_STARTED, Progress: 70})
+	task, _ = Get(test_uuid)
+	if (task.Status != "completed" || task.Progress != 100) {
+		t.Errorf("finished task should not restart: got %s %d", task.Status, task.Progress)
+	}
+}
+
+func Test_update_expire(t *testing.T) {
+	test_reset()
+	old := ts.Now() - (TASK_EXPIRE + 10) * 1000
+	Update(&TaskEvent{Uuid: test_uuid, State: openapi.OPERATION_FAILED, Ts: old, Te: old})
+	Update(&TaskEvent{Uuid: "other", State: openapi.OPERATION_STARTED, Ts: ts.Now()})
+	_, err := Get(test_uuid)
+	if (err == nil) {
+		t.Errorf("expired task should have been removed")
+	}
+	_, err = Get("other")
+	if (err != nil) {
+		t.Errorf("running task should be present: %v", err)
+	}
+}
+
+func Test_search(t *testing.T) {
+	test_reset()
+	Update(&TaskEvent{Uuid: "t1", Vm: "vm1", Op: openapi.OpVmCreate, State: openapi.OPERATION_STARTED})
+	Update(&TaskEvent{Uuid: "t2", Vm: "vm2", Op: openapi.OpVmMigrate, State: openapi.OPERATION_STARTED})
+	Update(&TaskEvent{Uuid: "t3", Vm: "vm1", Op: openapi.OpVmDelete, State: openapi.OPERATION_STARTED})
+	if (len(Search("").Items) != 3) {
+		t.Errorf("Search(\"\"): expected 3 tasks")
+	}
+	list := Search("vm1")
+	if (len(list.Items) != 2) {
+		t.Fatalf("Search(vm1): expected 2 tasks, got %d", len(list.Items))
+	}
+	for _, item := range list.Items {
+		if (item.Vm != "vm1") {
+			t.Errorf("Search(vm1): unexpected vm %s", item.Vm)
+		}
+	}
+}
+
+/* the largest possible event must fit in a serf user event */
+func Test_task_event_size(t *testing.T) {
+	var buf [1024]byte
+	e := TaskEvent{
+		Uuid: test_uuid, Vm: test_uuid, Host: test_uuid,
+		Op: openapi.OpVmMigrate, State: openapi.OPERATION_FAILED, Progress: 100,
+		Ts: ts.Now(), Te: ts.Now(), Msg: strings.Repeat("x", TASK_MSG_MAX),
+	}
+	size, err := sbinary.Encode(buf[:], binary.LittleEndian, &e)
+	if (err != nil) {
+		t.Fatalf("Encode: %v", err)
+	}
+	if (size > 512) {
+		t.Errorf("encoded TaskEvent too large: %d", size)
+	}
+	var d TaskEvent
+	_, err = sbinary.Decode(buf[:size], binary.LittleEndian, &d)
+	if (err != nil || d != e) {
+		t.Errorf("Decode: roundtrip mismatch (%v)", err)
+	}
+}
@@ -0,0 +1,115 @@
+/*
+ * Copyright (c) 2024-2026 SUSE LLC
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, see
+ * <https://www.gnu.org/licenses/>
+ */
+package virtx
+
+import (
+	"net/http"
+	"encoding/json"
+	"strings"
+	"time"
+	"fmt"
+
+	"suse.com/virtx/pkg/httpx"
+	"suse.com/virtx/pkg/logger"
+	"suse.com/virtx/pkg/events"
+)
+
+const (
+	EVENT_KEEPALIVE_INTERVAL = 15 /* seconds between keepalive comments on idle streams */
+)
+
+/*
+ * stream inventory and task changes as Server-Sent Events.
+ * Each event has the event type (host, vm, task) and the JSON data of the
+ * HostListItem, VmListItem or Task. Optional query parameters:
+ * vm=UUID, host=UUID, type=TYPE[,TYPE...]
+ *
+ * No proxying is needed, since all hosts receive all the changes.
+ * If the client does not keep up, the stream is closed, and the client
+ * should reconnect and fetch the current state again.
+ */
+func event_list(w http.ResponseWriter, r *http.Request) {
+	var (
+		err error
+		f events.Filter
+		s *events.Subscriber
+		e events.Event
+		ok bool
+		data []byte
+		flusher http.Flusher
+		ticker *time.Ticker
+		query = r.URL.Query()
+	)
+	_, err = httpx.Decode_request_body(r, nil)
+	if (err != nil) {
+		logger.Log(err.Error())
+		httpx.Do_error_field(w, http.StatusBadRequest, httpx.ERR_INVALID_BODY, "failed to decode body: " + err.Error(), httpx.Error_field(err))
+		return
+	}
+	flusher, ok = w.(http.Flusher)
+	if (!ok) {
+		httpx.Do_error(w, http.StatusInternalServerError, httpx.ERR_INTERNAL, "streaming not supported")
+		return
+	}
+	f.Vm = query.Get("vm")
+	f.Host = query.Get("host")
+	if (query.Get("type") != "") {
+		f.Types = strings.Split(query.Get("type"), ",")
+		for _, t := range f.Types {
+			if (t != events.TYPE_HOST && t != events.TYPE_VM && t != events.TYPE_TASK) {
+				httpx.Do_error_field(w, http.StatusBadRequest, httpx.ERR_INVALID_PARAMETER, "invalid event type", "type")
+				return
+			}
+		}
+	}
+	s = events.Subscribe(f)
+	defer s.Unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ticker = time.NewTicker(EVENT_KEEPALIVE_INTERVAL * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-service.shutdown:
+			return
+		case <-ticker.C:
+			_, err = fmt.Fprintf(w, ": keepalive\n\n")
+		case e, ok = <-s.C:
+			if (!ok) {
+				logger.Log("event stream: client %s could not keep up, closing", r.RemoteAddr)
+				return
+			}
+			data, err = json.Marshal(e.Data)
+			if (err != nil) {
+				logger.Log("failed to encode JSON")
+				continue
+			}
+			_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Type, data)
+		}
+		if (err != nil) {
+			return
+		}
+		flusher.Flush()
+	}
+}
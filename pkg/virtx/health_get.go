@@ -0,0 +1,174 @@
+/*
+ * Copyright (c) 2024-2026 SUSE LLC
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, see
+ * <https://www.gnu.org/licenses/>
+ */
+package virtx
+
+import (
+	"net/http"
+	"encoding/json"
+	"bytes"
+	"errors"
+	"os"
+	"time"
+	"fmt"
+
+	"suse.com/virtx/pkg/httpx"
+	"suse.com/virtx/pkg/logger"
+	"suse.com/virtx/pkg/model"
+	"suse.com/virtx/pkg/hypervisor"
+	"suse.com/virtx/pkg/serfcomm"
+	"suse.com/virtx/pkg/lockman"
+	"suse.com/virtx/pkg/machine"
+	"suse.com/virtx/pkg/ts"
+	. "suse.com/virtx/pkg/constants"
+)
+
+const (
+	HEALTH_CHECK_TIMEOUT = 5        /* seconds, a hung NFS mount must not hang the probe */
+	HEALTH_BROADCAST_MAX_AGE = 45   /* seconds, 3 times the system info loop interval */
+)
+
+type health_check struct {
+	name string
+	fn func() error
+}
+
+/* liveness: the daemon is connected and still broadcasting its system info */
+var health_live = []health_check{
+	{ "libvirt", health_libvirt },
+	{ "serf", health_serf },
+	{ "broadcast", health_broadcast },
+}
+
+/* readiness: additionally the host can safely run VMs from the shared storage */
+var health_ready = []health_check{
+	{ "libvirt", health_libvirt },
+	{ "serf", health_serf },
+	{ "broadcast", health_broadcast },
+	{ "lockspace", lockman.Check_lockspace },
+	{ "storage", health_storage },
+}
+
+func health_libvirt() error {
+	if (!hypervisor.Is_connected()) {
+		return errors.New("not connected")
+	}
+	return nil
+}
+
+func health_serf() error {
+	if (!serfcomm.Is_connected()) {
+		return errors.New("RPC client not connected")
+	}
+	return nil
+}
+
+func health_broadcast() error {
+	var last int64 = serfcomm.Last_broadcast()
+	if (last == 0) {
+		return errors.New("no system info broadcast yet")
+	}
+	if (ts.Since(last) > HEALTH_BROADCAST_MAX_AGE * time.Second) {
+		return fmt.Errorf("last system info broadcast %s ago", ts.Since(last).Round(time.Second))
+	}
+	return nil
+}
+
+func health_storage() error {
+	var err error
+	_, err = os.Stat(REG_DIR + machine.Uuid())
+	if (err != nil) {
+		return err
+	}
+	_, err = os.ReadDir(DS_DIR)
+	return err
+}
+
+/* run the checks in parallel, each with a timeout */
+func health_run(checks []health_check) openapi.Health {
+	var (
+		h openapi.Health
+		results []chan error
+		timeout <-chan time.Time
+	)
+	h.Status = "ok"
+	h.Host = machine.Uuid()
+	results = make([]chan error, len(checks))
+	for i := range checks {
+		results[i] = make(chan error, 1)
+		go func(fn func() error, ch chan error) {
+			ch <- fn()
+		}(checks[i].fn, results[i])
+	}
+	timeout = time.After(HEALTH_CHECK_TIMEOUT * time.Second)
+	for i := range checks {
+		var (
+			err error
+			c openapi.HealthCheck = openapi.HealthCheck{ Name: checks[i].name }
+		)
+		select {
+		case err = <-results[i]:
+		case <-timeout:
+			err = errors.New("timeout")
+		}
+		if (err != nil) {
+			c.Detail = err.Error()
+			h.Status = "fail"
+		} else {
+			c.Ok = true
+		}
+		h.Checks = append(h.Checks, c)
+	}
+	return h
+}
+
+func health_respond(w http.ResponseWriter, r *http.Request, checks []health_check) {
+	var (
+		err error
+		h openapi.Health
+		buf bytes.Buffer
+		status int = http.StatusOK
+	)
+	_, err = httpx.Decode_request_body(r, nil)
+	if (err != nil) {
+		logger.Log(err.Error())
+		httpx.Do_error_field(w, http.StatusBadRequest, httpx.ERR_INVALID_BODY, "failed to decode body: " + err.Error(), httpx.Error_field(err))
+		return
+	}
+	h = health_run(checks)
+	if (h.Status != "ok") {
+		status = http.StatusServiceUnavailable
+	}
+	err = json.NewEncoder(&buf).Encode(&h)
+	if (err != nil) {
+		logger.Log("failed to encode JSON")
+		httpx.Do_error(w, http.StatusInternalServerError, httpx.ERR_INTERNAL, "Failed to encode JSON")
+		return
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	httpx.Do_response(w, status, &buf)
+}
+
+/* GET /healthz: 200 if this virtxd is alive, 503 otherwise */
+func health_get(w http.ResponseWriter, r *http.Request) {
+	health_respond(w, r, health_live)
+}
+
+/* GET /readyz: 200 if this host can serve requests and run VMs, 503 otherwise */
+func ready_get(w http.ResponseWriter, r *http.Request) {
+	health_respond(w, r, health_ready)
+}
@@ -40,17 +40,17 @@ func host_get(w http.ResponseWriter, r *http.Request) {
 	vr, err = httpx.Decode_request_body(r, nil)
 	if (err != nil) {
 		logger.Log(err.Error())
-		http.Error(w, "failed to decode body", http.StatusBadRequest)
+		httpx.Do_error_field(w, http.StatusBadRequest, httpx.ERR_INVALID_BODY, "failed to decode body: " + err.Error(), httpx.Error_field(err))
 		return
 	}
 	uuid = r.PathValue("uuid")
 	if (uuid == "") {
-		http.Error(w, "could not get uuid", http.StatusBadRequest)
+		httpx.Do_error(w, http.StatusBadRequest, httpx.ERR_INVALID_UUID, "could not get uuid")
 		return
 	}
 	hostinfo, err = inventory.Get_hostinfo(uuid)
 	if (err != nil) {
-		http.Error(w, "unknown uuid", http.StatusNotFound)
+		httpx.Do_error(w, http.StatusNotFound, httpx.ERR_NOT_FOUND, "unknown uuid")
 		return
 	}
 	if (http_host_is_remote(hostinfo.Uuid)) {
@@ -61,7 +61,7 @@ func host_get(w http.ResponseWriter, r *http.Request) {
 	err = json.NewEncoder(&buf).Encode(&host)
 	if (err != nil) {
 		logger.Log("failed to encode JSON")
-		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+		httpx.Do_error(w, http.StatusInternalServerError, httpx.ERR_INTERNAL, "Failed to encode JSON")
 		return
 	}
 	httpx.Do_response(w, http.StatusOK, &buf)
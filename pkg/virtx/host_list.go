@@ -34,19 +34,33 @@ func host_list(w http.ResponseWriter, r *http.Request) {
 		o openapi.HostListOptions
 		host_list openapi.HostList
 		buf bytes.Buffer
+		out any
 	)
 	_, err = httpx.Decode_request_body(r, &o)
 	if (err != nil) {
 		logger.Log(err.Error())
-		http.Error(w, "failed to decode body", http.StatusBadRequest)
+		httpx.Do_error_field(w, http.StatusBadRequest, httpx.ERR_INVALID_BODY, "failed to decode body: " + err.Error(), httpx.Error_field(err))
 		return
 	}
 	/* filters: [name, cpuarch, cpudef, hoststate, memoryavailable] */
-	host_list = inventory.Search_hosts(o.Filter)
-	err = json.NewEncoder(&buf).Encode(&host_list)
+	host_list, err = inventory.List_hosts(&o)
+	if (err != nil) {
+		logger.Log("inventory.List_hosts: %s", err.Error())
+		httpx.Do_error(w, http.StatusBadRequest, httpx.ERR_INVALID_PARAMETER, "invalid list options: " + err.Error())
+		return
+	}
+	out = &host_list
+	if (len(o.Select) > 0) {
+		out, err = inventory.Select_fields(out, openapi.HostListFields{}, o.Select)
+		if (err != nil) {
+			httpx.Do_error_field(w, http.StatusBadRequest, httpx.ERR_INVALID_PARAMETER, "invalid list options: " + err.Error(), "select")
+			return
+		}
+	}
+	err = json.NewEncoder(&buf).Encode(out)
 	if (err != nil) {
 		logger.Log("failed to encode JSON")
-		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+		httpx.Do_error(w, http.StatusInternalServerError, httpx.ERR_INTERNAL, "Failed to encode JSON")
 		return
 	}
 	httpx.Do_response(w, http.StatusOK, &buf)
@@ -19,11 +19,20 @@ package virtx
 
 import (
 	"net/http"
+	"encoding/json"
+	"bytes"
+	"io"
 
+	"suse.com/virtx/pkg/apispec"
+	"suse.com/virtx/pkg/auth"
+	"suse.com/virtx/pkg/logger"
 	"suse.com/virtx/pkg/machine"
 	"suse.com/virtx/pkg/httpx"
 	"suse.com/virtx/pkg/model"
 	"suse.com/virtx/pkg/inventory"
+	"suse.com/virtx/pkg/task"
+	"suse.com/virtx/pkg/websocket"
+	. "suse.com/virtx/pkg/constants"
 )
 
 func http_host_is_remote(uuid string) bool {
@@ -37,12 +46,168 @@ func http_proxy_request(uuid string, w http.ResponseWriter, vr httpx.Request) {
 	)
 	hostinfo, err = inventory.Get_hostinfo(uuid)
 	if (err != nil) {
-		http.Error(w, "unknown host", http.StatusServiceUnavailable)
+		httpx.Do_error(w, http.StatusServiceUnavailable, httpx.ERR_HOST_UNAVAILABLE, "unknown host")
 		return
 	}
 	if (hostinfo.Cstate != openapi.CSTATE_ACTIVE) {
-		http.Error(w, "inactive host", http.StatusServiceUnavailable)
+		httpx.Do_error(w, http.StatusServiceUnavailable, httpx.ERR_HOST_UNAVAILABLE, "inactive host")
 		return
 	}
 	httpx.Proxy_request(hostinfo.Name, w, vr)
 }
+
+/* as http_proxy_request, but for requests upgrading the connection (WebSocket) */
+func http_proxy_upgrade(uuid string, w http.ResponseWriter, vr httpx.Request) {
+	var (
+		hostinfo inventory.HostInfo
+		err error
+	)
+	hostinfo, err = inventory.Get_hostinfo(uuid)
+	if (err != nil) {
+		httpx.Do_error(w, http.StatusServiceUnavailable, httpx.ERR_HOST_UNAVAILABLE, "unknown host")
+		return
+	}
+	if (hostinfo.Cstate != openapi.CSTATE_ACTIVE) {
+		httpx.Do_error(w, http.StatusServiceUnavailable, httpx.ERR_HOST_UNAVAILABLE, "inactive host")
+		return
+	}
+	httpx.Proxy_upgrade(hostinfo.Name, w, vr)
+}
+
+/*
+ * copy data between the WebSocket ws and rwc in both directions, until either
+ * side is closed or the service shuts down. Both are closed on return.
+ */
+func http_websocket_bridge(ws *websocket.Conn, rwc io.ReadWriteCloser) {
+	var (
+		out_done chan struct{} = make(chan struct{})
+		in_done chan struct{} = make(chan struct{})
+	)
+	go func() {
+		io.Copy(ws, rwc)
+		close(out_done)
+	}()
+	go func() {
+		io.Copy(rwc, ws)
+		close(in_done)
+	}()
+	select {
+	case <-out_done:
+	case <-in_done:
+	case <-service.shutdown:
+	}
+	/* closing both sides terminates the other copy */
+	rwc.Close()
+	ws.Close()
+}
+
+/* respond 202 Accepted with the task just started, and its location */
+func http_task_accepted(w http.ResponseWriter, uuid string) {
+	var (
+		err error
+		t openapi.Task
+		buf bytes.Buffer
+	)
+	t, err = task.Get(uuid)
+	if (err != nil) {
+		logger.Log("http_task_accepted: %s", err.Error())
+		httpx.Do_error(w, http.StatusInternalServerError, httpx.ERR_INTERNAL, "failed to get task")
+		return
+	}
+	err = json.NewEncoder(&buf).Encode(&t)
+	if (err != nil) {
+		logger.Log("failed to encode JSON")
+		httpx.Do_error(w, http.StatusInternalServerError, httpx.ERR_INTERNAL, "Failed to encode JSON")
+		return
+	}
+	w.Header().Set("Location", "/tasks/" + uuid)
+	httpx.Do_response(w, http.StatusAccepted, &buf)
+}
+
+/*
+ * authenticate and authorize the request for op before running the handler.
+ * This happens before any proxying, so the host receiving the request from the
+ * client makes the decision. A request proxied from another host (X-VirtX-Loop)
+ * is trusted without further checks if the peer presented a verified certificate
+ * for one of the hosts in the cluster. Otherwise the forwarded credentials are checked
+ * again, with the same result since the tokens are shared by all hosts.
+ */
+func http_auth(op openapi.Operation, handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			err error
+			id auth.Identity
+		)
+		if (!auth.Enabled()) {
+			handler(w, r)
+			return
+		}
+		if (r.Header.Get("X-VirtX-Loop") != "" && http_peer_is_host(r)) {
+			handler(w, r)
+			return
+		}
+		id, err = auth.Authenticate(r)
+		if (err != nil) {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			httpx.Do_error(w, http.StatusUnauthorized, httpx.ERR_UNAUTHORIZED, err.Error())
+			return
+		}
+		if (!auth.Authorized(id, op)) {
+			logger.Log("%s (%s) is not authorized for %s", id.Name, id.Role.String(), op.String())
+			httpx.Do_error(w, http.StatusForbidden, httpx.ERR_FORBIDDEN, "forbidden")
+			return
+		}
+		logger.Debug("%s (%s) authorized for %s", id.Name, id.Role.String(), op.String())
+		handler(w, r)
+	}
+}
+
+/* check whether the peer presented a verified TLS certificate for a cluster host */
+func http_peer_is_host(r *http.Request) bool {
+	var (
+		list openapi.HostList
+		item openapi.HostListItem
+	)
+	if (r.TLS == nil || len(r.TLS.VerifiedChains) < 1) {
+		return false
+	}
+	list = inventory.Search_hosts(openapi.HostListFields{})
+	for _, item = range list.Items {
+		if (r.TLS.PeerCertificates[0].VerifyHostname(item.Fields.Name) == nil) {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+ * validate the request body against the OpenAPI document before any handler runs,
+ * rejecting unknown fields and invalid enum values.
+ * Bodies which are missing or too large are left to httpx.Decode_request_body to report.
+ */
+func http_validate(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			err error
+			body []byte
+		)
+		if (r.ContentLength <= 0 || r.ContentLength >= HTTP_MAX_BODY_LEN) {
+			handler.ServeHTTP(w, r)
+			return
+		}
+		body, err = io.ReadAll(io.LimitReader(r.Body, HTTP_MAX_BODY_LEN))
+		r.Body.Close()
+		if (err != nil) {
+			httpx.Do_error(w, http.StatusBadRequest, httpx.ERR_INVALID_BODY, "failed to read body")
+			return
+		}
+		err = apispec.Validate(r.Method, r.URL.Path, r.Header.Get("Content-Type"), body)
+		if (err != nil) {
+			logger.Log("%s %s: %s", r.Method, r.URL.Path, err.Error())
+			httpx.Do_error_field(w, http.StatusBadRequest, httpx.ERR_INVALID_BODY, "invalid body: " + err.Error(), apispec.Error_field(err))
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		handler.ServeHTTP(w, r)
+	})
+}
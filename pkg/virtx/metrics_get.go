@@ -0,0 +1,153 @@
+/*
+ * Copyright (c) 2024-2026 SUSE LLC
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, see
+ * <https://www.gnu.org/licenses/>
+ */
+package virtx
+
+import (
+	"net/http"
+	"bytes"
+	"strconv"
+
+	"suse.com/virtx/pkg/httpx"
+	"suse.com/virtx/pkg/logger"
+	"suse.com/virtx/pkg/model"
+	"suse.com/virtx/pkg/hypervisor"
+	"suse.com/virtx/pkg/machine"
+	"suse.com/virtx/pkg/metrics"
+)
+
+const (
+	METRICS_CONTENT_TYPE = "text/plain; version=0.0.4; charset=utf-8"
+)
+
+/*
+ * GET /metrics returns the resources of this host and the statistics of the VMs
+ * running on it in Prometheus text format. Each host must be scraped separately.
+ */
+func metrics_get(w http.ResponseWriter, r *http.Request) {
+	var (
+		err error
+		buf bytes.Buffer
+		host openapi.Host
+		vms []hypervisor.VmStats
+	)
+	_, err = httpx.Decode_request_body(r, nil)
+	if (err != nil) {
+		logger.Log(err.Error())
+		httpx.Do_error_field(w, http.StatusBadRequest, httpx.ERR_INVALID_BODY, "failed to decode body: " + err.Error(), httpx.Error_field(err))
+		return
+	}
+	host = hypervisor.Get_host()
+	vms = hypervisor.Get_vms_stats()
+	metrics_write_host(&buf, &host)
+	metrics_write_vms(&buf, vms)
+	metrics.Write_counters(&buf)
+
+	w.Header().Set("Content-Type", METRICS_CONTENT_TYPE)
+	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
+
+func metrics_write_host(buf *bytes.Buffer, host *openapi.Host) {
+	var (
+		labels []metrics.Label = []metrics.Label{
+			{ Name: "host", Value: machine.Uuid() },
+			{ Name: "name", Value: host.Def.Name },
+		}
+		resources = []struct {
+			name string
+			unit string
+			help string
+			res *openapi.Hostresource
+		}{
+			{ "cpu", "mhz", "Host CPU", &host.Resources.Cpu },
+			{ "memory", "mib", "Host memory", &host.Resources.Memory },
+			{ "hp", "mib", "Host hugepages memory", &host.Resources.Hp },
+		}
+	)
+	for _, res := range resources {
+		var values = []struct {
+			name string
+			help string
+			value int32
+		}{
+			{ "total", "total", res.res.Total },
+			{ "used", "currently in use", res.res.Used },
+			{ "free", "currently free", res.res.Free },
+			{ "usedos", "used by the OS and the host stack", res.res.Usedos },
+			{ "reservedvms", "reserved for the VMs on this host", res.res.Reservedvms },
+			{ "usedvms", "used by the VMs on this host", res.res.Usedvms },
+			{ "availablevms", "available for other VMs", res.res.Availablevms },
+		}
+		for _, v := range values {
+			var name string = "virtx_host_" + res.name + "_" + v.name + "_" + res.unit
+			metrics.Write_header(buf, name, res.help + " " + v.help + ".", "gauge")
+			metrics.Write_sample(buf, name, labels, int64(v.value))
+		}
+	}
+}
+
+func metrics_write_vms(buf *bytes.Buffer, vms []hypervisor.VmStats) {
+	var (
+		labels = make([][]metrics.Label, len(vms))
+		values = []struct {
+			name string
+			help string
+			value func(s *openapi.Vmstats) int64
+		}{
+			{ "virtx_vm_cpu_utilization_percent", "VM cpu utilization, 100 = 1 cpu fully utilized.",
+				func(s *openapi.Vmstats) int64 { return int64(s.CpuUtilization) } },
+			{ "virtx_vm_cpu_used_mhz", "VM approximate MHz used.",
+				func(s *openapi.Vmstats) int64 { return int64(s.MhzUsed) } },
+			{ "virtx_vm_memory_capacity_mib", "VM memory capacity.",
+				func(s *openapi.Vmstats) int64 { return s.MemoryCapacity } },
+			{ "virtx_vm_memory_used_mib", "VM QEMU resident set size.",
+				func(s *openapi.Vmstats) int64 { return s.MemoryUsed } },
+			{ "virtx_vm_disk_capacity_mib", "VM virtual disk capacity.",
+				func(s *openapi.Vmstats) int64 { return s.DiskCapacity } },
+			{ "virtx_vm_disk_allocation_mib", "VM disk allocation.",
+				func(s *openapi.Vmstats) int64 { return s.DiskAllocation } },
+			{ "virtx_vm_disk_physical_mib", "VM disk physical size.",
+				func(s *openapi.Vmstats) int64 { return s.DiskPhysical } },
+			{ "virtx_vm_net_rx_kibps", "VM network receive bandwidth in KiB/s.",
+				func(s *openapi.Vmstats) int64 { return int64(s.NetRxBw) } },
+			{ "virtx_vm_net_tx_kibps", "VM network transmit bandwidth in KiB/s.",
+				func(s *openapi.Vmstats) int64 { return int64(s.NetTxBw) } },
+		}
+	)
+	for i := range vms {
+		labels[i] = []metrics.Label{
+			{ Name: "host", Value: vms[i].Host },
+			{ Name: "vm", Value: vms[i].Uuid },
+			{ Name: "name", Value: vms[i].Name },
+		}
+		for _, custom := range vms[i].Custom {
+			if (custom.Name == "") {
+				continue
+			}
+			/* the first of the custom fields mapping to the same label name wins */
+			labels[i] = metrics.Add_label(labels[i], metrics.Label_name("custom_", custom.Name), custom.Value)
+		}
+	}
+	for _, v := range values {
+		metrics.Write_header(buf, v.name, v.help, "gauge")
+		for i := range vms {
+			metrics.Write_sample(buf, v.name, labels[i], v.value(&vms[i].Stats))
+		}
+	}
+}
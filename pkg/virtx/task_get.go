@@ -0,0 +1,67 @@
+/*
+ * Copyright (c) 2024-2026 SUSE LLC
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, see
+ * <https://www.gnu.org/licenses/>
+ */
+package virtx
+
+import (
+	"net/http"
+	"encoding/json"
+	"bytes"
+
+	"suse.com/virtx/pkg/httpx"
+	"suse.com/virtx/pkg/logger"
+	"suse.com/virtx/pkg/model"
+	"suse.com/virtx/pkg/task"
+)
+
+func task_get(w http.ResponseWriter, r *http.Request) {
+	var (
+		err error
+		uuid string
+		t openapi.Task
+		buf bytes.Buffer
+		vr httpx.Request
+	)
+	vr, err = httpx.Decode_request_body(r, nil)
+	if (err != nil) {
+		logger.Log(err.Error())
+		httpx.Do_error_field(w, http.StatusBadRequest, httpx.ERR_INVALID_BODY, "failed to decode body: " + err.Error(), httpx.Error_field(err))
+		return
+	}
+	uuid = r.PathValue("uuid")
+	if (uuid == "") {
+		httpx.Do_error(w, http.StatusBadRequest, httpx.ERR_INVALID_UUID, "could not get uuid")
+		return
+	}
+	t, err = task.Get(uuid)
+	if (err != nil) {
+		httpx.Do_error(w, http.StatusNotFound, httpx.ERR_NOT_FOUND, "unknown uuid")
+		return
+	}
+	/* the host running the task has the most up to date information */
+	if (http_host_is_remote(t.Host)) {
+		http_proxy_request(t.Host, w, vr)
+		return
+	}
+	err = json.NewEncoder(&buf).Encode(&t)
+	if (err != nil) {
+		logger.Log("failed to encode JSON")
+		httpx.Do_error(w, http.StatusInternalServerError, httpx.ERR_INTERNAL, "Failed to encode JSON")
+		return
+	}
+	httpx.Do_response(w, http.StatusOK, &buf)
+}
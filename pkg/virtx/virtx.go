@@ -28,11 +28,13 @@ import (
 
 	"suse.com/virtx/pkg/logger"
 	"suse.com/virtx/pkg/httpx"
+	"suse.com/virtx/pkg/model"
 )
 
 type Service struct {
 	servemux *http.ServeMux
 	server http.Server
+	shutdown chan struct{}      /* closed on Shutdown, to terminate event streams */
 }
 
 var service Service
@@ -46,32 +48,67 @@ func Init() {
 	g_uuid.DisableRandPool()
 
 	var servemux *http.ServeMux = http.NewServeMux()
-	servemux.HandleFunc("POST /vms", vm_create)
-	servemux.HandleFunc("GET /vms", vm_list)
-	servemux.HandleFunc("PUT /vms/{uuid}", vm_update)
-	servemux.HandleFunc("GET /vms/{uuid}", vm_get)
-	servemux.HandleFunc("DELETE /vms/{uuid}", vm_delete)
-	servemux.HandleFunc("GET /vms/{uuid}/runstate", vm_runstate_get)
-	servemux.HandleFunc("POST /vms/{uuid}/runstate/boot", vm_boot)
-	servemux.HandleFunc("DELETE /vms/{uuid}/runstate/boot", vm_shutdown)
-	servemux.HandleFunc("POST /vms/{uuid}/runstate/pause", vm_pause)
-	servemux.HandleFunc("DELETE /vms/{uuid}/runstate/pause", vm_resume)
-	servemux.HandleFunc("POST /vms/{uuid}/runstate/migrate", vm_migrate)
-	servemux.HandleFunc("GET /vms/{uuid}/runstate/migrate", vm_migrate_get)
-	servemux.HandleFunc("DELETE /vms/{uuid}/runstate/migrate", vm_migrate_abort)
-	servemux.HandleFunc("PUT /vms/{uuid}/register", vm_register)
-
-	servemux.HandleFunc("GET /hosts", host_list)
-	servemux.HandleFunc("GET /hosts/{uuid}", host_get)
+	servemux.HandleFunc("POST /vms", http_auth(openapi.OpVmCreate, vm_create))
+	servemux.HandleFunc("GET /vms", http_auth(openapi.OpVmList, vm_list))
+	servemux.HandleFunc("PUT /vms/{uuid}", http_auth(openapi.OpVmUpdate, vm_update))
+	servemux.HandleFunc("PATCH /vms/{uuid}", http_auth(openapi.OpVmPatch, vm_patch))
+	servemux.HandleFunc("GET /vms/{uuid}", http_auth(openapi.OpVmGet, vm_get))
+	servemux.HandleFunc("DELETE /vms/{uuid}", http_auth(openapi.OpVmDelete, vm_delete))
+	servemux.HandleFunc("GET /vms/{uuid}/runstate", http_auth(openapi.OpVmRunstateGet, vm_runstate_get))
+	servemux.HandleFunc("POST /vms/{uuid}/runstate/boot", http_auth(openapi.OpVmBoot, vm_boot))
+	servemux.HandleFunc("DELETE /vms/{uuid}/runstate/boot", http_auth(openapi.OpVmShutdown, vm_shutdown))
+	servemux.HandleFunc("POST /vms/{uuid}/runstate/reboot", http_auth(openapi.OpVmReboot, vm_reboot))
+	servemux.HandleFunc("POST /vms/{uuid}/runstate/pause", http_auth(openapi.OpVmPause, vm_pause))
+	servemux.HandleFunc("DELETE /vms/{uuid}/runstate/pause", http_auth(openapi.OpVmResume, vm_resume))
+	servemux.HandleFunc("POST /vms/{uuid}/runstate/migrate", http_auth(openapi.OpVmMigrate, vm_migrate))
+	servemux.HandleFunc("GET /vms/{uuid}/runstate/migrate", http_auth(openapi.OpVmMigrateGet, vm_migrate_get))
+	servemux.HandleFunc("DELETE /vms/{uuid}/runstate/migrate", http_auth(openapi.OpVmMigrateAbort, vm_migrate_abort))
+	servemux.HandleFunc("PUT /vms/{uuid}/register", http_auth(openapi.OpVmRegister, vm_register))
+	servemux.HandleFunc("POST /vms/{uuid}/clone", http_auth(openapi.OpVmClone, vm_clone))
+	servemux.HandleFunc("POST /vms/{uuid}/template", http_auth(openapi.OpVmTemplateCreate, vm_template_create))
+	servemux.HandleFunc("POST /vms/{uuid}/disks", http_auth(openapi.OpVmDiskAttach, vm_disk_attach))
+	servemux.HandleFunc("DELETE /vms/{uuid}/disks", http_auth(openapi.OpVmDiskDetach, vm_disk_detach))
+	servemux.HandleFunc("POST /vms/{uuid}/nets", http_auth(openapi.OpVmNetAttach, vm_net_attach))
+	servemux.HandleFunc("DELETE /vms/{uuid}/nets", http_auth(openapi.OpVmNetDetach, vm_net_detach))
+	servemux.HandleFunc("POST /vms/{uuid}/resize", http_auth(openapi.OpVmResize, vm_resize))
+	servemux.HandleFunc("GET /vms/{uuid}/console", http_auth(openapi.OpVmConsole, vm_console))
+	servemux.HandleFunc("GET /vms/{uuid}/vnc", http_auth(openapi.OpVmVnc, vm_vnc))
+	servemux.HandleFunc("GET /vms/{uuid}/screenshot", http_auth(openapi.OpVmScreenshot, vm_screenshot))
+	servemux.HandleFunc("GET /vms/{uuid}/snapshots", http_auth(openapi.OpVmSnapshotList, vm_snapshot_list))
+	servemux.HandleFunc("POST /vms/{uuid}/snapshots", http_auth(openapi.OpVmSnapshotCreate, vm_snapshot_create))
+	servemux.HandleFunc("DELETE /vms/{uuid}/snapshots/{name}", http_auth(openapi.OpVmSnapshotDelete, vm_snapshot_delete))
+	servemux.HandleFunc("POST /vms/{uuid}/snapshots/{name}/revert", http_auth(openapi.OpVmSnapshotRevert, vm_snapshot_revert))
+	servemux.HandleFunc("GET /vms/{uuid}/tasks", http_auth(openapi.OpTaskList, vm_task_list))
+
+	servemux.HandleFunc("GET /hosts", http_auth(openapi.OpHostList, host_list))
+	servemux.HandleFunc("GET /hosts/{uuid}", http_auth(openapi.OpHostGet, host_get))
+
+	servemux.HandleFunc("GET /templates", http_auth(openapi.OpTemplateList, template_list))
+
+	servemux.HandleFunc("GET /tasks", http_auth(openapi.OpTaskList, task_list))
+	servemux.HandleFunc("GET /tasks/{uuid}", http_auth(openapi.OpTaskGet, task_get))
+
+	servemux.HandleFunc("GET /events", http_auth(openapi.OpEventList, event_list))
+	servemux.HandleFunc("GET /metrics", http_auth(openapi.OpMetricsGet, metrics_get))
+
+	/* health probes and the API description do not require authentication */
+	servemux.HandleFunc("GET /healthz", health_get)
+	servemux.HandleFunc("GET /readyz", ready_get)
+	servemux.HandleFunc("GET /openapi.json", openapi_get)
 
 	service = Service{
 		servemux: servemux,
 		server: http.Server{
 			Addr: ":8080",
-			Handler: servemux,
+			Handler: http_validate(servemux),
 			ReadTimeout: httpx.SERVER_TIMEOUT * time.Second,
+			TLSConfig: httpx.Tls_config(),
 		},
+		shutdown: make(chan struct{}),
 	}
+	service.server.RegisterOnShutdown(func() {
+		close(service.shutdown)
+	})
 }
 
 func New_uuid() string {
@@ -113,9 +150,14 @@ func Start_listening() <-chan error {
 			return
 		}
 
-		logger.Debug("HTTP service listening on %s", listener.Addr().String())
-
-		err = service.server.Serve(listener)
+		if (service.server.TLSConfig != nil) {
+			logger.Debug("HTTPS service listening on %s", listener.Addr().String())
+			/* certificates are already loaded in TLSConfig */
+			err = service.server.ServeTLS(listener, "", "")
+		} else {
+			logger.Debug("HTTP service listening on %s", listener.Addr().String())
+			err = service.server.Serve(listener)
+		}
 		if (err != nil && !errors.Is(err, http.ErrServerClosed)) {
 			err_ch <- err
 			return
@@ -0,0 +1,178 @@
+/*
+ * Copyright (c) 2024-2026 SUSE LLC
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, see
+ * <https://www.gnu.org/licenses/>
+ */
+package virtx
+
+import (
+	"net/http"
+	"errors"
+
+	"suse.com/virtx/pkg/hypervisor"
+	"suse.com/virtx/pkg/logger"
+	"suse.com/virtx/pkg/model"
+	"suse.com/virtx/pkg/vmdef"
+	"suse.com/virtx/pkg/vmreg"
+	"suse.com/virtx/pkg/httpx"
+	"suse.com/virtx/pkg/inventory"
+	"suse.com/virtx/pkg/storage"
+	"suse.com/virtx/pkg/task"
+)
+
+/*
+ * create a new VM as a copy of a powered off VM. The managed disks are copied
+ * (with their current state, if they have snapshots), the unmanaged disks are shared.
+ * The request runs on the host of the source VM, which owns its definition.
+ * The new VM is defined on o.Host, by default on the host of the source VM.
+ */
+func vm_clone(w http.ResponseWriter, r *http.Request) {
+	var (
+		err error
+		o openapi.VmCloneOptions
+		uuid, new_uuid, xml, tuuid string
+		vminfo inventory.VmInfo
+		hostinfo inventory.HostInfo
+		src, vm openapi.Vmdef
+		sources []string
+		vr httpx.Request
+	)
+	vr, err = httpx.Decode_request_body(r, &o)
+	if (err != nil) {
+		logger.Log(err.Error())
+		httpx.Do_error_field(w, http.StatusBadRequest, httpx.ERR_INVALID_BODY, "failed to decode body: " + err.Error(), httpx.Error_field(err))
+		return
+	}
+	uuid = r.PathValue("uuid")
+	if (uuid == "") {
+		httpx.Do_error(w, http.StatusBadRequest, httpx.ERR_INVALID_UUID, "could not get uuid")
+		return
+	}
+	vminfo, err = inventory.Get_vminfo(uuid)
+	if (err != nil) {
+		httpx.Do_error(w, http.StatusNotFound, httpx.ERR_NOT_FOUND, "unknown uuid")
+		return
+	}
+	if (http_host_is_remote(vminfo.Host)) { /* need to proxy */
+		http_proxy_request(vminfo.Host, w, vr)
+		return
+	}
+	if (vminfo.Runstate != openapi.RUNSTATE_POWEROFF && vminfo.Runstate != openapi.RUNSTATE_CRASHED) {
+		httpx.Do_error(w, http.StatusUnprocessableEntity, httpx.ERR_INVALID_STATE, "VM is not powered off")
+		return
+	}
+	if (http_host_is_remote(o.Host)) {
+		/* the new VM is defined on the target host directly from here */
+		hostinfo, err = inventory.Get_hostinfo(o.Host)
+		if (err != nil) {
+			httpx.Do_error_field(w, http.StatusUnprocessableEntity, httpx.ERR_INVALID_PARAMETER, "unknown host", "host")
+			return
+		}
+		if (hostinfo.Cstate != openapi.CSTATE_ACTIVE) {
+			httpx.Do_error(w, http.StatusServiceUnavailable, httpx.ERR_HOST_UNAVAILABLE, "inactive host")
+			return
+		}
+	}
+	new_uuid = New_uuid()
+	if (new_uuid == "") {
+		httpx.Do_error(w, http.StatusInternalServerError, httpx.ERR_INTERNAL, "failed to generate uuid")
+		return
+	}
+	if (!vm_change_begin(uuid)) {
+		httpx.Do_error(w, http.StatusConflict, httpx.ERR_CONFLICT, "VM definition is being changed")
+		return
+	}
+	/* read the configuration of the source VM from the registry on disk */
+	xml, err = vmreg.Load(vminfo.Host, uuid)
+	if (err != nil) {
+		vm_change_end(uuid)
+		logger.Log("vmreg.Load(%s, %s) failed: %s", vminfo.Host, uuid, err.Error())
+		httpx.Do_error(w, http.StatusInternalServerError, httpx.ERR_INTERNAL, "could not Load VM")
+		return
+	}
+	err = vmdef.From_xml(&src, xml)
+	if (err != nil) {
+		vm_change_end(uuid)
+		logger.Log("vmdef.From_xml failed: %s", err.Error())
+		httpx.Do_error(w, http.StatusInternalServerError, httpx.ERR_INTERNAL, "invalid VM data")
+		return
+	}
+	vm, err = vmdef.Clone(&src, o.Name, new_uuid)
+	if (err != nil) {
+		vm_change_end(uuid)
+		httpx.Do_error(w, http.StatusUnprocessableEntity, httpx.ERR_INVALID_PARAMETER, "could not clone VM: " + err.Error())
+		return
+	}
+	err = vmdef.Validate(&vm)
+	if (err != nil) {
+		vm_change_end(uuid)
+		logger.Log("vmdef.Validate failed: %s", err.Error())
+		httpx.Do_error_field(w, http.StatusBadRequest, httpx.ERR_INVALID_PARAMETER, "invalid parameters: " + err.Error(), vmdef.Error_field(err))
+		return
+	}
+	sources = vmdef.Clone_sources(&src, xml)
+	tuuid, err = task.Start(openapi.OpVmClone, new_uuid, func(t *task.Task) (string, error) {
+		defer vm_change_end(uuid)
+		return vm_clone_task(&vm, &src, sources, uuid, new_uuid, o.Host, hostinfo.Name)
+	})
+	if (err != nil) {
+		vm_change_end(uuid)
+		logger.Log("task.Start failed: %s", err.Error())
+		httpx.Do_error(w, http.StatusInternalServerError, httpx.ERR_INTERNAL, "failed to start task")
+		return
+	}
+	http_task_accepted(w, tuuid)
+}
+
+/*
+ * copy the storage and define the new VM, copying large disks can take a long time.
+ * If host is remote, the new VM is defined on it, reached at hostname.
+ */
+func vm_clone_task(vm *openapi.Vmdef, src *openapi.Vmdef, sources []string, src_uuid string, uuid string, host string, hostname string) (string, error) {
+	var (
+		err error
+		xml string
+		vminfo inventory.VmInfo
+		created storage.CreatedResources
+	)
+	/* the VM may have been started before the task, the disk leases only protect the copy itself */
+	vminfo, err = inventory.Get_vminfo(src_uuid)
+	if (err != nil) {
+		return "", errors.New("source VM not found")
+	}
+	if (vminfo.Runstate != openapi.RUNSTATE_POWEROFF && vminfo.Runstate != openapi.RUNSTATE_CRASHED) {
+		return "", errors.New("VM is not powered off")
+	}
+	created, err = storage.Clone(vm, src, sources, src_uuid, uuid)
+	if (err != nil) {
+		storage.Rollback(created, uuid)
+		return "", errors.New("storage copy failed: " + err.Error())
+	}
+	xml, err = vmdef.To_xml(vm, uuid)
+	if (err != nil) {
+		storage.Rollback(created, uuid)
+		return "", errors.New("invalid parameters: " + err.Error())
+	}
+	if (http_host_is_remote(host)) {
+		err = hypervisor.Define_domain_remote(hostname, host, xml, uuid)
+	} else {
+		err = hypervisor.Define_domain(xml, uuid)
+	}
+	if (err != nil) {
+		storage.Rollback(created, uuid)
+		return "", errors.New("could not define VM: " + err.Error())
+	}
+	return "", nil
+}
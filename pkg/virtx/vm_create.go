@@ -19,8 +19,7 @@ package virtx
 
 import (
 	"net/http"
-	"encoding/json"
-	"bytes"
+	"errors"
 
 	"suse.com/virtx/pkg/hypervisor"
 	"suse.com/virtx/pkg/logger"
@@ -28,20 +27,20 @@ import (
 	"suse.com/virtx/pkg/vmdef"
 	"suse.com/virtx/pkg/httpx"
 	"suse.com/virtx/pkg/storage"
+	"suse.com/virtx/pkg/task"
 )
 
 func vm_create(w http.ResponseWriter, r *http.Request) {
 	var (
 		err error
 		o openapi.VmCreateOptions
-		xml, uuid string
+		uuid, tuuid string
 		vr httpx.Request
-		created storage.CreatedResources
 	)
 	vr, err = httpx.Decode_request_body(r, &o)
 	if (err != nil) {
 		logger.Log(err.Error())
-		http.Error(w, "failed to decode body", http.StatusBadRequest)
+		httpx.Do_error_field(w, http.StatusBadRequest, httpx.ERR_INVALID_BODY, "failed to decode body: " + err.Error(), httpx.Error_field(err))
 		return
 	}
 	if (http_host_is_remote(o.Host)) { /* need to proxy */
@@ -52,41 +51,47 @@ func vm_create(w http.ResponseWriter, r *http.Request) {
 	err = vmdef.Validate(&o.Vmdef)
 	if (err != nil) {
 		logger.Log("vmdef.Validate failed: %s", err.Error())
-		http.Error(w, "invalid parameters", http.StatusBadRequest)
+		httpx.Do_error_field(w, http.StatusBadRequest, httpx.ERR_INVALID_PARAMETER, "invalid parameters: " + err.Error(), vmdef.Error_field(err))
 		return
 	}
 	uuid = New_uuid()
 	if (uuid == "") {
-		http.Error(w, "failed", http.StatusInternalServerError)
+		httpx.Do_error(w, http.StatusInternalServerError, httpx.ERR_INTERNAL, "failed to generate uuid")
 		return
 	}
-	/* create storage if needed, can change o.Vmdef in some cases */
-	created, err = storage.Create(&o.Vmdef, nil, uuid)
+	tuuid, err = task.Start(openapi.OpVmCreate, uuid, func(t *task.Task) (string, error) {
+		return vm_create_task(&o.Vmdef, uuid)
+	})
 	if (err != nil) {
-		logger.Log("vm_create_storage failed: %s", err.Error())
-		storage.Rollback(created, uuid)
-		http.Error(w, "storage creation failed", http.StatusInsufficientStorage)
+		logger.Log("task.Start failed: %s", err.Error())
+		httpx.Do_error(w, http.StatusInternalServerError, httpx.ERR_INTERNAL, "failed to start task")
 		return
 	}
-	xml, err = vmdef.To_xml(&o.Vmdef, uuid)
+	http_task_accepted(w, tuuid)
+}
+
+/* create the storage and define the VM, can take a long time for thick provisioning */
+func vm_create_task(vm *openapi.Vmdef, uuid string) (string, error) {
+	var (
+		err error
+		xml string
+		created storage.CreatedResources
+	)
+	/* create storage if needed, can change vm in some cases */
+	created, err = storage.Create(vm, nil, uuid)
 	if (err != nil) {
-		logger.Log("vmdef.To_xml failed: %s", err.Error())
 		storage.Rollback(created, uuid)
-		http.Error(w, "invalid parameters", http.StatusBadRequest)
-		return
+		return "", errors.New("storage creation failed: " + err.Error())
 	}
-	err = hypervisor.Define_domain(xml, uuid)
+	xml, err = vmdef.To_xml(vm, uuid)
 	if (err != nil) {
-		logger.Log("hypervisor.Define_domain failed: %s", err.Error())
 		storage.Rollback(created, uuid)
-		http.Error(w, "could not define VM", http.StatusFailedDependency)
-		return
+		return "", errors.New("invalid parameters: " + err.Error())
 	}
-	var buf bytes.Buffer
-	err = json.NewEncoder(&buf).Encode(&uuid)
+	err = hypervisor.Define_domain(xml, uuid)
 	if (err != nil) {
-		logger.Log("failed to encode JSON")
-		w.Header().Set("Warning", `299 VirtX "failed to encode JSON"`)
+		storage.Rollback(created, uuid)
+		return "", errors.New("could not define VM: " + err.Error())
 	}
-	httpx.Do_response(w, http.StatusCreated, &buf)
+	return "", nil
 }
@@ -19,20 +19,24 @@ package virtx
 
 import (
 	"net/http"
+	"errors"
+
 	"suse.com/virtx/pkg/hypervisor"
 	"suse.com/virtx/pkg/logger"
 	"suse.com/virtx/pkg/model"
 	"suse.com/virtx/pkg/vmdef"
+	"suse.com/virtx/pkg/vmreg"
 	"suse.com/virtx/pkg/httpx"
 	"suse.com/virtx/pkg/inventory"
 	"suse.com/virtx/pkg/storage"
+	"suse.com/virtx/pkg/task"
 )
 
 func vm_delete(w http.ResponseWriter, r *http.Request) {
 	var (
 		err error
 		o openapi.VmDeleteOptions
-		uuid, xml string
+		uuid, xml, tuuid string
 		vminfo inventory.VmInfo
 		vm openapi.Vmdef
 		vr httpx.Request
@@ -41,17 +45,17 @@ func vm_delete(w http.ResponseWriter, r *http.Request) {
 	vr, err = httpx.Decode_request_body(r, &o)
 	if (err != nil) {
 		logger.Log(err.Error())
-		http.Error(w, "failed to decode body", http.StatusBadRequest)
+		httpx.Do_error_field(w, http.StatusBadRequest, httpx.ERR_INVALID_BODY, "failed to decode body: " + err.Error(), httpx.Error_field(err))
 		return
 	}
 	uuid = r.PathValue("uuid")
 	if (uuid == "") {
-		http.Error(w, "could not get uuid", http.StatusBadRequest)
+		httpx.Do_error(w, http.StatusBadRequest, httpx.ERR_INVALID_UUID, "could not get uuid")
 		return
 	}
 	vminfo, err = inventory.Get_vminfo(uuid)
 	if (err != nil) {
-		http.Error(w, "unknown uuid", http.StatusNotFound)
+		httpx.Do_error(w, http.StatusNotFound, httpx.ERR_NOT_FOUND, "unknown uuid")
 		return
 	}
 	if (http_host_is_remote(vminfo.Host)) {
@@ -60,32 +64,62 @@ func vm_delete(w http.ResponseWriter, r *http.Request) {
 	}
 	state = vminfo.Runstate
 	if (state != openapi.RUNSTATE_POWEROFF && state != openapi.RUNSTATE_CRASHED) {
-		http.Error(w, "VM is not powered off", http.StatusUnprocessableEntity)
+		httpx.Do_error(w, http.StatusUnprocessableEntity, httpx.ERR_INVALID_STATE, "VM is not powered off")
+		return
+	}
+	if (!vm_change_begin(uuid)) {
+		httpx.Do_error(w, http.StatusConflict, httpx.ERR_CONFLICT, "VM definition is being changed")
+		return
+	}
+	xml, err = vmreg.Load(vminfo.Host, uuid)
+	if (err != nil) {
+		vm_change_end(uuid)
+		logger.Log("vmreg.Load(%s, %s) failed: %s", vminfo.Host, uuid, err.Error())
+		httpx.Do_error(w, http.StatusInternalServerError, httpx.ERR_INTERNAL, "could not Load VM")
+		return
+	}
+	if (!http_check_etag(w, r, xml)) {
+		vm_change_end(uuid)
 		return
 	}
 	xml, err = hypervisor.Dumpxml(uuid)
 	if (err != nil) {
+		vm_change_end(uuid)
 		logger.Log("hypervisor.Dumpxml failed: %s", err.Error())
-		http.Error(w, "could not Get VM XML", http.StatusFailedDependency)
+		httpx.Do_error(w, http.StatusFailedDependency, httpx.ERR_HYPERVISOR, "could not Get VM XML: " + err.Error())
 		return
 	}
 	err = vmdef.From_xml(&vm, xml)
 	if (err != nil) {
+		vm_change_end(uuid)
 		logger.Log("vmdef.From_xml failed: %s", err.Error())
-		http.Error(w, "invalid VM data", http.StatusInternalServerError)
+		httpx.Do_error(w, http.StatusInternalServerError, httpx.ERR_INTERNAL, "invalid VM data")
 		return
 	}
-	err = hypervisor.Delete_domain(uuid)
+	tuuid, err = task.Start(openapi.OpVmDelete, uuid, func(t *task.Task) (string, error) {
+		defer vm_change_end(uuid)
+		return vm_delete_task(&vm, uuid, o.Deletestorage)
+	})
 	if (err != nil) {
-		logger.Log("Delete_domain failed: %s", err.Error())
-		http.Error(w, "Failed to delete VM", http.StatusFailedDependency)
+		vm_change_end(uuid)
+		logger.Log("task.Start failed: %s", err.Error())
+		httpx.Do_error(w, http.StatusInternalServerError, httpx.ERR_INTERNAL, "failed to start task")
 		return
 	}
-	err = storage.Delete(&vm, nil, uuid, o.Deletestorage)
+	http_task_accepted(w, tuuid)
+}
+
+/* delete the VM and its storage, wiping LUNs can take a long time */
+func vm_delete_task(vm *openapi.Vmdef, uuid string, deletestorage bool) (string, error) {
+	var err error
+	err = hypervisor.Delete_domain(uuid)
+	if (err != nil) {
+		return "", errors.New("failed to delete VM: " + err.Error())
+	}
+	err = storage.Delete(vm, nil, uuid, deletestorage)
 	if (err != nil) {
-		w.Header().Set("Warning", `299 VirtX "some resources could not be deleted"`)
-		httpx.Do_response(w, http.StatusOK, nil)
-	} else {
-		httpx.Do_response(w, http.StatusNoContent, nil)
+		/* complete with a warning */
+		return "some resources could not be deleted", nil
 	}
+	return "", nil
 }
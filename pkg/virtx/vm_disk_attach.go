@@ -0,0 +1,149 @@
+/*
+ * Copyright (c) 2024-2026 SUSE LLC
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, see
+ * <https://www.gnu.org/licenses/>
+ */
+package virtx
+
+import (
+	"net/http"
+	"errors"
+
+	"suse.com/virtx/pkg/hypervisor"
+	"suse.com/virtx/pkg/logger"
+	"suse.com/virtx/pkg/model"
+	"suse.com/virtx/pkg/vmreg"
+	"suse.com/virtx/pkg/vmdef"
+	"suse.com/virtx/pkg/httpx"
+	"suse.com/virtx/pkg/inventory"
+	"suse.com/virtx/pkg/storage"
+	"suse.com/virtx/pkg/task"
+)
+
+/*
+ * attach a disk to a VM, also while running, without redefining it.
+ * Managed disks are provisioned first, and their storage is removed again if attaching fails.
+ */
+func vm_disk_attach(w http.ResponseWriter, r *http.Request) {
+	var (
+		err error
+		disk openapi.Disk
+		old, vm openapi.Vmdef
+		xml, uuid, tuuid string
+		vminfo inventory.VmInfo
+		vr httpx.Request
+		state openapi.Vmrunstate
+	)
+	vr, err = httpx.Decode_request_body(r, &disk)
+	if (err != nil) {
+		logger.Log(err.Error())
+		httpx.Do_error_field(w, http.StatusBadRequest, httpx.ERR_INVALID_BODY, "failed to decode body: " + err.Error(), httpx.Error_field(err))
+		return
+	}
+	uuid = r.PathValue("uuid")
+	if (uuid == "") {
+		httpx.Do_error(w, http.StatusBadRequest, httpx.ERR_INVALID_UUID, "could not get uuid")
+		return
+	}
+	vminfo, err = inventory.Get_vminfo(uuid)
+	if (err != nil) {
+		httpx.Do_error(w, http.StatusNotFound, httpx.ERR_NOT_FOUND, "unknown uuid")
+		return
+	}
+	if (http_host_is_remote(vminfo.Host)) {
+		http_proxy_request(vminfo.Host, w, vr)
+		return
+	}
+	state = vminfo.Runstate
+	if (state != openapi.RUNSTATE_POWEROFF && state != openapi.RUNSTATE_CRASHED &&
+		state != openapi.RUNSTATE_RUNNING && state != openapi.RUNSTATE_PAUSED) {
+		httpx.Do_error(w, http.StatusUnprocessableEntity, httpx.ERR_INVALID_STATE, "VM is not powered off or running")
+		return
+	}
+	if ((state == openapi.RUNSTATE_RUNNING || state == openapi.RUNSTATE_PAUSED) &&
+		disk.Bus != openapi.BUS_VIRTIO_BLK && disk.Bus != openapi.BUS_VIRTIO_SCSI) {
+		httpx.Do_error_field(w, http.StatusUnprocessableEntity, httpx.ERR_INVALID_PARAMETER, "only virtio buses support hot-plug", "bus")
+		return
+	}
+	if (!vm_change_begin(uuid)) {
+		httpx.Do_error(w, http.StatusConflict, httpx.ERR_CONFLICT, "VM definition is being changed")
+		return
+	}
+	xml, err = vmreg.Load(vminfo.Host, uuid)
+	if (err != nil) {
+		vm_change_end(uuid)
+		logger.Log("vmreg.Load(%s, %s) failed: %s", vminfo.Host, uuid, err.Error())
+		httpx.Do_error(w, http.StatusInternalServerError, httpx.ERR_INTERNAL, "could not Load VM")
+		return
+	}
+	err = vmdef.From_xml(&old, xml)
+	if (err != nil) {
+		vm_change_end(uuid)
+		logger.Log("vmdef.From_xml failed: %s", err.Error())
+		httpx.Do_error(w, http.StatusInternalServerError, httpx.ERR_INTERNAL, "invalid VM data")
+		return
+	}
+	if (vmdef.Has_snapshots(xml)) {
+		/* the new disk would not be part of the existing snapshots */
+		vm_change_end(uuid)
+		httpx.Do_error(w, http.StatusUnprocessableEntity, httpx.ERR_INVALID_STATE, "VM has snapshots")
+		return
+	}
+	if (vmdef.Has_path(&old, disk.Path)) {
+		vm_change_end(uuid)
+		httpx.Do_error_field(w, http.StatusConflict, httpx.ERR_CONFLICT, "disk already attached", "path")
+		return
+	}
+	vm = old
+	vm.Disks = append(append([]openapi.Disk{}, old.Disks...), disk)
+	err = vmdef.Validate(&vm)
+	if (err != nil) {
+		vm_change_end(uuid)
+		logger.Log("vmdef.Validate failed: %s", err.Error())
+		httpx.Do_error_field(w, http.StatusBadRequest, httpx.ERR_INVALID_PARAMETER, "invalid parameters: " + err.Error(), vmdef.Error_field(err))
+		return
+	}
+	tuuid, err = task.Start(openapi.OpVmDiskAttach, uuid, func(t *task.Task) (string, error) {
+		defer vm_change_end(uuid)
+		return vm_disk_attach_task(&vm, &old, uuid)
+	})
+	if (err != nil) {
+		vm_change_end(uuid)
+		logger.Log("task.Start failed: %s", err.Error())
+		httpx.Do_error(w, http.StatusInternalServerError, httpx.ERR_INTERNAL, "failed to start task")
+		return
+	}
+	http_task_accepted(w, tuuid)
+}
+
+/* create the storage of the new disk, which is the last of vm, and attach it */
+func vm_disk_attach_task(vm *openapi.Vmdef, old *openapi.Vmdef, uuid string) (string, error) {
+	var (
+		err error
+		created storage.CreatedResources
+	)
+	/* create the storage and the lease, can change the disk (f.e. detected provisioning) */
+	created, err = storage.Create(vm, old, uuid)
+	if (err != nil) {
+		storage.Rollback(created, uuid)
+		return "", errors.New("storage creation failed: " + err.Error())
+	}
+	err = hypervisor.Attach_disk(uuid, &vm.Disks[len(vm.Disks) - 1])
+	if (err != nil) {
+		storage.Rollback(created, uuid)
+		return "", errors.New("could not attach disk: " + err.Error())
+	}
+	return "", nil
+}
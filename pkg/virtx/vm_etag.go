@@ -0,0 +1,77 @@
+/*
+ * Copyright (c) 2024-2026 SUSE LLC
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, see
+ * <https://www.gnu.org/licenses/>
+ */
+package virtx
+
+import (
+	"net/http"
+	"sync"
+
+	"suse.com/virtx/pkg/httpx"
+	"suse.com/virtx/pkg/vmreg"
+)
+
+/*
+ * VMs with a change of definition (update, delete) in progress on this host.
+ * The If-Match check and the change need to be atomic, otherwise two clients
+ * holding the same ETag could both pass the check and overwrite each other.
+ */
+var vm_changes = struct {
+	m sync.Mutex
+	busy map[string]bool
+}{
+	busy: make(map[string]bool),
+}
+
+/* start a change of the VM definition, false if another one is in progress */
+func vm_change_begin(uuid string) bool {
+	vm_changes.m.Lock()
+	defer vm_changes.m.Unlock()
+	if (vm_changes.busy[uuid]) {
+		return false
+	}
+	vm_changes.busy[uuid] = true
+	return true
+}
+
+func vm_change_end(uuid string) {
+	vm_changes.m.Lock()
+	defer vm_changes.m.Unlock()
+	delete(vm_changes.busy, uuid)
+}
+
+/*
+ * check the If-Match header of the request against the registered VM definition xml.
+ * Replies 428 if the header is missing, and 412 with the current ETag if it does not match,
+ * so that the client can re-read the VM and retry.
+ */
+func http_check_etag(w http.ResponseWriter, r *http.Request, xml string) bool {
+	var (
+		if_match string = r.Header.Get("If-Match")
+		etag string = vmreg.Etag(xml)
+	)
+	if (if_match == "") {
+		httpx.Do_error(w, http.StatusPreconditionRequired, httpx.ERR_PRECONDITION_REQUIRED, "If-Match header required")
+		return false
+	}
+	if (!httpx.Etag_match(if_match, etag)) {
+		w.Header().Set("ETag", etag)
+		httpx.Do_error(w, http.StatusPreconditionFailed, httpx.ERR_PRECONDITION_FAILED, "VM definition has changed")
+		return false
+	}
+	return true
+}
@@ -25,6 +25,7 @@ import (
 	"suse.com/virtx/pkg/logger"
 	"suse.com/virtx/pkg/model"
 	"suse.com/virtx/pkg/vmdef"
+	"suse.com/virtx/pkg/vmreg"
 	"suse.com/virtx/pkg/httpx"
 	"suse.com/virtx/pkg/inventory"
 )
@@ -32,7 +33,7 @@ import (
 func vm_get(w http.ResponseWriter, r *http.Request) {
 	var (
 		err error
-		uuid, xml string
+		uuid, xml, etag string
 		vminfo inventory.VmInfo
 		vm openapi.Vm
 		buf bytes.Buffer
@@ -41,35 +42,44 @@ func vm_get(w http.ResponseWriter, r *http.Request) {
 	vr, err = httpx.Decode_request_body(r, nil)
 	if (err != nil) {
 		logger.Log(err.Error())
-		http.Error(w, "failed to decode body", http.StatusBadRequest)
+		httpx.Do_error_field(w, http.StatusBadRequest, httpx.ERR_INVALID_BODY, "failed to decode body: " + err.Error(), httpx.Error_field(err))
 		return
 	}
 	uuid = r.PathValue("uuid")
 	if (uuid == "") {
-		http.Error(w, "could not get uuid", http.StatusBadRequest)
+		httpx.Do_error(w, http.StatusBadRequest, httpx.ERR_INVALID_UUID, "could not get uuid")
 		return
 	}
 	vminfo, err = inventory.Get_vminfo(uuid)
 	if (err != nil) {
-		http.Error(w, "unknown uuid", http.StatusNotFound)
+		httpx.Do_error(w, http.StatusNotFound, httpx.ERR_NOT_FOUND, "unknown uuid")
 		return
 	}
 	if (http_host_is_remote(vminfo.Host)) {
 		http_proxy_request(vminfo.Host, w, vr)
 		return
 	}
+	/* the ETag is the hash of the registered definition, to be passed as If-Match to update or delete */
+	xml, err = vmreg.Load(vminfo.Host, uuid)
+	if (err != nil) {
+		logger.Log("vmreg.Load(%s, %s) failed: %s", vminfo.Host, uuid, err.Error())
+		httpx.Do_error(w, http.StatusInternalServerError, httpx.ERR_INTERNAL, "could not Load VM")
+		return
+	}
+	etag = vmreg.Etag(xml)
 	xml, err = hypervisor.Dumpxml(uuid)
 	if (err != nil) {
 		logger.Log("hypervisor.Dumpxml failed: %s", err.Error())
-		http.Error(w, "could not get VM", http.StatusFailedDependency)
+		httpx.Do_error(w, http.StatusFailedDependency, httpx.ERR_HYPERVISOR, "could not get VM: " + err.Error())
 		return
 	}
 	err = vmdef.From_xml(&vm.Def, xml)
 	if (err != nil) {
 		logger.Log("vmdef.From_xml failed: %s", err.Error())
-		http.Error(w, "invalid VM data", http.StatusInternalServerError)
+		httpx.Do_error(w, http.StatusInternalServerError, httpx.ERR_INTERNAL, "invalid VM data")
 		return
 	}
+	vmdef.Hide_secrets(&vm.Def)
 	vm.Uuid = uuid
 	vm.Runinfo.Runstate = vminfo.Runstate
 	vm.Runinfo.Host = vminfo.Host
@@ -77,14 +87,15 @@ func vm_get(w http.ResponseWriter, r *http.Request) {
 	vm.Stats, err = hypervisor.Get_Vmstats(uuid)
 	if (err != nil) {
 		logger.Log("hypervisor.Get_Vmstats failed: %s", err.Error())
-		http.Error(w, "could not get VM stats", http.StatusNotFound)
+		httpx.Do_error(w, http.StatusNotFound, httpx.ERR_HYPERVISOR, "could not get VM stats: " + err.Error())
 		return
 	}
 	err = json.NewEncoder(&buf).Encode(&vm)
 	if (err != nil) {
 		logger.Log("failed to encode JSON")
-		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+		httpx.Do_error(w, http.StatusInternalServerError, httpx.ERR_INTERNAL, "Failed to encode JSON")
 		return
 	}
+	w.Header().Set("ETag", etag)
 	httpx.Do_response(w, http.StatusOK, &buf)
 }
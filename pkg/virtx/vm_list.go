@@ -34,18 +34,39 @@ func vm_list(w http.ResponseWriter, r *http.Request) {
 		o openapi.VmListOptions
 		vm_list openapi.VmList
 		buf bytes.Buffer
+		out any
 	)
 	_, err = httpx.Decode_request_body(r, &o)
 	if (err != nil) {
 		logger.Log(err.Error())
-		http.Error(w, "failed to decode body", http.StatusBadRequest)
+		httpx.Do_error_field(w, http.StatusBadRequest, httpx.ERR_INVALID_BODY, "failed to decode body: " + err.Error(), httpx.Error_field(err))
 		return
 	}
-	vm_list = inventory.Search_vms(o.Filter)
-	err = json.NewEncoder(&buf).Encode(&vm_list)
+	if (o.Filter.Address != "") {
+		_, err = inventory.Parse_address_filter(o.Filter.Address)
+		if (err != nil) {
+			httpx.Do_error_field(w, http.StatusBadRequest, httpx.ERR_INVALID_PARAMETER, "invalid list options: " + err.Error(), "filter.address")
+			return
+		}
+	}
+	vm_list, err = inventory.List_vms(&o)
+	if (err != nil) {
+		logger.Log("inventory.List_vms: %s", err.Error())
+		httpx.Do_error(w, http.StatusBadRequest, httpx.ERR_INVALID_PARAMETER, "invalid list options: " + err.Error())
+		return
+	}
+	out = &vm_list
+	if (len(o.Select) > 0) {
+		out, err = inventory.Select_fields(out, openapi.VmListFields{}, o.Select)
+		if (err != nil) {
+			httpx.Do_error_field(w, http.StatusBadRequest, httpx.ERR_INVALID_PARAMETER, "invalid list options: " + err.Error(), "select")
+			return
+		}
+	}
+	err = json.NewEncoder(&buf).Encode(out)
 	if (err != nil) {
 		logger.Log("failed to encode JSON")
-		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+		httpx.Do_error(w, http.StatusInternalServerError, httpx.ERR_INTERNAL, "Failed to encode JSON")
 		return
 	}
 	httpx.Do_response(w, http.StatusOK, &buf)
@@ -19,12 +19,19 @@ package virtx
 
 import (
 	"net/http"
+	"errors"
+	"time"
 
 	"suse.com/virtx/pkg/hypervisor"
 	"suse.com/virtx/pkg/logger"
 	"suse.com/virtx/pkg/model"
 	"suse.com/virtx/pkg/httpx"
 	"suse.com/virtx/pkg/inventory"
+	"suse.com/virtx/pkg/task"
+)
+
+const (
+	VM_MIGRATE_PROGRESS_INTERVAL = 2 /* seconds between live migration progress updates */
 )
 
 func vm_migrate(w http.ResponseWriter, r *http.Request) {
@@ -38,32 +45,33 @@ func vm_migrate(w http.ResponseWriter, r *http.Request) {
 		host_old_id string
 		host_new inventory.HostInfo
 		proxy_hostid string
+		tuuid string
 	)
 	vr, err = httpx.Decode_request_body(r, &o)
 	if (err != nil) {
 		logger.Log(err.Error())
-		http.Error(w, "failed to decode body", http.StatusBadRequest)
+		httpx.Do_error_field(w, http.StatusBadRequest, httpx.ERR_INVALID_BODY, "failed to decode body: " + err.Error(), httpx.Error_field(err))
 		return
 	}
 	uuid = r.PathValue("uuid")
 	if (uuid == "") {
-		http.Error(w, "could not get uuid", http.StatusBadRequest)
+		httpx.Do_error(w, http.StatusBadRequest, httpx.ERR_INVALID_UUID, "could not get uuid")
 		return
 	}
 	vminfo, err = inventory.Get_vminfo(uuid)
 	if (err != nil) {
-		http.Error(w, "unknown uuid", http.StatusNotFound)
+		httpx.Do_error(w, http.StatusNotFound, httpx.ERR_NOT_FOUND, "unknown uuid")
 		return
 	}
 	state = vminfo.Runstate
 	if (o.Host == "") {
 		/* Auto migration is not implemented yet */
-		http.Error(w, "Not implemented", http.StatusNotImplemented)
+		httpx.Do_error(w, http.StatusNotImplemented, httpx.ERR_NOT_IMPLEMENTED, "Not implemented")
 		return
 	}
 	host_old_id = vminfo.Host
 	if (o.Host == host_old_id) {
-		http.Error(w, "Cannot migrate to the same host", http.StatusUnprocessableEntity)
+		httpx.Do_error_field(w, http.StatusUnprocessableEntity, httpx.ERR_INVALID_PARAMETER, "Cannot migrate to the same host", "host")
 		return
 	}
 	proxy_hostid = host_old_id
@@ -74,31 +82,69 @@ func vm_migrate(w http.ResponseWriter, r *http.Request) {
 	switch (o.MigrationType) {
 	case openapi.MIGRATION_COLD:
 		if (state != openapi.RUNSTATE_POWEROFF && state != openapi.RUNSTATE_CRASHED) {
-			http.Error(w, "VM is not powered off", http.StatusUnprocessableEntity)
+			httpx.Do_error(w, http.StatusUnprocessableEntity, httpx.ERR_INVALID_STATE, "VM is not powered off")
 			return
 		}
 	case openapi.MIGRATION_LIVE:
 		if (state != openapi.RUNSTATE_RUNNING && state != openapi.RUNSTATE_PAUSED) {
-			http.Error(w, "VM is not running or paused", http.StatusUnprocessableEntity)
+			httpx.Do_error(w, http.StatusUnprocessableEntity, httpx.ERR_INVALID_STATE, "VM is not running or paused")
 			return
 		}
 	default:
-		http.Error(w, "invalid migration type", http.StatusBadRequest)
+		httpx.Do_error_field(w, http.StatusBadRequest, httpx.ERR_INVALID_PARAMETER, "invalid migration type", "migration_type")
 		return
 	}
 	host_new, err = inventory.Get_hostinfo(o.Host)
 	if (err != nil) {
 		logger.Log("inventory.Get_host(%s) failed: %s", o.Host, err.Error())
-		http.Error(w, "failed to get host", http.StatusInternalServerError)
+		httpx.Do_error(w, http.StatusInternalServerError, httpx.ERR_INTERNAL, "failed to get host")
+		return
+	}
+	tuuid, err = task.Start(openapi.OpVmMigrate, uuid, func(t *task.Task) (string, error) {
+		return vm_migrate_task(t, host_new.Name, o.Host, host_old_id, uuid, o.MigrationType == openapi.MIGRATION_LIVE, int(vminfo.Vcpus))
+	})
+	if (err != nil) {
+		logger.Log("task.Start failed: %s", err.Error())
+		httpx.Do_error(w, http.StatusInternalServerError, httpx.ERR_INTERNAL, "failed to start task")
 		return
 	}
-	go func() {
-		err = hypervisor.Migrate_domain(host_new.Name, o.Host, host_old_id, uuid, o.MigrationType == openapi.MIGRATION_LIVE, int(vminfo.Vcpus))
-		if (err != nil) {
-			logger.Log("migration of domain %s failed: %s", uuid, err.Error())
-		} else {
-			logger.Debug("migration of domain %s successful", uuid)
+	http_task_accepted(w, tuuid)
+}
+
+/* migrate the domain, reporting the progress of live migrations */
+func vm_migrate_task(t *task.Task, host_name string, host_new_id string, host_old_id string, uuid string, live bool, vcpus int) (string, error) {
+	var (
+		err error
+		done chan struct{} = make(chan struct{})
+	)
+	if (live) {
+		go vm_migrate_progress(t, uuid, done)
+	}
+	err = hypervisor.Migrate_domain(host_name, host_new_id, host_old_id, uuid, live, vcpus)
+	close(done)
+	if (err != nil) {
+		return "", errors.New("migration failed: " + err.Error())
+	}
+	return "", nil
+}
+
+func vm_migrate_progress(t *task.Task, uuid string, done chan struct{}) {
+	var (
+		err error
+		info openapi.MigrationInfo
+		ticker *time.Ticker = time.NewTicker(VM_MIGRATE_PROGRESS_INTERVAL * time.Second)
+	)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			info, err = hypervisor.Get_migration_info(uuid)
+			if (err != nil || info.Progress.Total <= 0) {
+				continue
+			}
+			t.Set_progress(int16(info.Progress.Transferred * 100 / info.Progress.Total))
 		}
-	} ()
-	httpx.Do_response(w, http.StatusAccepted, nil)
+	}
 }
@@ -0,0 +1,180 @@
+/*
+ * Copyright (c) 2024-2026 SUSE LLC
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, see
+ * <https://www.gnu.org/licenses/>
+ */
+package virtx
+
+import (
+	"net/http"
+	"encoding/json"
+	"strings"
+
+	"suse.com/virtx/pkg/apispec"
+	"suse.com/virtx/pkg/encoding/jsonpatch"
+	"suse.com/virtx/pkg/logger"
+	"suse.com/virtx/pkg/model"
+	"suse.com/virtx/pkg/vmreg"
+	"suse.com/virtx/pkg/vmdef"
+	"suse.com/virtx/pkg/httpx"
+	"suse.com/virtx/pkg/inventory"
+	"suse.com/virtx/pkg/task"
+)
+
+/*
+ * PATCH /vms/{uuid}: change part of the VM definition.
+ * The patch is applied to the JSON of the registered Vmdef, either as a
+ * JSON merge patch (RFC 7396) or as a JSON patch (RFC 6902) depending on Content-Type.
+ * The result is validated and the VM redefined as for vm_update.
+ * The storage of disks removed by the patch is not deleted.
+ */
+func vm_patch(w http.ResponseWriter, r *http.Request) {
+	var (
+		err error
+		host string
+		patch json.RawMessage
+		old, vm openapi.Vmdef
+		xml, uuid, tuuid, content_type string
+		doc []byte
+		vminfo inventory.VmInfo
+		vr httpx.Request
+		state openapi.Vmrunstate
+	)
+	vr, err = httpx.Decode_request_body(r, &patch)
+	if (err != nil) {
+		logger.Log(err.Error())
+		httpx.Do_error_field(w, http.StatusBadRequest, httpx.ERR_INVALID_BODY, "failed to decode body: " + err.Error(), httpx.Error_field(err))
+		return
+	}
+	content_type, _, _ = strings.Cut(r.Header.Get("Content-Type"), ";")
+	content_type = strings.TrimSpace(content_type)
+	if (content_type != jsonpatch.MERGE_CONTENT_TYPE && content_type != jsonpatch.PATCH_CONTENT_TYPE) {
+		w.Header().Set("Accept-Patch", jsonpatch.MERGE_CONTENT_TYPE + ", " + jsonpatch.PATCH_CONTENT_TYPE)
+		httpx.Do_error(w, http.StatusUnsupportedMediaType, httpx.ERR_UNSUPPORTED_MEDIA_TYPE, "unsupported patch format " + content_type)
+		return
+	}
+	uuid = r.PathValue("uuid")
+	if (uuid == "") {
+		httpx.Do_error(w, http.StatusBadRequest, httpx.ERR_INVALID_UUID, "could not get uuid")
+		return
+	}
+	vminfo, err = inventory.Get_vminfo(uuid)
+	if (err != nil) {
+		httpx.Do_error(w, http.StatusNotFound, httpx.ERR_NOT_FOUND, "unknown uuid")
+		return
+	}
+	host = vminfo.Host
+	if (http_host_is_remote(host)) { /* need to proxy */
+		http_proxy_request(host, w, vr)
+		return
+	}
+	state = vminfo.Runstate
+	if (state == openapi.RUNSTATE_MIGRATING) {
+		httpx.Do_error(w, http.StatusUnprocessableEntity, httpx.ERR_INVALID_STATE, "VM is migrating")
+		return
+	}
+	if (!vm_change_begin(uuid)) {
+		httpx.Do_error(w, http.StatusConflict, httpx.ERR_CONFLICT, "VM definition is being changed")
+		return
+	}
+	/* read the configuration of the VM from the registry on disk */
+	xml, err = vmreg.Load(host, uuid)
+	if (err != nil) {
+		vm_change_end(uuid)
+		logger.Log("vmreg.Load(%s, %s) failed: %s", host, uuid, err.Error())
+		httpx.Do_error(w, http.StatusInternalServerError, httpx.ERR_INTERNAL, "could not Load VM")
+		return
+	}
+	if (!http_check_etag(w, r, xml)) {
+		vm_change_end(uuid)
+		return
+	}
+	err = vmdef.From_xml(&old, xml)
+	if (err != nil) {
+		vm_change_end(uuid)
+		logger.Log("vmdef_from_xml failed: %s", err.Error())
+		httpx.Do_error(w, http.StatusInternalServerError, httpx.ERR_INTERNAL, "invalid VM data")
+		return
+	}
+	/* patch the definition as the client sees it with GET, secrets hidden */
+	vm = old
+	vmdef.Hide_secrets(&vm)
+	doc, err = json.Marshal(&vm)
+	if (err != nil) {
+		vm_change_end(uuid)
+		logger.Log("failed to encode JSON")
+		httpx.Do_error(w, http.StatusInternalServerError, httpx.ERR_INTERNAL, "Failed to encode JSON")
+		return
+	}
+	if (content_type == jsonpatch.MERGE_CONTENT_TYPE) {
+		doc, err = jsonpatch.Merge(doc, patch)
+	} else {
+		doc, err = jsonpatch.Apply(doc, patch)
+	}
+	if (err != nil) {
+		vm_change_end(uuid)
+		httpx.Do_error(w, http.StatusUnprocessableEntity, httpx.ERR_INVALID_PARAMETER, "could not apply patch: " + err.Error())
+		return
+	}
+	/* a JSON patch can add anything, so check the result against the schema too */
+	err = apispec.Validate_schema("Vmdef", doc)
+	if (err != nil) {
+		vm_change_end(uuid)
+		httpx.Do_error_field(w, http.StatusBadRequest, httpx.ERR_INVALID_PARAMETER, "invalid parameters: " + err.Error(), apispec.Error_field(err))
+		return
+	}
+	vm = openapi.Vmdef{}
+	err = json.Unmarshal(doc, &vm)
+	if (err != nil) {
+		vm_change_end(uuid)
+		httpx.Do_error_field(w, http.StatusBadRequest, httpx.ERR_INVALID_PARAMETER, "invalid parameters: " + err.Error(), httpx.Error_field(err))
+		return
+	}
+	vmdef.Keep_secrets(&vm, &old)
+	err = vmdef.Validate(&vm)
+	if (err != nil) {
+		vm_change_end(uuid)
+		logger.Log("vmdef_validate failed: %s", err.Error())
+		httpx.Do_error_field(w, http.StatusBadRequest, httpx.ERR_INVALID_PARAMETER, "invalid parameters: " + err.Error(), vmdef.Error_field(err))
+		return
+	}
+	if (vmdef.Metadata_only(&vm, &old)) {
+		tuuid, err = task.Start(openapi.OpVmPatch, uuid, func(t *task.Task) (string, error) {
+			defer vm_change_end(uuid)
+			return vm_metadata_task(&vm, uuid)
+		})
+	} else if (state != openapi.RUNSTATE_POWEROFF && state != openapi.RUNSTATE_CRASHED) {
+		vm_change_end(uuid)
+		httpx.Do_error(w, http.StatusUnprocessableEntity, httpx.ERR_INVALID_STATE, "VM is not powered off (only name and custom fields can be changed while running)")
+		return
+	} else if (vmdef.Has_snapshots(xml)) {
+		/* redefining would lose the snapshot overlays of the disks */
+		vm_change_end(uuid)
+		httpx.Do_error(w, http.StatusUnprocessableEntity, httpx.ERR_INVALID_STATE, "VM has snapshots (only name and custom fields can be changed)")
+		return
+	} else {
+		tuuid, err = task.Start(openapi.OpVmPatch, uuid, func(t *task.Task) (string, error) {
+			defer vm_change_end(uuid)
+			return vm_update_task(&vm, &old, uuid, false)
+		})
+	}
+	if (err != nil) {
+		vm_change_end(uuid)
+		logger.Log("task.Start failed: %s", err.Error())
+		httpx.Do_error(w, http.StatusInternalServerError, httpx.ERR_INTERNAL, "failed to start task")
+		return
+	}
+	http_task_accepted(w, tuuid)
+}
@@ -35,17 +35,17 @@ func vm_pause(w http.ResponseWriter, r *http.Request) {
 	vr, err = httpx.Decode_request_body(r, nil)
 	if (err != nil) {
 		logger.Log(err.Error())
-		http.Error(w, "failed to decode body", http.StatusBadRequest)
+		httpx.Do_error_field(w, http.StatusBadRequest, httpx.ERR_INVALID_BODY, "failed to decode body: " + err.Error(), httpx.Error_field(err))
 		return
 	}
 	uuid = r.PathValue("uuid")
 	if (uuid == "") {
-		http.Error(w, "could not get uuid", http.StatusBadRequest)
+		httpx.Do_error(w, http.StatusBadRequest, httpx.ERR_INVALID_UUID, "could not get uuid")
 		return
 	}
 	vminfo, err = inventory.Get_vminfo(uuid)
 	if (err != nil) {
-		http.Error(w, "unknown uuid", http.StatusNotFound)
+		httpx.Do_error(w, http.StatusNotFound, httpx.ERR_NOT_FOUND, "unknown uuid")
 		return
 	}
 	if (http_host_is_remote(vminfo.Host)) {
@@ -55,7 +55,7 @@ func vm_pause(w http.ResponseWriter, r *http.Request) {
 	err = hypervisor.Pause_domain(uuid)
 	if (err != nil) {
 		logger.Log("hypervisor.Pause_domain failed: %s", err.Error())
-		http.Error(w, "could not pause VM", http.StatusFailedDependency)
+		httpx.Do_error(w, http.StatusFailedDependency, httpx.ERR_HYPERVISOR, "could not pause VM: " + err.Error())
 		return
 	}
 	httpx.Do_response(w, http.StatusNoContent, nil)
@@ -0,0 +1,76 @@
+/*
+ * Copyright (c) 2024-2026 SUSE LLC
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, see
+ * <https://www.gnu.org/licenses/>
+ */
+package virtx
+
+import (
+	"net/http"
+
+	"suse.com/virtx/pkg/hypervisor"
+	"suse.com/virtx/pkg/logger"
+	"suse.com/virtx/pkg/model"
+	"suse.com/virtx/pkg/httpx"
+	"suse.com/virtx/pkg/inventory"
+)
+
+/*
+ * reboot the guest without powering off the VM, so that resources tied to the
+ * running domain (f.e. the cloud-init disk) are kept.
+ */
+func vm_reboot(w http.ResponseWriter, r *http.Request) {
+	var (
+		err error
+		uuid string
+		o openapi.VmRebootOptions
+		vminfo inventory.VmInfo
+		vr httpx.Request
+	)
+	vr, err = httpx.Decode_request_body(r, &o)
+	if (err != nil) {
+		logger.Log(err.Error())
+		httpx.Do_error_field(w, http.StatusBadRequest, httpx.ERR_INVALID_BODY, "failed to decode body: " + err.Error(), httpx.Error_field(err))
+		return
+	}
+	uuid = r.PathValue("uuid")
+	if (uuid == "") {
+		httpx.Do_error(w, http.StatusBadRequest, httpx.ERR_INVALID_UUID, "could not get uuid")
+		return
+	}
+	vminfo, err = inventory.Get_vminfo(uuid)
+	if (err != nil) {
+		httpx.Do_error(w, http.StatusNotFound, httpx.ERR_NOT_FOUND, "unknown uuid")
+		return
+	}
+	if (http_host_is_remote(vminfo.Host)) {
+		http_proxy_request(vminfo.Host, w, vr)
+		return
+	}
+	err = hypervisor.Reboot_domain(uuid, o.Force, o.Agent)
+	if (err != nil) {
+		logger.Log("hypervisor.Reboot_domain failed: %s", err.Error())
+		httpx.Do_error(w, http.StatusFailedDependency, httpx.ERR_HYPERVISOR, "could not reboot VM: " + err.Error())
+		return
+	}
+	var status int
+	if (!o.Force) {
+		/* domain could be rebooting or not, depends on guest ACPI */
+		status = http.StatusAccepted
+	} else {
+		status = http.StatusNoContent
+	}
+	httpx.Do_response(w, status, nil)
+}
@@ -42,12 +42,12 @@ func vm_register(w http.ResponseWriter, r *http.Request) {
 	vr, err = httpx.Decode_request_body(r, &o)
 	if (err != nil) {
 		logger.Log(err.Error())
-		http.Error(w, "failed to decode body", http.StatusBadRequest)
+		httpx.Do_error_field(w, http.StatusBadRequest, httpx.ERR_INVALID_BODY, "failed to decode body: " + err.Error(), httpx.Error_field(err))
 		return
 	}
 	uuid = r.PathValue("uuid")
 	if (uuid == "") {
-		http.Error(w, "could not get uuid", http.StatusBadRequest)
+		httpx.Do_error(w, http.StatusBadRequest, httpx.ERR_INVALID_UUID, "could not get uuid")
 		return
 	}
 	if (http_host_is_remote(o.Host)) { /* need to proxy */
@@ -63,7 +63,7 @@ func vm_register(w http.ResponseWriter, r *http.Request) {
 		 * Check if it exists in vmreg, and if not register it from libvirt
 		 */
 		if (vminfo.Host != o.Host || vminfo.Host != machine.Uuid()) {
-			http.Error(w, "invalid host for this VM", http.StatusUnprocessableEntity)
+			httpx.Do_error_field(w, http.StatusUnprocessableEntity, httpx.ERR_INVALID_PARAMETER, "invalid host for this VM", "host")
 			return
 		}
 		err = vm_register_vmreg(o.Host, uuid)
@@ -82,7 +82,7 @@ func vm_register(w http.ResponseWriter, r *http.Request) {
 	}
 	if (err != nil) {
 		logger.Log("failed to register %s/%s: %s", o.Host, uuid, err.Error())
-		http.Error(w, "failed to register uuid", http.StatusFailedDependency)
+		httpx.Do_error(w, http.StatusFailedDependency, httpx.ERR_HYPERVISOR, "failed to register uuid: " + err.Error())
 		return
 	}
 	httpx.Do_response(w, status, nil)
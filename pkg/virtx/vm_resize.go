@@ -0,0 +1,159 @@
+/*
+ * Copyright (c) 2024-2026 SUSE LLC
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, see
+ * <https://www.gnu.org/licenses/>
+ */
+package virtx
+
+import (
+	"net/http"
+	"errors"
+
+	"suse.com/virtx/pkg/hypervisor"
+	"suse.com/virtx/pkg/logger"
+	"suse.com/virtx/pkg/model"
+	"suse.com/virtx/pkg/vmreg"
+	"suse.com/virtx/pkg/vmdef"
+	"suse.com/virtx/pkg/httpx"
+	"suse.com/virtx/pkg/inventory"
+	"suse.com/virtx/pkg/task"
+)
+
+/*
+ * hot-add vcpus (whole sockets) and memory to a VM, also while running, up to the maximums in its definition.
+ * The additional resources are checked against the resources available for VMs on the host.
+ */
+func vm_resize(w http.ResponseWriter, r *http.Request) {
+	var (
+		err error
+		o openapi.VmResizeOptions
+		old, vm openapi.Vmdef
+		xml, uuid, tuuid string
+		vminfo inventory.VmInfo
+		vr httpx.Request
+		state openapi.Vmrunstate
+		maxsockets int16
+		max_memory int32
+		vcpus uint
+	)
+	vr, err = httpx.Decode_request_body(r, &o)
+	if (err != nil) {
+		logger.Log(err.Error())
+		httpx.Do_error_field(w, http.StatusBadRequest, httpx.ERR_INVALID_BODY, "failed to decode body: " + err.Error(), httpx.Error_field(err))
+		return
+	}
+	uuid = r.PathValue("uuid")
+	if (uuid == "") {
+		httpx.Do_error(w, http.StatusBadRequest, httpx.ERR_INVALID_UUID, "could not get uuid")
+		return
+	}
+	vminfo, err = inventory.Get_vminfo(uuid)
+	if (err != nil) {
+		httpx.Do_error(w, http.StatusNotFound, httpx.ERR_NOT_FOUND, "unknown uuid")
+		return
+	}
+	if (http_host_is_remote(vminfo.Host)) {
+		http_proxy_request(vminfo.Host, w, vr)
+		return
+	}
+	state = vminfo.Runstate
+	if (state != openapi.RUNSTATE_POWEROFF && state != openapi.RUNSTATE_CRASHED &&
+		state != openapi.RUNSTATE_RUNNING && state != openapi.RUNSTATE_PAUSED) {
+		httpx.Do_error(w, http.StatusUnprocessableEntity, httpx.ERR_INVALID_STATE, "VM is not powered off or running")
+		return
+	}
+	if (o.Sockets < 0 || o.Memory < 0 || (o.Sockets == 0 && o.Memory == 0)) {
+		httpx.Do_error(w, http.StatusBadRequest, httpx.ERR_INVALID_PARAMETER, "no sockets or memory to set")
+		return
+	}
+	if (!vm_change_begin(uuid)) {
+		httpx.Do_error(w, http.StatusConflict, httpx.ERR_CONFLICT, "VM definition is being changed")
+		return
+	}
+	xml, err = vmreg.Load(vminfo.Host, uuid)
+	if (err != nil) {
+		vm_change_end(uuid)
+		logger.Log("vmreg.Load(%s, %s) failed: %s", vminfo.Host, uuid, err.Error())
+		httpx.Do_error(w, http.StatusInternalServerError, httpx.ERR_INTERNAL, "could not Load VM")
+		return
+	}
+	err = vmdef.From_xml(&old, xml)
+	if (err != nil) {
+		vm_change_end(uuid)
+		logger.Log("vmdef.From_xml failed: %s", err.Error())
+		httpx.Do_error(w, http.StatusInternalServerError, httpx.ERR_INTERNAL, "invalid VM data")
+		return
+	}
+	vm = old
+	if (o.Sockets != 0) {
+		maxsockets = old.Cpudef.Maxsockets
+		if (maxsockets < old.Cpudef.Sockets) {
+			maxsockets = old.Cpudef.Sockets
+		}
+		if (o.Sockets < old.Cpudef.Sockets || o.Sockets > maxsockets) {
+			vm_change_end(uuid)
+			httpx.Do_error_field(w, http.StatusUnprocessableEntity, httpx.ERR_INVALID_PARAMETER, "sockets can only be added, up to cpudef.maxsockets", "sockets")
+			return
+		}
+		vm.Cpudef.Sockets = o.Sockets
+		vcpus = uint(vm.Cpudef.Sockets * vm.Cpudef.Cores * vm.Cpudef.Threads)
+	}
+	if (o.Memory != 0) {
+		max_memory = old.Memory.Max
+		if (max_memory < old.Memory.Total) {
+			max_memory = old.Memory.Total
+		}
+		if (o.Memory < old.Memory.Total || o.Memory > max_memory) {
+			vm_change_end(uuid)
+			httpx.Do_error_field(w, http.StatusUnprocessableEntity, httpx.ERR_INVALID_PARAMETER, "memory can only be added, up to memory.max", "memory")
+			return
+		}
+		vm.Memory.Total = o.Memory
+	}
+	err = vmdef.Validate(&vm)
+	if (err != nil) {
+		vm_change_end(uuid)
+		logger.Log("vmdef.Validate failed: %s", err.Error())
+		httpx.Do_error_field(w, http.StatusBadRequest, httpx.ERR_INVALID_PARAMETER, "invalid parameters: " + err.Error(), vmdef.Error_field(err))
+		return
+	}
+	err = hypervisor.Check_resources(int(vm.Cpudef.Sockets - old.Cpudef.Sockets) * int(vm.Cpudef.Cores * vm.Cpudef.Threads),
+		vm.Memory.Total - old.Memory.Total, vm.Memory.Hp)
+	if (err != nil) {
+		vm_change_end(uuid)
+		httpx.Do_error(w, http.StatusUnprocessableEntity, httpx.ERR_INVALID_STATE, "host resources: " + err.Error())
+		return
+	}
+	tuuid, err = task.Start(openapi.OpVmResize, uuid, func(t *task.Task) (string, error) {
+		defer vm_change_end(uuid)
+		return vm_resize_task(uuid, vcpus, o.Memory)
+	})
+	if (err != nil) {
+		vm_change_end(uuid)
+		logger.Log("task.Start failed: %s", err.Error())
+		httpx.Do_error(w, http.StatusInternalServerError, httpx.ERR_INTERNAL, "failed to start task")
+		return
+	}
+	http_task_accepted(w, tuuid)
+}
+
+func vm_resize_task(uuid string, vcpus uint, memory int32) (string, error) {
+	var err error
+	err = hypervisor.Resize_domain(uuid, vcpus, memory)
+	if (err != nil) {
+		return "", errors.New("could not resize VM: " + err.Error())
+	}
+	return "", nil
+}
@@ -39,17 +39,17 @@ func vm_runstate_get(w http.ResponseWriter, r *http.Request) {
 	_, err = httpx.Decode_request_body(r, nil)
 	if (err != nil) {
 		logger.Log(err.Error())
-		http.Error(w, "failed to decode body", http.StatusBadRequest)
+		httpx.Do_error_field(w, http.StatusBadRequest, httpx.ERR_INVALID_BODY, "failed to decode body: " + err.Error(), httpx.Error_field(err))
 		return
 	}
 	uuid = r.PathValue("uuid")
 	if (uuid == "") {
-		http.Error(w, "vm_runstate_get: Failed to decode parameters", http.StatusBadRequest)
+		httpx.Do_error(w, http.StatusBadRequest, httpx.ERR_INVALID_UUID, "vm_runstate_get: Failed to decode parameters")
 		return
 	}
 	vminfo, err = inventory.Get_vminfo(uuid)
 	if (err != nil) {
-		http.Error(w, "vm_runstate_get: No such VM", http.StatusNotFound)
+		httpx.Do_error(w, http.StatusNotFound, httpx.ERR_NOT_FOUND, "vm_runstate_get: No such VM")
 		return
 	}
 	runinfo.Runstate = vminfo.Runstate
@@ -57,7 +57,7 @@ func vm_runstate_get(w http.ResponseWriter, r *http.Request) {
 	err = json.NewEncoder(&buf).Encode(&runinfo)
 	if (err != nil) {
 		logger.Log("failed to encode JSON")
-		http.Error(w, "Failed to encode JSON", http.StatusInternalServerError)
+		httpx.Do_error(w, http.StatusInternalServerError, httpx.ERR_INTERNAL, "Failed to encode JSON")
 		return
 	}
 	httpx.Do_response(w, http.StatusOK, &buf)
@@ -19,12 +19,14 @@ package virtx
 
 import (
 	"net/http"
+	"errors"
 
 	"suse.com/virtx/pkg/hypervisor"
 	"suse.com/virtx/pkg/logger"
 	"suse.com/virtx/pkg/model"
 	"suse.com/virtx/pkg/httpx"
 	"suse.com/virtx/pkg/inventory"
+	"suse.com/virtx/pkg/task"
 )
 
 func vm_shutdown(w http.ResponseWriter, r *http.Request) {
@@ -34,35 +36,53 @@ func vm_shutdown(w http.ResponseWriter, r *http.Request) {
 		o openapi.VmShutdownOptions
 		vminfo inventory.VmInfo
 		vr httpx.Request
+		tuuid string
 	)
 	vr, err = httpx.Decode_request_body(r, &o)
 	if (err != nil) {
 		logger.Log(err.Error())
-		http.Error(w, "failed to decode body", http.StatusBadRequest)
+		httpx.Do_error_field(w, http.StatusBadRequest, httpx.ERR_INVALID_BODY, "failed to decode body: " + err.Error(), httpx.Error_field(err))
 		return
 	}
 	uuid = r.PathValue("uuid")
 	if (uuid == "") {
-		http.Error(w, "could not get uuid", http.StatusBadRequest)
+		httpx.Do_error(w, http.StatusBadRequest, httpx.ERR_INVALID_UUID, "could not get uuid")
 		return
 	}
 	if (o.Force < 0 || o.Force > 2) {
-		http.Error(w, "invalid force field", http.StatusBadRequest)
+		httpx.Do_error_field(w, http.StatusBadRequest, httpx.ERR_INVALID_PARAMETER, "invalid force field", "force")
+		return
+	}
+	if (o.Timeout < 0) {
+		httpx.Do_error_field(w, http.StatusBadRequest, httpx.ERR_INVALID_PARAMETER, "invalid timeout field", "timeout")
 		return
 	}
 	vminfo, err = inventory.Get_vminfo(uuid)
 	if (err != nil) {
-		http.Error(w, "unknown uuid", http.StatusNotFound)
+		httpx.Do_error(w, http.StatusNotFound, httpx.ERR_NOT_FOUND, "unknown uuid")
 		return
 	}
 	if (http_host_is_remote(vminfo.Host)) {
 		http_proxy_request(vminfo.Host, w, vr)
 		return
 	}
-	err = hypervisor.Shutdown_domain(uuid, o.Force)
+	if (o.Force == 0 && o.Timeout > 0) {
+		/* bounded shutdown, escalating if the guest ignores ACPI */
+		tuuid, err = task.Start(openapi.OpVmShutdown, uuid, func(t *task.Task) (string, error) {
+			return vm_shutdown_task(uuid, o.Timeout, o.Agent)
+		})
+		if (err != nil) {
+			logger.Log("task.Start failed: %s", err.Error())
+			httpx.Do_error(w, http.StatusInternalServerError, httpx.ERR_INTERNAL, "failed to start task")
+			return
+		}
+		http_task_accepted(w, tuuid)
+		return
+	}
+	err = hypervisor.Shutdown_domain(uuid, o.Force, o.Agent)
 	if (err != nil) {
 		logger.Log("hypervisor.Shutdown_domain failed: %s", err.Error())
-		http.Error(w, "could not shutdown VM", http.StatusFailedDependency)
+		httpx.Do_error(w, http.StatusFailedDependency, httpx.ERR_HYPERVISOR, "could not shutdown VM: " + err.Error())
 		return
 	}
 	var status int
@@ -75,3 +95,15 @@ func vm_shutdown(w http.ResponseWriter, r *http.Request) {
 	}
 	httpx.Do_response(w, status, nil)
 }
+
+func vm_shutdown_task(uuid string, timeout int32, agent bool) (string, error) {
+	var (
+		err error
+		method string
+	)
+	method, err = hypervisor.Shutdown_domain_timeout(uuid, timeout, agent)
+	if (err != nil) {
+		return "", errors.New("could not shutdown VM: " + err.Error())
+	}
+	return "powered off with " + method, nil
+}
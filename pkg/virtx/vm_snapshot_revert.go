@@ -0,0 +1,111 @@
+/*
+ * Copyright (c) 2024-2026 SUSE LLC
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, see
+ * <https://www.gnu.org/licenses/>
+ */
+package virtx
+
+import (
+	"net/http"
+	"errors"
+
+	"suse.com/virtx/pkg/hypervisor"
+	"suse.com/virtx/pkg/logger"
+	"suse.com/virtx/pkg/model"
+	"suse.com/virtx/pkg/vmdef"
+	"suse.com/virtx/pkg/httpx"
+	"suse.com/virtx/pkg/inventory"
+	"suse.com/virtx/pkg/task"
+)
+
+/*
+ * revert the disks of a powered off VM to a snapshot, discarding their current state.
+ * The snapshots taken after it are deleted.
+ */
+func vm_snapshot_revert(w http.ResponseWriter, r *http.Request) {
+	var (
+		err error
+		uuid, name, tuuid string
+		vminfo inventory.VmInfo
+		vr httpx.Request
+		exists bool
+	)
+	vr, err = httpx.Decode_request_body(r, nil)
+	if (err != nil) {
+		logger.Log(err.Error())
+		httpx.Do_error_field(w, http.StatusBadRequest, httpx.ERR_INVALID_BODY, "failed to decode body: " + err.Error(), httpx.Error_field(err))
+		return
+	}
+	uuid = r.PathValue("uuid")
+	if (uuid == "") {
+		httpx.Do_error(w, http.StatusBadRequest, httpx.ERR_INVALID_UUID, "could not get uuid")
+		return
+	}
+	vminfo, err = inventory.Get_vminfo(uuid)
+	if (err != nil) {
+		httpx.Do_error(w, http.StatusNotFound, httpx.ERR_NOT_FOUND, "unknown uuid")
+		return
+	}
+	if (http_host_is_remote(vminfo.Host)) {
+		http_proxy_request(vminfo.Host, w, vr)
+		return
+	}
+	name = r.PathValue("name")
+	err = vmdef.Validate_snapshot_name(name)
+	if (err != nil) {
+		httpx.Do_error(w, http.StatusBadRequest, httpx.ERR_INVALID_PARAMETER, "invalid parameters: " + err.Error())
+		return
+	}
+	if (vminfo.Runstate != openapi.RUNSTATE_POWEROFF && vminfo.Runstate != openapi.RUNSTATE_CRASHED) {
+		httpx.Do_error(w, http.StatusUnprocessableEntity, httpx.ERR_INVALID_STATE, "VM is not powered off")
+		return
+	}
+	if (!vm_change_begin(uuid)) {
+		httpx.Do_error(w, http.StatusConflict, httpx.ERR_CONFLICT, "VM definition is being changed")
+		return
+	}
+	exists, err = vm_snapshot_exists(uuid, name)
+	if (err != nil) {
+		vm_change_end(uuid)
+		logger.Log("vm_snapshot_exists failed: %s", err.Error())
+		httpx.Do_error(w, http.StatusFailedDependency, httpx.ERR_HYPERVISOR, "could not list snapshots: " + err.Error())
+		return
+	}
+	if (!exists) {
+		vm_change_end(uuid)
+		httpx.Do_error(w, http.StatusNotFound, httpx.ERR_NOT_FOUND, "unknown snapshot")
+		return
+	}
+	tuuid, err = task.Start(openapi.OpVmSnapshotRevert, uuid, func(t *task.Task) (string, error) {
+		defer vm_change_end(uuid)
+		return vm_snapshot_revert_task(uuid, name)
+	})
+	if (err != nil) {
+		vm_change_end(uuid)
+		logger.Log("task.Start failed: %s", err.Error())
+		httpx.Do_error(w, http.StatusInternalServerError, httpx.ERR_INTERNAL, "failed to start task")
+		return
+	}
+	http_task_accepted(w, tuuid)
+}
+
+func vm_snapshot_revert_task(uuid string, name string) (string, error) {
+	var err error
+	err = hypervisor.Revert_snapshot(uuid, name)
+	if (err != nil) {
+		return "", errors.New("could not revert to snapshot: " + err.Error())
+	}
+	return "", nil
+}
@@ -0,0 +1,173 @@
+/*
+ * Copyright (c) 2024-2026 SUSE LLC
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, see
+ * <https://www.gnu.org/licenses/>
+ */
+package virtx
+
+import (
+	"net/http"
+	"errors"
+	"os"
+
+	"suse.com/virtx/pkg/logger"
+	"suse.com/virtx/pkg/model"
+	"suse.com/virtx/pkg/vmdef"
+	"suse.com/virtx/pkg/vmreg"
+	"suse.com/virtx/pkg/httpx"
+	"suse.com/virtx/pkg/inventory"
+	"suse.com/virtx/pkg/storage"
+	"suse.com/virtx/pkg/task"
+)
+
+/*
+ * create a template image as a full copy of the OS disk of a powered off VM
+ * (with its current state, if it has snapshots). The VM is not changed.
+ */
+func vm_template_create(w http.ResponseWriter, r *http.Request) {
+	var (
+		err error
+		o openapi.VmTemplateOptions
+		uuid, xml, tuuid, source string
+		vminfo inventory.VmInfo
+		vm openapi.Vmdef
+		vr httpx.Request
+	)
+	vr, err = httpx.Decode_request_body(r, &o)
+	if (err != nil) {
+		logger.Log(err.Error())
+		httpx.Do_error_field(w, http.StatusBadRequest, httpx.ERR_INVALID_BODY, "failed to decode body: " + err.Error(), httpx.Error_field(err))
+		return
+	}
+	uuid = r.PathValue("uuid")
+	if (uuid == "") {
+		httpx.Do_error(w, http.StatusBadRequest, httpx.ERR_INVALID_UUID, "could not get uuid")
+		return
+	}
+	vminfo, err = inventory.Get_vminfo(uuid)
+	if (err != nil) {
+		httpx.Do_error(w, http.StatusNotFound, httpx.ERR_NOT_FOUND, "unknown uuid")
+		return
+	}
+	if (http_host_is_remote(vminfo.Host)) {
+		http_proxy_request(vminfo.Host, w, vr)
+		return
+	}
+	err = vmdef.Validate_template_name(o.Name)
+	if (err != nil) {
+		httpx.Do_error_field(w, http.StatusBadRequest, httpx.ERR_INVALID_PARAMETER, "invalid parameters: " + err.Error(), "name")
+		return
+	}
+	if (vminfo.Runstate != openapi.RUNSTATE_POWEROFF && vminfo.Runstate != openapi.RUNSTATE_CRASHED) {
+		httpx.Do_error(w, http.StatusUnprocessableEntity, httpx.ERR_INVALID_STATE, "VM is not powered off")
+		return
+	}
+	_, err = os.Stat(vmdef.Template_path(o.Name))
+	if (err == nil) {
+		httpx.Do_error(w, http.StatusConflict, httpx.ERR_CONFLICT, "template already exists")
+		return
+	}
+	/* a template file removed behind our back must not be replaced under the linked disks */
+	err = vm_template_check_unused(vmdef.Template_path(o.Name))
+	if (err != nil) {
+		logger.Log("vm_template_check_unused: %s", err.Error())
+		httpx.Do_error(w, http.StatusConflict, httpx.ERR_CONFLICT, err.Error())
+		return
+	}
+	if (!vm_change_begin(uuid)) {
+		httpx.Do_error(w, http.StatusConflict, httpx.ERR_CONFLICT, "VM definition is being changed")
+		return
+	}
+	xml, err = vmreg.Load(vminfo.Host, uuid)
+	if (err != nil) {
+		vm_change_end(uuid)
+		logger.Log("vmreg.Load(%s, %s) failed: %s", vminfo.Host, uuid, err.Error())
+		httpx.Do_error(w, http.StatusInternalServerError, httpx.ERR_INTERNAL, "could not Load VM")
+		return
+	}
+	err = vmdef.From_xml(&vm, xml)
+	if (err != nil) {
+		vm_change_end(uuid)
+		logger.Log("vmdef.From_xml failed: %s", err.Error())
+		httpx.Do_error(w, http.StatusInternalServerError, httpx.ERR_INTERNAL, "invalid VM data")
+		return
+	}
+	/* the copy is read under the lease of the OS disk */
+	if (vm.Osdisk.Device != openapi.DEVICE_DISK || vm.Osdisk.Man != openapi.DISK_MAN_MANAGED) {
+		vm_change_end(uuid)
+		httpx.Do_error(w, http.StatusUnprocessableEntity, httpx.ERR_INVALID_PARAMETER, "OS disk is not a managed virtual disk")
+		return
+	}
+	source = vmdef.Clone_sources(&vm, xml)[0]
+	tuuid, err = task.Start(openapi.OpVmTemplateCreate, uuid, func(t *task.Task) (string, error) {
+		defer vm_change_end(uuid)
+		return vm_template_create_task(source, vm.Osdisk.Path, o.Name, uuid)
+	})
+	if (err != nil) {
+		vm_change_end(uuid)
+		logger.Log("task.Start failed: %s", err.Error())
+		httpx.Do_error(w, http.StatusInternalServerError, httpx.ERR_INTERNAL, "failed to start task")
+		return
+	}
+	http_task_accepted(w, tuuid)
+}
+
+/* copy the OS disk into the template, copying large disks can take a long time */
+func vm_template_create_task(source string, root string, name string, uuid string) (string, error) {
+	var err error
+	err = storage.Template_create(source, root, name, uuid)
+	if (err != nil) {
+		return "", errors.New("could not create template: " + err.Error())
+	}
+	return "created " + vmdef.Template_path(name), nil
+}
+
+/* check that no linked disk of any registered VM is based on the template at path */
+func vm_template_check_unused(path string) error {
+	var (
+		err error
+		hosts, uuids []string
+		xml string
+		vm openapi.Vmdef
+	)
+	hosts, err = vmreg.Hosts()
+	if (err != nil) {
+		return errors.New("could not read the VM registry: " + err.Error())
+	}
+	for _, host := range hosts {
+		uuids, err = vmreg.Uuids(host)
+		if (err != nil) {
+			return errors.New("could not read the VM registry: " + err.Error())
+		}
+		for _, uuid := range uuids {
+			xml, err = vmreg.Load(host, uuid)
+			if (err != nil) {
+				return errors.New("could not Load VM " + uuid + ": " + err.Error())
+			}
+			vm = openapi.Vmdef{}
+			err = vmdef.From_xml(&vm, xml)
+			if (err != nil) {
+				logger.Log("vm_template_check_unused: invalid VM data for %s: %s", uuid, err.Error())
+				continue
+			}
+			for _, disk := range vmdef.Disks(&vm) {
+				if (disk.Prov == openapi.DISK_PROV_LINKED && disk.Base == path) {
+					return errors.New("template is used by the linked disks of VM " + uuid)
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -19,6 +19,7 @@ package virtx
 
 import (
 	"net/http"
+	"errors"
 
 	"suse.com/virtx/pkg/hypervisor"
 	"suse.com/virtx/pkg/logger"
@@ -28,6 +29,7 @@ import (
 	"suse.com/virtx/pkg/httpx"
 	"suse.com/virtx/pkg/inventory"
 	"suse.com/virtx/pkg/storage"
+	"suse.com/virtx/pkg/task"
 )
 
 func vm_update(w http.ResponseWriter, r *http.Request) {
@@ -36,26 +38,25 @@ func vm_update(w http.ResponseWriter, r *http.Request) {
 		host string
 		o openapi.VmUpdateOptions
 		old openapi.Vmdef
-		xml, uuid string
+		xml, uuid, tuuid string
 		vminfo inventory.VmInfo
 		vr httpx.Request
 		state openapi.Vmrunstate
-		created storage.CreatedResources
 	)
 	vr, err = httpx.Decode_request_body(r, &o)
 	if (err != nil) {
 		logger.Log(err.Error())
-		http.Error(w, "failed to decode body", http.StatusBadRequest)
+		httpx.Do_error_field(w, http.StatusBadRequest, httpx.ERR_INVALID_BODY, "failed to decode body: " + err.Error(), httpx.Error_field(err))
 		return
 	}
 	uuid = r.PathValue("uuid")
 	if (uuid == "") {
-		http.Error(w, "could not get uuid", http.StatusBadRequest)
+		httpx.Do_error(w, http.StatusBadRequest, httpx.ERR_INVALID_UUID, "could not get uuid")
 		return
 	}
 	vminfo, err = inventory.Get_vminfo(uuid)
 	if (err != nil) {
-		http.Error(w, "unknown uuid", http.StatusNotFound)
+		httpx.Do_error(w, http.StatusNotFound, httpx.ERR_NOT_FOUND, "unknown uuid")
 		return
 	}
 	host = vminfo.Host
@@ -64,59 +65,108 @@ func vm_update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	state = vminfo.Runstate
-	if (state != openapi.RUNSTATE_POWEROFF && state != openapi.RUNSTATE_CRASHED) {
-		http.Error(w, "VM is not powered off", http.StatusUnprocessableEntity)
+	if (state == openapi.RUNSTATE_MIGRATING) {
+		httpx.Do_error(w, http.StatusUnprocessableEntity, httpx.ERR_INVALID_STATE, "VM is migrating")
 		return
 	}
 	err = vmdef.Validate(&o.Vmdef)
 	if (err != nil) {
 		logger.Log("vmdef_validate failed: %s", err.Error())
-		http.Error(w, "invalid parameters", http.StatusBadRequest)
+		httpx.Do_error_field(w, http.StatusBadRequest, httpx.ERR_INVALID_PARAMETER, "invalid parameters: " + err.Error(), vmdef.Error_field(err))
+		return
+	}
+	if (!vm_change_begin(uuid)) {
+		httpx.Do_error(w, http.StatusConflict, httpx.ERR_CONFLICT, "VM definition is being changed")
 		return
 	}
 	/* read the configuration of the VM from the registry on disk */
 	xml, err = vmreg.Load(host, uuid)
 	if (err != nil) {
+		vm_change_end(uuid)
 		logger.Log("vmreg.Load(%s, %s) failed: %s", host, uuid, err.Error())
-		http.Error(w, "could not Load VM", http.StatusInternalServerError)
+		httpx.Do_error(w, http.StatusInternalServerError, httpx.ERR_INTERNAL, "could not Load VM")
+		return
+	}
+	if (!http_check_etag(w, r, xml)) {
+		vm_change_end(uuid)
 		return
 	}
 	err = vmdef.From_xml(&old, xml)
 	if (err != nil) {
+		vm_change_end(uuid)
 		logger.Log("vmdef_from_xml failed: %s", err.Error())
-		http.Error(w, "invalid VM data", http.StatusInternalServerError)
+		httpx.Do_error(w, http.StatusInternalServerError, httpx.ERR_INTERNAL, "invalid VM data")
 		return
 	}
-	/* create missing storage where needed, can change o.Vmdef in some cases */
-	created, err = storage.Create(&o.Vmdef, &old, uuid)
+	/* the client got the secrets hidden with GET, and passes them back unchanged */
+	vmdef.Keep_secrets(&o.Vmdef, &old)
+	if (vmdef.Metadata_only(&o.Vmdef, &old)) {
+		tuuid, err = task.Start(openapi.OpVmUpdate, uuid, func(t *task.Task) (string, error) {
+			defer vm_change_end(uuid)
+			return vm_metadata_task(&o.Vmdef, uuid)
+		})
+	} else if (state != openapi.RUNSTATE_POWEROFF && state != openapi.RUNSTATE_CRASHED) {
+		vm_change_end(uuid)
+		httpx.Do_error(w, http.StatusUnprocessableEntity, httpx.ERR_INVALID_STATE, "VM is not powered off (only name and custom fields can be changed while running)")
+		return
+	} else if (vmdef.Has_snapshots(xml)) {
+		/* redefining would lose the snapshot overlays of the disks */
+		vm_change_end(uuid)
+		httpx.Do_error(w, http.StatusUnprocessableEntity, httpx.ERR_INVALID_STATE, "VM has snapshots (only name and custom fields can be changed)")
+		return
+	} else {
+		tuuid, err = task.Start(openapi.OpVmUpdate, uuid, func(t *task.Task) (string, error) {
+			defer vm_change_end(uuid)
+			return vm_update_task(&o.Vmdef, &old, uuid, o.Deletestorage)
+		})
+	}
 	if (err != nil) {
-		logger.Log("vm_update_storage failed: %s", err.Error())
-		storage.Rollback(created, uuid)
-		http.Error(w, "storage update failed", http.StatusInsufficientStorage)
+		vm_change_end(uuid)
+		logger.Log("task.Start failed: %s", err.Error())
+		httpx.Do_error(w, http.StatusInternalServerError, httpx.ERR_INTERNAL, "failed to start task")
 		return
 	}
-	xml, err = vmdef.To_xml(&o.Vmdef, uuid)
+	http_task_accepted(w, tuuid)
+}
+
+/* update the storage and redefine the VM */
+func vm_update_task(vm *openapi.Vmdef, old *openapi.Vmdef, uuid string, deletestorage bool) (string, error) {
+	var (
+		err error
+		xml string
+		created storage.CreatedResources
+	)
+	/* create missing storage where needed, can change vm in some cases */
+	created, err = storage.Create(vm, old, uuid)
+	if (err != nil) {
+		storage.Rollback(created, uuid)
+		return "", errors.New("storage update failed: " + err.Error())
+	}
+	xml, err = vmdef.To_xml(vm, uuid)
 	if (err != nil) {
-		logger.Log("vmdef_to_xml failed: %s", err.Error())
 		storage.Rollback(created, uuid)
-		http.Error(w, "invalid parameters", http.StatusBadRequest)
-		return
+		return "", errors.New("invalid parameters: " + err.Error())
 	}
 	/* redefine the updated domain */
 	err = hypervisor.Define_domain(xml, uuid)
 	if (err != nil) {
-		logger.Log("hypervisor.Define_domain failed: %s", err.Error())
 		storage.Rollback(created, uuid)
-		http.Error(w, "could not define VM", http.StatusFailedDependency)
-		return
+		return "", errors.New("could not define VM: " + err.Error())
 	}
-	err = storage.Delete(&old, &o.Vmdef, uuid, o.Deletestorage)
+	err = storage.Delete(old, vm, uuid, deletestorage)
 	if (err != nil) {
-		w.Header().Set("Warning", `299 VirtX "some resources could not be deleted"`)
-		/* respond with Ok (there was a Warning) */
-		httpx.Do_response(w, http.StatusOK, nil)
-	} else {
-		/* respond with NoContent (no warnings) */
-		httpx.Do_response(w, http.StatusNoContent, nil)
+		/* complete with a warning */
+		return "some resources could not be deleted", nil
+	}
+	return "", nil
+}
+
+/* change only the name and custom fields, also while running */
+func vm_metadata_task(vm *openapi.Vmdef, uuid string) (string, error) {
+	var err error
+	err = hypervisor.Set_metadata(uuid, vm.Name, vm.Custom)
+	if (err != nil) {
+		return "", errors.New("could not set VM metadata: " + err.Error())
 	}
+	return "", nil
 }
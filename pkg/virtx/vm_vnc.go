@@ -0,0 +1,98 @@
+/*
+ * Copyright (c) 2024-2026 SUSE LLC
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, see
+ * <https://www.gnu.org/licenses/>
+ */
+package virtx
+
+import (
+	"net"
+	"net/http"
+	"time"
+
+	"suse.com/virtx/pkg/hypervisor"
+	"suse.com/virtx/pkg/logger"
+	"suse.com/virtx/pkg/model"
+	"suse.com/virtx/pkg/httpx"
+	"suse.com/virtx/pkg/inventory"
+	"suse.com/virtx/pkg/websocket"
+)
+
+/*
+ * upgrade the connection to a WebSocket connected to the VNC server of the VM,
+ * compatible with noVNC. The VNC password, if set, is checked by the VNC server itself.
+ */
+func vm_vnc(w http.ResponseWriter, r *http.Request) {
+	var (
+		err error
+		uuid string
+		vminfo inventory.VmInfo
+		vr httpx.Request
+		network, address string
+		conn net.Conn
+		ws *websocket.Conn
+	)
+	vr, err = httpx.Decode_request_body(r, nil)
+	if (err != nil) {
+		logger.Log(err.Error())
+		httpx.Do_error_field(w, http.StatusBadRequest, httpx.ERR_INVALID_BODY, "failed to decode body: " + err.Error(), httpx.Error_field(err))
+		return
+	}
+	uuid = r.PathValue("uuid")
+	if (uuid == "") {
+		httpx.Do_error(w, http.StatusBadRequest, httpx.ERR_INVALID_UUID, "could not get uuid")
+		return
+	}
+	vminfo, err = inventory.Get_vminfo(uuid)
+	if (err != nil) {
+		httpx.Do_error(w, http.StatusNotFound, httpx.ERR_NOT_FOUND, "unknown uuid")
+		return
+	}
+	if (http_host_is_remote(vminfo.Host)) {
+		http_proxy_upgrade(vminfo.Host, w, vr)
+		return
+	}
+	if (!websocket.Is_upgrade(r)) {
+		w.Header().Set("Upgrade", "websocket")
+		httpx.Do_error(w, http.StatusUpgradeRequired, httpx.ERR_UPGRADE_REQUIRED, "VNC requires a WebSocket connection")
+		return
+	}
+	if (vminfo.Runstate != openapi.RUNSTATE_RUNNING && vminfo.Runstate != openapi.RUNSTATE_PAUSED) {
+		httpx.Do_error(w, http.StatusUnprocessableEntity, httpx.ERR_INVALID_STATE, "VM is not running or paused")
+		return
+	}
+	network, address, err = hypervisor.Vnc_address(uuid)
+	if (err != nil) {
+		logger.Log("hypervisor.Vnc_address failed: %s", err.Error())
+		httpx.Do_error(w, http.StatusFailedDependency, httpx.ERR_HYPERVISOR, "could not get VNC address: " + err.Error())
+		return
+	}
+	conn, err = net.DialTimeout(network, address, 5 * time.Second)
+	if (err != nil) {
+		logger.Log("failed to connect to VNC of VM %s at %s: %s", uuid, address, err.Error())
+		httpx.Do_error(w, http.StatusFailedDependency, httpx.ERR_HYPERVISOR, "could not connect to VNC: " + err.Error())
+		return
+	}
+	ws, err = websocket.Upgrade(w, r)
+	if (err != nil) {
+		conn.Close()
+		logger.Log("websocket.Upgrade failed: %s", err.Error())
+		httpx.Do_error(w, http.StatusBadRequest, httpx.ERR_INVALID_PARAMETER, "could not upgrade to WebSocket: " + err.Error())
+		return
+	}
+	logger.Log("VNC of VM %s opened", uuid)
+	http_websocket_bridge(ws, conn)
+	logger.Log("VNC of VM %s closed", uuid)
+}
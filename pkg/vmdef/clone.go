@@ -0,0 +1,96 @@
+/*
+ * Copyright (c) 2024-2026 SUSE LLC
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, see
+ * <https://www.gnu.org/licenses/>
+ */
+package vmdef
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"suse.com/virtx/pkg/model"
+
+	. "suse.com/virtx/pkg/constants"
+)
+
+/*
+ * get the definition of a clone of src, named name.
+ * The managed disks are placed in DS_DIR/uuid/, keeping their file names,
+ * and the MACs and the Genid are left for libvirt to generate.
+ * Unmanaged disks are shared with the source.
+ */
+func Clone(src *openapi.Vmdef, name string, uuid string) (openapi.Vmdef, error) {
+	var (
+		vm openapi.Vmdef = *src
+		used = make(map[string]bool)
+	)
+	vm.Name = name
+	vm.Disks = append([]openapi.Disk{}, src.Disks...)
+	vm.Nets = append([]openapi.Net{}, src.Nets...)
+	vm.Custom = append([]openapi.CustomField{}, src.Custom...)
+	for i, disk := range Disks(&vm) {
+		if (disk.Man == openapi.DISK_MAN_UNMANAGED) {
+			continue
+		}
+		if (disk.Device == openapi.DEVICE_LUN) {
+			return vm, errors.New("managed LUNs cannot be cloned")
+		}
+		path := DS_DIR + uuid + "/" + filepath.Base(disk.Path)
+		if (used[path]) {
+			path = fmt.Sprintf("%s%s/%d-%s", DS_DIR, uuid, i, filepath.Base(disk.Path))
+		}
+		used[path] = true
+		disk.Path = path
+		/* the copy is always created by virtx, and linked disks are copied in full */
+		if (disk.Prov == openapi.DISK_PROV_NONE || disk.Prov == openapi.DISK_PROV_LINKED) {
+			disk.Prov = openapi.DISK_PROV_THIN
+		}
+		disk.Base = ""
+	}
+	for i := range vm.Nets {
+		vm.Nets[i].Mac = ""
+	}
+	if (vm.Genid != "") {
+		vm.Genid = "auto"
+	}
+	return vm, nil
+}
+
+/*
+ * get the files to copy for each disk of src, in the order of Disks(src).
+ * For disks with snapshots this is the latest overlay, so that the clone gets the current state.
+ */
+func Clone_sources(src *openapi.Vmdef, xmlstr string) []string {
+	var (
+		sources []string
+		active = make(map[string]string)
+	)
+	disks, _, err := Snapshot_disks(xmlstr)
+	if (err == nil) {
+		for _, disk := range disks {
+			active[disk.Root] = disk.Active
+		}
+	}
+	for _, disk := range Disks(src) {
+		if (active[disk.Path] != "") {
+			sources = append(sources, active[disk.Path])
+		} else {
+			sources = append(sources, disk.Path)
+		}
+	}
+	return sources
+}
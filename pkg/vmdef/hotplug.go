@@ -0,0 +1,360 @@
+/*
+ * Copyright (c) 2024-2026 SUSE LLC
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, see
+ * <https://www.gnu.org/licenses/>
+ */
+package vmdef
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"crypto/rand"
+	"encoding/xml" /* XXX necessary due to missing Marshal() for libvirtxml.DomainLease XXX */
+
+	"suse.com/virtx/pkg/model"
+
+	"libvirt.org/go/libvirtxml"
+	. "suse.com/virtx/pkg/constants"
+)
+
+/*
+ * the devices to attach or detach for hot-plugging a disk, in attach order.
+ * Controller is set only for buses with a controller per disk, Lease only for managed disks.
+ */
+type HotplugDisk struct {
+	Lease string
+	Controller string
+	Disk string
+	Target string   /* the target device name, f.e. "vdb" */
+	Iothread uint   /* the new iothread used by the disk or its controller, or 0 */
+}
+
+/* the prefix of the MACs generated for hot-plugged interfaces, as libvirt does for qemu */
+const MAC_PREFIX = "52:54:00"
+
+/*
+ * XXX
+ * libvirtxml package is missing the necessary Marshal() method for leases:
+ * domain_leases[0].Marshal()
+ * https://gitlab.com/libvirt/libvirt-go-module/-/work_items/25
+ * XXX
+ */
+func Lease_to_xml(lease *libvirtxml.DomainLease) (string, error) {
+	var (
+		err error
+		lease_bytes []byte
+	)
+	s := struct {
+		XMLName xml.Name `xml:"lease"`
+		*libvirtxml.DomainLease
+	}{
+		DomainLease: lease,
+	}
+	lease_bytes, err = xml.Marshal(&s)
+	if (err != nil) {
+		return "", fmt.Errorf("marshalling lease XML: %w", err)
+	}
+	return string(lease_bytes), nil
+}
+
+/*
+ * get the devices to attach disk to a domain, given the XML of its definitions
+ * (the persistent one, and also the live one if running).
+ * Detaching disks leaves gaps, so the target name and the controller index are the first
+ * ones which are free, instead of following the count of disks as To_xml does.
+ */
+func Attach_disk_xml(xmlstrs []string, disk *openapi.Disk) (HotplugDisk, error) {
+	var (
+		err error
+		hd HotplugDisk
+		iothreads uint
+		used = make(map[string]bool) /* target names and controllers as "type:index" */
+		disk_count = make(map[string]int)
+	)
+	for _, xmlstr := range xmlstrs {
+		var domain libvirtxml.Domain
+		err = domain.Unmarshal(xmlstr)
+		if (err != nil) {
+			return hd, err
+		}
+		if (domain.IOThreads > iothreads) {
+			iothreads = domain.IOThreads
+		}
+		if (domain.Devices == nil) {
+			continue
+		}
+		for _, domain_disk := range domain.Devices.Disks {
+			if (domain_disk.Target != nil) {
+				used[domain_disk.Target.Dev] = true
+			}
+			if (hotplug_disk_path(&domain_disk) == disk.Path) {
+				return hd, errors.New("disk already attached")
+			}
+		}
+		for _, controller := range domain.Devices.Controllers {
+			if (controller.Index != nil) {
+				used[fmt.Sprintf("%s:%d", controller.Type, *controller.Index)] = true
+			}
+		}
+	}
+	disk_count["scsi"] = 1 /* SCSI controller 0 reserved for cloud-init */
+	for {
+		var (
+			domain_disks []libvirtxml.DomainDisk
+			domain_leases []libvirtxml.DomainLease
+			domain_controllers []libvirtxml.DomainController
+			leases *[]libvirtxml.DomainLease
+			iothread_count uint = iothreads
+		)
+		if (disk.Man != openapi.DISK_MAN_UNMANAGED) {
+			leases = &domain_leases
+		}
+		err = Disk_to_xml(disk, disk_count, &iothread_count, &domain_disks, leases, &domain_controllers, -1)
+		if (err != nil) {
+			return hd, err
+		}
+		target := domain_disks[0].Target
+		if (used[target.Dev] || (len(domain_controllers) > 0 &&
+			used[fmt.Sprintf("%s:%d", domain_controllers[0].Type, *domain_controllers[0].Index)])) {
+			/* Disk_to_xml counted the disk already, so the next try gets the next name */
+			if (disk_count[target.Bus] >= 26) {
+				return hd, errors.New("no free device name")
+			}
+			continue
+		}
+		hd.Target = target.Dev
+		if (iothread_count > iothreads) {
+			hd.Iothread = iothread_count
+		}
+		if (len(domain_leases) > 0) {
+			hd.Lease, err = Lease_to_xml(&domain_leases[0])
+			if (err != nil) {
+				return hd, err
+			}
+		}
+		if (len(domain_controllers) > 0) {
+			hd.Controller, err = domain_controllers[0].Marshal()
+			if (err != nil) {
+				return hd, err
+			}
+		}
+		hd.Disk, err = domain_disks[0].Marshal()
+		return hd, err
+	}
+}
+
+/*
+ * get the devices to detach the disk with path from a domain, given the XML of its persistent definition.
+ * The controller of the disk is detached too if no other disk uses it; the iothread is kept.
+ */
+func Detach_disk_xml(xmlstr string, path string) (HotplugDisk, error) {
+	var (
+		err error
+		hd HotplugDisk
+		domain libvirtxml.Domain
+		disk openapi.Disk
+		found *libvirtxml.DomainDisk
+		controller uint
+	)
+	err = domain.Unmarshal(xmlstr)
+	if (err != nil) {
+		return hd, err
+	}
+	if (domain.Devices == nil) {
+		return hd, errors.New("missing Devices")
+	}
+	for i := range domain.Devices.Disks {
+		if (hotplug_disk_path(&domain.Devices.Disks[i]) == path) {
+			found = &domain.Devices.Disks[i]
+			break
+		}
+	}
+	if (found == nil || found.Target == nil) {
+		return hd, errors.New("disk not found: " + path)
+	}
+	err = vmdef_disk_from_xml(&disk, found)
+	if (err != nil) {
+		return hd, err
+	}
+	hd.Target = found.Target.Dev
+	hd.Disk, err = found.Marshal()
+	if (err != nil) {
+		return hd, err
+	}
+	if (disk.Man != openapi.DISK_MAN_UNMANAGED) {
+		var lease libvirtxml.DomainLease = vmdef_lease(&disk)
+		hd.Lease, err = Lease_to_xml(&lease)
+		if (err != nil) {
+			return hd, err
+		}
+	}
+	if (found.Target.Bus != "scsi" || found.Address == nil || found.Address.Drive == nil || found.Address.Drive.Controller == nil) {
+		return hd, nil
+	}
+	controller = *found.Address.Drive.Controller
+	if (controller == 0) { /* reserved for cloud-init */
+		return hd, nil
+	}
+	for i := range domain.Devices.Disks {
+		var other *libvirtxml.DomainDisk = &domain.Devices.Disks[i]
+		if (other != found && other.Target != nil && other.Target.Bus == "scsi" && other.Address != nil &&
+			other.Address.Drive != nil && other.Address.Drive.Controller != nil && *other.Address.Drive.Controller == controller) {
+			return hd, nil
+		}
+	}
+	for i := range domain.Devices.Controllers {
+		var c *libvirtxml.DomainController = &domain.Devices.Controllers[i]
+		if (c.Type == "scsi" && c.Index != nil && *c.Index == controller) {
+			hd.Controller, err = c.Marshal()
+			return hd, err
+		}
+	}
+	return hd, nil
+}
+
+/* the path of the disk as shown in the Vmdef, or "" if it has no source */
+func hotplug_disk_path(domain_disk *libvirtxml.DomainDisk) string {
+	if (domain_disk.Source == nil) {
+		return ""
+	}
+	if (domain_disk.Source.Block != nil) {
+		return domain_disk.Source.Block.Dev
+	}
+	if (domain_disk.Source.File != nil) {
+		return Snapshot_root(domain_disk.Source.File.File)
+	}
+	return ""
+}
+
+/*
+ * get the interface to attach net to a domain, given the XML of its definitions
+ * (the persistent one, and also the live one if running).
+ * If net has no Mac, a new one is generated and set, so that the persistent and the live
+ * interfaces get the same address, which can be reported back.
+ */
+func Attach_net_xml(xmlstrs []string, net *openapi.Net, vlanid int16) (string, error) {
+	var (
+		err error
+		used = make(map[string]bool)
+		domain_interface libvirtxml.DomainInterface
+	)
+	for _, xmlstr := range xmlstrs {
+		var domain libvirtxml.Domain
+		err = domain.Unmarshal(xmlstr)
+		if (err != nil) {
+			return "", err
+		}
+		if (domain.Devices == nil) {
+			continue
+		}
+		for _, i := range domain.Devices.Interfaces {
+			if (i.MAC != nil) {
+				used[strings.ToLower(i.MAC.Address)] = true
+			}
+		}
+	}
+	if (net.Mac != "") {
+		if (used[strings.ToLower(net.Mac)]) {
+			return "", errors.New("mac already in use")
+		}
+	} else {
+		for {
+			net.Mac, err = Generate_mac()
+			if (err != nil) {
+				return "", err
+			}
+			if (!used[net.Mac]) {
+				break
+			}
+		}
+	}
+	domain_interface = Net_to_xml(net, vlanid)
+	return domain_interface.Marshal()
+}
+
+/* get the interface to detach the net with mac from a domain, given the XML of its persistent definition */
+func Detach_net_xml(xmlstr string, mac string) (string, error) {
+	var (
+		err error
+		domain libvirtxml.Domain
+	)
+	err = domain.Unmarshal(xmlstr)
+	if (err != nil) {
+		return "", err
+	}
+	if (domain.Devices == nil) {
+		return "", errors.New("missing Devices")
+	}
+	for _, i := range domain.Devices.Interfaces {
+		if (i.MAC != nil && strings.EqualFold(i.MAC.Address, mac)) {
+			return i.Marshal()
+		}
+	}
+	return "", errors.New("net not found: " + mac)
+}
+
+/* generate a random MAC address with the MAC_PREFIX */
+func Generate_mac() (string, error) {
+	var (
+		err error
+		b [3]byte
+	)
+	_, err = rand.Read(b[:])
+	if (err != nil) {
+		return "", err
+	}
+	return fmt.Sprintf("%s:%02x:%02x:%02x", MAC_PREFIX, b[0], b[1], b[2]), nil
+}
+
+/*
+ * get the virtio-mem device of a domain with memory hot-add, given the XML of its persistent definition,
+ * with the requested size changed so that the total memory becomes total MiB.
+ */
+func Memory_resize_xml(xmlstr string, total int32) (string, error) {
+	var (
+		err error
+		domain libvirtxml.Domain
+		vm openapi.Vmdef
+		dev *libvirtxml.DomainMemorydev
+		current int32
+	)
+	err = domain.Unmarshal(xmlstr)
+	if (err != nil) {
+		return "", err
+	}
+	dev = vmdef_memorydev(&domain)
+	if (dev == nil) {
+		return "", errors.New("memory hot-add not enabled")
+	}
+	err = vmdef_memory_from_xml(&vm, &domain)
+	if (err != nil) {
+		return "", err
+	}
+	if (total > vm.Memory.Max) {
+		return "", fmt.Errorf("memory exceeds maximum %d MiB", vm.Memory.Max)
+	}
+	current, err = vmdef_mib(dev.Target.Requested.Value, dev.Target.Requested.Unit)
+	if (err != nil) {
+		return "", err
+	}
+	/* the boot memory in the NUMA cells cannot change */
+	requested := total - (vm.Memory.Total - current)
+	if (requested < 0 || requested % MEMORY_BLOCK != 0) {
+		return "", fmt.Errorf("memory must exceed the boot memory by a multiple of %d MiB", MEMORY_BLOCK)
+	}
+	dev.Target.Requested = &libvirtxml.DomainMemorydevTargetRequested{ Value: uint(requested), Unit: "MiB" }
+	return dev.Marshal()
+}
@@ -0,0 +1,188 @@
+/*
+ * Copyright (c) 2024-2026 SUSE LLC
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, see
+ * <https://www.gnu.org/licenses/>
+ */
+package vmdef
+
+import (
+	"errors"
+	"strings"
+
+	"suse.com/virtx/pkg/model"
+
+	"libvirt.org/go/libvirtxml"
+	. "suse.com/virtx/pkg/constants"
+)
+
+/*
+ * External snapshots add a qcow2 overlay on top of each managed disk.
+ * The overlay of snapshot "name" for the disk "root" is the file "root@name",
+ * and the chain is linear: the image frozen by a snapshot is the parent file,
+ * either the root disk or the overlay of the previous snapshot.
+ * The Vmdef always shows the root path, which is also what the disk lease is keyed on.
+ */
+
+/* SnapshotDisk is a disk of the domain which takes part in snapshots */
+type SnapshotDisk struct {
+	Target string /* the target device name, f.e. "vda" */
+	Root string   /* the disk path in the Vmdef */
+	Active string /* the current source file, either Root or an overlay */
+}
+
+/* return the path of the overlay for snapshot name of the disk root */
+func Snapshot_path(root string, name string) string {
+	return root + SNAPSHOT_SEP + name
+}
+
+/* return the root disk path of an overlay path, or path itself if it is not an overlay */
+func Snapshot_root(path string) string {
+	var i int = strings.LastIndex(path, SNAPSHOT_SEP)
+	if (i < 0 || strings.ContainsAny(path[i:], "/.")) {
+		return path
+	}
+	return path[:i]
+}
+
+/* get the disk driver type of a file in a snapshot chain, or "" if not recognized */
+func Snapshot_driver(path string) string {
+	if (Snapshot_root(path) != path) {
+		return "qcow2"
+	}
+	return Disk_driver(path)
+}
+
+/* validate a snapshot name, which is also part of the overlay file names */
+func Validate_snapshot_name(name string) error {
+	return vmdef_validate_file_name(name, SNAPSHOT_NAME_MAX, "snapshot")
+}
+
+/* validate a name which is used to build file names, kind is only used for the error message */
+func vmdef_validate_file_name(name string, max int, kind string) error {
+	if (name == "" || len(name) > max) {
+		return errors.New("invalid " + kind + " name length")
+	}
+	for _, c := range name {
+		if ((c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c == '_' || c == '-') {
+			continue
+		}
+		return errors.New("invalid character in " + kind + " name")
+	}
+	return nil
+}
+
+/*
+ * get the disks of the domain XML which take part in snapshots (managed disks),
+ * and the target names of the other disks, which are excluded.
+ */
+func Snapshot_disks(xmlstr string) ([]SnapshotDisk, []string, error) {
+	var (
+		err error
+		domain libvirtxml.Domain
+		disks []SnapshotDisk
+		excluded []string
+	)
+	err = domain.Unmarshal(xmlstr)
+	if (err != nil) {
+		return nil, nil, err
+	}
+	if (domain.Devices == nil) {
+		return nil, nil, errors.New("missing Devices")
+	}
+	for i := range domain.Devices.Disks {
+		var (
+			disk openapi.Disk
+			domain_disk *libvirtxml.DomainDisk = &domain.Devices.Disks[i]
+		)
+		err = vmdef_disk_from_xml(&disk, domain_disk)
+		if (err != nil) {
+			return nil, nil, err
+		}
+		if (disk.Device != openapi.DEVICE_DISK || disk.Man != openapi.DISK_MAN_MANAGED) {
+			excluded = append(excluded, domain_disk.Target.Dev)
+			continue
+		}
+		disks = append(disks, SnapshotDisk{
+			Target: domain_disk.Target.Dev,
+			Root: disk.Path,
+			Active: domain_disk.Source.File.File,
+		})
+	}
+	if (len(disks) == 0) {
+		return nil, nil, errors.New("no managed disks")
+	}
+	return disks, excluded, nil
+}
+
+/* check whether any disk of the domain XML is an overlay created by a snapshot */
+func Has_snapshots(xmlstr string) bool {
+	var (
+		err error
+		disks []SnapshotDisk
+	)
+	disks, _, err = Snapshot_disks(xmlstr)
+	if (err != nil) {
+		return false
+	}
+	for _, disk := range disks {
+		if (disk.Active != disk.Root) {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+ * change the source file of the disk with the target name in the domain XML.
+ * chain contains the new source file first, followed by its backing files down to the root disk.
+ * The template image of a linked disk is appended to the chain.
+ */
+func Set_disk_source(xmlstr string, target string, chain []string) (string, error) {
+	var (
+		err error
+		domain libvirtxml.Domain
+		disk openapi.Disk
+	)
+	err = domain.Unmarshal(xmlstr)
+	if (err != nil) {
+		return "", err
+	}
+	if (domain.Devices == nil) {
+		return "", errors.New("missing Devices")
+	}
+	for i := range domain.Devices.Disks {
+		var domain_disk *libvirtxml.DomainDisk = &domain.Devices.Disks[i]
+		if (domain_disk.Target == nil || domain_disk.Target.Dev != target) {
+			continue
+		}
+		if (domain_disk.Source == nil || domain_disk.Source.File == nil) {
+			return "", errors.New("missing Disk File")
+		}
+		err = vmdef_disk_from_xml(&disk, domain_disk)
+		if (err != nil) {
+			return "", err
+		}
+		if (disk.Base != "") {
+			chain = append(chain, disk.Base)
+		}
+		domain_disk.Source.File.File = chain[0]
+		if (domain_disk.Driver != nil) {
+			domain_disk.Driver.Type = Snapshot_driver(chain[0])
+		}
+		domain_disk.BackingStore = vmdef_backing_store(chain[1:])
+		return domain.Marshal()
+	}
+	return "", errors.New("disk not found: " + target)
+}
@@ -0,0 +1,87 @@
+/*
+ * Copyright (c) 2024-2026 SUSE LLC
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, see
+ * <https://www.gnu.org/licenses/>
+ */
+package vmdef
+
+import (
+	"path/filepath"
+
+	"libvirt.org/go/libvirtxml"
+	. "suse.com/virtx/pkg/constants"
+)
+
+/*
+ * Templates are read-only disk images in TEMPLATE_DIR, shared by all VMs using them.
+ * A linked disk is a thin qcow2 overlay backed by a template image: each VM owns
+ * (and holds the lease of) its overlay, while the template is only read, so it has no lease.
+ * The backing chain of linked disks is written explicitly in the domain XML,
+ * and it is where the template of the disk is read back from.
+ */
+
+/* return the path of the template image created with name */
+func Template_path(name string) string {
+	return TEMPLATE_DIR + name + ".qcow2"
+}
+
+/* validate a template name, which is also the template file name without extension */
+func Validate_template_name(name string) error {
+	return vmdef_validate_file_name(name, TEMPLATE_NAME_MAX, "template")
+}
+
+/* validate a template image path and return its driver, or "" on error */
+func Validate_template_path(path string) string {
+	if (!filepath.IsAbs(path) || filepath.Clean(path) != path) {
+		return ""
+	}
+	if (filepath.Dir(path) + "/" != TEMPLATE_DIR) {
+		return ""
+	}
+	if (filepath.Ext(path) == ".iso") {
+		return ""
+	}
+	return Disk_driver(path)
+}
+
+/* build the backing chain of files, from the top down, terminated by an empty backingStore */
+func vmdef_backing_store(files []string) *libvirtxml.DomainDiskBackingStore {
+	var store *libvirtxml.DomainDiskBackingStore = &libvirtxml.DomainDiskBackingStore{}
+	for i := len(files) - 1; i >= 0; i-- {
+		store = &libvirtxml.DomainDiskBackingStore{
+			Format: &libvirtxml.DomainDiskFormat{
+				Type: Snapshot_driver(files[i]),
+			},
+			Source: &libvirtxml.DomainDiskSource{
+				File: &libvirtxml.DomainDiskSourceFile{
+					File: files[i],
+				},
+			},
+			BackingStore: store,
+		}
+	}
+	return store
+}
+
+/* get the last file of the backing chain of the disk, which is the template of a linked disk */
+func vmdef_disk_base(domain_disk *libvirtxml.DomainDisk) string {
+	var base string
+	for store := domain_disk.BackingStore; store != nil; store = store.BackingStore {
+		if (store.Source != nil && store.Source.File != nil) {
+			base = store.Source.File.File
+		}
+	}
+	return base
+}
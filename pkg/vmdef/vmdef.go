@@ -24,6 +24,7 @@ import (
 	"strings"
 	"path/filepath"
 	"fmt"
+	"reflect"
 
 	"suse.com/virtx/pkg/model"
 	"suse.com/virtx/pkg/machine"
@@ -60,6 +61,16 @@ func Has_path(vmdef *openapi.Vmdef, path string) bool {
 	return false
 }
 
+/* get the disk with a certain path in the vmdef, or nil if not present */
+func Get_disk(vmdef *openapi.Vmdef, path string) *openapi.Disk {
+	for _, disk := range Disks(vmdef) {
+		if (path == disk.Path) {
+			return disk
+		}
+	}
+	return nil
+}
+
 /*
  * Return the number of vcpus from a Vmdef
  */
@@ -67,6 +78,27 @@ func vmdef_get_vcpus(vmdef *openapi.Vmdef) uint {
 	return uint(vmdef.Cpudef.Sockets * vmdef.Cpudef.Cores * vmdef.Cpudef.Threads);
 }
 
+/* Return the maximum number of sockets, which can be hot-added up to */
+func vmdef_get_maxsockets(vmdef *openapi.Vmdef) int16 {
+	if (vmdef.Cpudef.Maxsockets > vmdef.Cpudef.Sockets) {
+		return vmdef.Cpudef.Maxsockets
+	}
+	return vmdef.Cpudef.Sockets
+}
+
+/* Return the maximum number of vcpus from a Vmdef */
+func vmdef_get_maxvcpus(vmdef *openapi.Vmdef) uint {
+	return uint(vmdef_get_maxsockets(vmdef) * vmdef.Cpudef.Cores * vmdef.Cpudef.Threads);
+}
+
+/* Return the maximum memory in MiB, which can be hot-added up to */
+func vmdef_get_max_memory(vmdef *openapi.Vmdef) int32 {
+	if (vmdef.Memory.Max > vmdef.Memory.Total) {
+		return vmdef.Memory.Max
+	}
+	return vmdef.Memory.Total
+}
+
 /* get disk driver type from path, or "" if not recognized */
 func Disk_driver(p string) string {
 	var (
@@ -101,38 +133,78 @@ func Validate_disk_path(path string) string {
 	return Disk_driver(path)
 }
 
-func vmdef_validate_disk(disk *openapi.Disk) error {
+/*
+ * FieldError: a validation error about a specific field of the Vmdef,
+ * identified by its JSON path, f.e. "disks[1].bus"
+ */
+type FieldError struct {
+	Field string
+	Msg string
+}
+
+func (e *FieldError) Error() string {
+	return e.Msg
+}
+
+func vmdef_field_error(field string, msg string) error {
+	return &FieldError{ Field: field, Msg: msg }
+}
+
+/* return the JSON path of the invalid field, or "" if err is not a FieldError */
+func Error_field(err error) string {
+	var fe *FieldError
+	if (errors.As(err, &fe)) {
+		return fe.Field
+	}
+	return ""
+}
+
+func vmdef_validate_disk(disk *openapi.Disk, field string) error {
 	var (
 		disk_driver string
 	)
 	if (disk.Size < 0) {
-		return errors.New("invalid Disk Size")
+		return vmdef_field_error(field + ".size", "invalid Disk Size")
 	}
 	disk_driver = Validate_disk_path(disk.Path)
 	if (disk_driver == "") {
-		return errors.New("invalid Disk Path")
+		return vmdef_field_error(field + ".path", "invalid Disk Path")
 	}
 	if (!disk.Device.IsValid()) {
-		return errors.New("invalid Disk Device")
+		return vmdef_field_error(field + ".device", "invalid Disk Device")
 	}
 	if (!disk.Bus.IsValid()) {
-		return errors.New("invalid Disk Bus")
+		return vmdef_field_error(field + ".bus", "invalid Disk Bus")
 	}
 	if (!disk.Prov.IsValid()) {
-		return errors.New("invalid Disk Provisioning mode")
+		return vmdef_field_error(field + ".prov", "invalid Disk Provisioning mode")
 	}
 	if (!disk.Man.IsValid()) {
-		return errors.New("invalid Disk Management mode")
+		return vmdef_field_error(field + ".man", "invalid Disk Management mode")
 	}
 	if (disk.Device == openapi.DEVICE_LUN) {
 		if (disk.Bus != openapi.BUS_VIRTIO_SCSI) {
-			return errors.New("invalid Bus type for Lun")
+			return vmdef_field_error(field + ".bus", "invalid Bus type for Lun")
 		}
 	}
 	if (disk.Device == openapi.DEVICE_CDROM) {
 		if (disk.Bus != openapi.BUS_VIRTIO_SCSI && disk.Bus != openapi.BUS_SCSI && disk.Bus != openapi.BUS_SATA) {
-			return errors.New("invalid Bus type for CDROM")
+			return vmdef_field_error(field + ".bus", "invalid Bus type for CDROM")
+		}
+	}
+	/* templates are shared and only read, they must not be used as disks */
+	if (strings.HasPrefix(disk.Path, TEMPLATE_DIR) && disk.Device != openapi.DEVICE_CDROM) {
+		return vmdef_field_error(field + ".path", "template images can only be the base of linked disks")
+	}
+	if (disk.Prov == openapi.DISK_PROV_LINKED) {
+		if (disk.Device != openapi.DEVICE_DISK || disk_driver != "qcow2") {
+			return vmdef_field_error(field + ".prov", "linked disks must be qcow2 virtual disks")
+		}
+		if (Validate_template_path(disk.Base) == "") {
+			return vmdef_field_error(field + ".base", "invalid template image")
 		}
+	} else if (disk.Base != "") {
+		return vmdef_field_error(field + ".base", "base is only valid for linked disks")
 	}
 	return nil
 }
@@ -141,63 +213,118 @@ func vmdef_validate_disk(disk *openapi.Disk) error {
 func Validate(vmdef *openapi.Vmdef) error {
 	var err error
 	if (vmdef.Name == "" || len(vmdef.Name) > VM_NAME_MAX) {
-		return errors.New("invalid Name length")
+		return vmdef_field_error("name", "invalid Name length")
 	}
 	if (vmdef.Memory.Total < 1) {
-		return errors.New("invalid memory size")
+		return vmdef_field_error("memory.total", "invalid memory size")
 	}
 	if (vmdef.Cpudef.Model == "") {
-		return errors.New("no cpu model provided")
+		return vmdef_field_error("cpudef.model", "no cpu model provided")
 	}
 	if (vmdef.Cpudef.Sockets < 1 ||	vmdef.Cpudef.Cores < 1 || vmdef.Cpudef.Threads < 1) {
-		return errors.New("no cpu topology provided")
+		return vmdef_field_error("cpudef", "no cpu topology provided")
 	}
 	if (vmdef.Cpudef.Threads > 1) {
-		return errors.New("unsupported cpu topology")
+		return vmdef_field_error("cpudef.threads", "unsupported cpu topology")
+	}
+	if (vmdef.Cpudef.Maxsockets != 0 && vmdef.Cpudef.Maxsockets < vmdef.Cpudef.Sockets) {
+		return vmdef_field_error("cpudef.maxsockets", "invalid Maxsockets")
+	}
+	if (vmdef.Memory.Max != 0 && (vmdef.Memory.Max < vmdef.Memory.Total || (vmdef.Memory.Max - vmdef.Memory.Total) % MEMORY_BLOCK != 0)) {
+		return vmdef_field_error("memory.max", fmt.Sprintf("invalid maximum memory size, must exceed total by a multiple of %d MiB", MEMORY_BLOCK))
+	}
+	if (vmdef.Memory.Max > vmdef.Memory.Total && vmdef.Memory.Hp) {
+		/* the virtio-mem device is not backed by hugepages */
+		return vmdef_field_error("memory.max", "memory hot-add is not supported with hugepages")
 	}
 	if (vmdef.Genid != "" && vmdef.Genid != "auto" && len(vmdef.Genid) != 36) {
-		return errors.New("invalid Genid")
+		return vmdef_field_error("genid", "invalid Genid")
 	}
 	if (vmdef.Osdisk.Path == "") {
-		return errors.New("no OS Disk")
+		return vmdef_field_error("osdisk.path", "no OS Disk")
 	}
 	if (len(vmdef.Disks) > DISKS_MAX) {
-		return errors.New("invalid Disks")
+		return vmdef_field_error("disks", "invalid Disks")
 	}
 	if (len(vmdef.Nets) > NETS_MAX) {
-		return errors.New("invalid Nets")
+		return vmdef_field_error("nets", "invalid Nets")
 	}
 	if (vmdef.Vlanid < 0 || vmdef.Vlanid > VLAN_MAX) {
-		return errors.New("invalid Vlanid")
+		return vmdef_field_error("vlanid", "invalid Vlanid")
+	}
+	if (len(vmdef.VncPassword) > VNC_PASSWORD_MAX) {
+		return vmdef_field_error("vnc_password", "invalid Vnc Password length")
 	}
 	/* *** DISKS *** */
-	for _, disk := range Disks(vmdef) {
-		err = vmdef_validate_disk(disk)
+	err = vmdef_validate_disk(&vmdef.Osdisk, "osdisk")
+	if (err != nil) {
+		return err
+	}
+	for i := range vmdef.Disks {
+		err = vmdef_validate_disk(&vmdef.Disks[i], fmt.Sprintf("disks[%d]", i))
 		if (err != nil) {
 			return err
 		}
 	}
 	/* *** NETWORKS *** */
-	for _, net := range vmdef.Nets {
+	for i, net := range vmdef.Nets {
 		if (net.Mac != "" && len(net.Mac) != MAC_LEN) {
-			return errors.New("invalid Mac")
+			return vmdef_field_error(fmt.Sprintf("nets[%d].mac", i), "invalid Mac")
 		}
 		if (!net.Model.IsValid()) {
-			return errors.New("invalid Net model")
+			return vmdef_field_error(fmt.Sprintf("nets[%d].model", i), "invalid Net model")
 		}
 	}
 	/* *** CUSTOM FIELDS *** */
-	for _, custom := range vmdef.Custom {
+	if (len(vmdef.Custom) > CUSTOM_FIELDS_MAX) {
+		return vmdef_field_error("custom", "too many Custom Fields")
+	}
+	for i, custom := range vmdef.Custom {
 		if (custom.Name == "") {
 			continue
 		}
 		if (!custom.IsAlnum()) {
-			return errors.New("invalid Custom Field")
+			return vmdef_field_error(fmt.Sprintf("custom[%d]", i), "invalid Custom Field")
+		}
+		if (len(custom.Name) > CUSTOM_NAME_MAX || len(custom.Value) > CUSTOM_VALUE_MAX) {
+			return vmdef_field_error(fmt.Sprintf("custom[%d]", i), "Custom Field too long")
 		}
 	}
 	return nil
 }
 
+/*
+ * check whether vm differs from old only in the name and the custom fields.
+ * These are stored as domain metadata and can be changed while the VM is running.
+ */
+func Metadata_only(vm *openapi.Vmdef, old *openapi.Vmdef) bool {
+	var a, b openapi.Vmdef = *vm, *old
+	a.Name, b.Name = "", ""
+	a.Custom, b.Custom = nil, nil
+	/* an empty list and a missing one are the same definition */
+	if (len(a.Disks) == 0 && len(b.Disks) == 0) {
+		a.Disks, b.Disks = nil, nil
+	}
+	if (len(a.Nets) == 0 && len(b.Nets) == 0) {
+		a.Nets, b.Nets = nil, nil
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+/* replace the secrets of vm, so that it can be shown to clients */
+func Hide_secrets(vm *openapi.Vmdef) {
+	if (vm.VncPassword != "") {
+		vm.VncPassword = VNC_PASSWORD_HIDDEN
+	}
+}
+
+/* restore in vm the secrets of old hidden by Hide_secrets, which the client passed back unchanged */
+func Keep_secrets(vm *openapi.Vmdef, old *openapi.Vmdef) {
+	if (vm.VncPassword == VNC_PASSWORD_HIDDEN) {
+		vm.VncPassword = old.VncPassword
+	}
+}
+
 func Disk_to_xml(disk *openapi.Disk, disk_count map[string]int, iothread_count *uint,
 	domain_disks *[]libvirtxml.DomainDisk, domain_leases *[]libvirtxml.DomainLease,
 	domain_controllers *[]libvirtxml.DomainController, order int) error {
@@ -309,6 +436,12 @@ func Disk_to_xml(disk *openapi.Disk, disk_count map[string]int, iothread_count *
 				}
 			}
 		}(),
+		BackingStore: func() *libvirtxml.DomainDiskBackingStore {
+			if (disk.Prov == openapi.DISK_PROV_LINKED) {
+				return vmdef_backing_store([]string{ disk.Base })
+			}
+			return nil
+		}(),
 		Target: &libvirtxml.DomainDiskTarget{
 			Dev: device_name,
 			Bus: ctrl_type,
@@ -353,6 +486,131 @@ func Disk_to_xml(disk *openapi.Disk, disk_count map[string]int, iothread_count *
 	return nil
 }
 
+/* the interface of net, with the VLAN of the VM (0 for none) */
+func Net_to_xml(net *openapi.Net, vlanid int16) libvirtxml.DomainInterface {
+	return libvirtxml.DomainInterface{
+		/* XMLName:,*/
+		/* Managed:,*/
+		/* TrustGuestRXFilters:,*/
+		MAC: func() *libvirtxml.DomainInterfaceMAC {
+			if (net.Mac != "") {
+				return &libvirtxml.DomainInterfaceMAC{
+					Address: net.Mac,
+				}
+			}
+			return nil
+		}(),
+		Source: func() *libvirtxml.DomainInterfaceSource {
+			if (net.Nettype == openapi.NET_BRIDGE) {
+				return &libvirtxml.DomainInterfaceSource{
+					Bridge: &libvirtxml.DomainInterfaceSourceBridge{
+						Bridge: net.Name,
+					},
+				}
+			}
+			if (net.Nettype == openapi.NET_LIBVIRT) {
+				return &libvirtxml.DomainInterfaceSource{
+					Network: &libvirtxml.DomainInterfaceSourceNetwork{
+						Network: net.Name,
+					},
+				}
+			}
+			return nil
+		}(),
+		VLan: func() *libvirtxml.DomainInterfaceVLan {
+			if (vlanid > 0) {
+				return &libvirtxml.DomainInterfaceVLan{
+					Tags: []libvirtxml.DomainInterfaceVLanTag{
+						{ ID: uint(vlanid), },
+					},
+				}
+			}
+			return nil
+		}(),
+		Model: &libvirtxml.DomainInterfaceModel{
+			Type: net.Model.String(),
+		},
+		Driver: &libvirtxml.DomainInterfaceDriver{
+			TXMode: "iothread", /* XXX there is no way in libvirt to assign iothread ID to specific queue or interface XXX */
+		},
+	}
+}
+
+/* get the total and maximum memory of a domain with memory hot-add from its NUMA cells and virtio-mem device */
+func vmdef_memory_from_xml(vmdef *openapi.Vmdef, domain *libvirtxml.Domain) error {
+	var (
+		err error
+		dev *libvirtxml.DomainMemorydev
+		base, mib, requested, size int32
+	)
+	dev = vmdef_memorydev(domain)
+	if (dev == nil) {
+		return nil
+	}
+	if (dev.Target.Size == nil || dev.Target.Requested == nil) {
+		return errors.New("missing Memory device Size or Requested")
+	}
+	for _, cell := range domain.CPU.Numa.Cell {
+		mib, err = vmdef_mib(cell.Memory, cell.Unit)
+		if (err != nil) {
+			return err
+		}
+		base += mib
+	}
+	requested, err = vmdef_mib(dev.Target.Requested.Value, dev.Target.Requested.Unit)
+	if (err != nil) {
+		return err
+	}
+	size, err = vmdef_mib(dev.Target.Size.Value, dev.Target.Size.Unit)
+	if (err != nil) {
+		return err
+	}
+	vmdef.Memory.Total = base + requested
+	vmdef.Memory.Max = base + size
+	return nil
+}
+
+/* get the virtio-mem device used for memory hot-add, or nil */
+func vmdef_memorydev(domain *libvirtxml.Domain) *libvirtxml.DomainMemorydev {
+	if (domain.Devices == nil || domain.CPU == nil || domain.CPU.Numa == nil) {
+		return nil
+	}
+	for i := range domain.Devices.Memorydevs {
+		if (domain.Devices.Memorydevs[i].Model == "virtio-mem" && domain.Devices.Memorydevs[i].Target != nil) {
+			return &domain.Devices.Memorydevs[i]
+		}
+	}
+	return nil
+}
+
+/* convert a libvirt memory value to MiB. libvirt uses KiB by default */
+func vmdef_mib(value uint, unit string) (int32, error) {
+	var scale uint64
+	switch (unit) {
+	case "b", "bytes":
+		scale = 1
+	case "KB":
+		scale = 1000
+	case "", "k", "KiB":
+		scale = KiB
+	case "MB":
+		scale = 1000 * 1000
+	case "M", "MiB":
+		scale = MiB
+	case "GB":
+		scale = 1000 * 1000 * 1000
+	case "G", "GiB":
+		scale = GiB
+	case "TB":
+		scale = 1000 * 1000 * 1000 * 1000
+	case "T", "TiB":
+		scale = TiB
+	default:
+		return 0, errors.New("unknown memory unit " + unit)
+	}
+	return int32(uint64(value) * scale / MiB), nil
+}
+
 func vmdef_disk_from_xml(disk *openapi.Disk, domain_disk *libvirtxml.DomainDisk) error {
 	var (
 		err error
@@ -375,7 +633,8 @@ func vmdef_disk_from_xml(disk *openapi.Disk, domain_disk *libvirtxml.DomainDisk)
 		if (domain_disk.Source.File == nil) {
 			return errors.New("missing Disk File")
 		}
-		disk.Path = domain_disk.Source.File.File
+		/* a disk with snapshots points to its latest overlay, show the root disk instead */
+		disk.Path = Snapshot_root(domain_disk.Source.File.File)
 	}
 	if (domain_disk.Target == nil) {
 		return errors.New("missing Disk Target")
@@ -400,6 +659,9 @@ func vmdef_disk_from_xml(disk *openapi.Disk, domain_disk *libvirtxml.DomainDisk)
 	if (err != nil) {
 		return err
 	}
+	if (disk.Prov == openapi.DISK_PROV_LINKED) {
+		disk.Base = vmdef_disk_base(domain_disk)
+	}
 	ctrl_type = fields[2]
 	ctrl_model = fields[3]
 	err = disk.Bus.Parse(ctrl_type, ctrl_model)
@@ -432,8 +694,16 @@ func To_xml(vmdef *openapi.Vmdef, uuid string) (string, error) {
 		meta_xml string
 	)
 	var vcpus uint = vmdef_get_vcpus(vmdef)
+	var maxvcpus uint = vmdef_get_maxvcpus(vmdef)
+	var max_memory int32 = vmdef_get_max_memory(vmdef)
 	domain_vcpu := libvirtxml.DomainVCPU{
-		Value: vcpus,
+		Value: maxvcpus,
+		Current: func() uint {
+			if (vcpus < maxvcpus) {
+				return vcpus /* the others can be hot-added */
+			}
+			return 0
+		}(),
 	}
 	switch (machine.Arch()) {
 	case "aarch64":
@@ -460,7 +730,7 @@ func To_xml(vmdef *openapi.Vmdef, uuid string) (string, error) {
 		}(),
 		Check: "none",
 		MaxPhysAddr: func() *libvirtxml.DomainCPUMaxPhysAddr {
-			phys_bits := uint(bits.Len64(uint64(vmdef.Memory.Total)) + 20)
+			phys_bits := uint(bits.Len64(uint64(max_memory)) + 20)
 			if (phys_bits < 36) { /* minimum required by AMD64 spec */
 				phys_bits = 36;
 			}
@@ -470,7 +740,7 @@ func To_xml(vmdef *openapi.Vmdef, uuid string) (string, error) {
 			}
 		}(),
 		Topology: &libvirtxml.DomainCPUTopology{
-			Sockets: int(vmdef.Cpudef.Sockets),
+			Sockets: int(vmdef_get_maxsockets(vmdef)),
 			Cores: int(vmdef.Cpudef.Cores),
 			Threads: int(vmdef.Cpudef.Threads),
 		},
@@ -492,9 +762,38 @@ func To_xml(vmdef *openapi.Vmdef, uuid string) (string, error) {
 		}(),
 	}
 	domain_memory := libvirtxml.DomainMemory{
-		Value: uint(vmdef.Memory.Total),
+		Value: uint(max_memory),
 		Unit: "MiB",
 	}
+	/*
+	 * memory hot-add: the memory above the total is provided by a virtio-mem device,
+	 * which needs a NUMA cell for the boot memory. Plugging memory changes its requested size.
+	 */
+	var domain_memorydevs []libvirtxml.DomainMemorydev
+	if (max_memory > vmdef.Memory.Total) {
+		var cell_id uint = 0
+		domain_cpu.Numa = &libvirtxml.DomainNuma{
+			Cell: []libvirtxml.DomainCell{
+				{
+					ID: &cell_id,
+					CPUs: fmt.Sprintf("0-%d", maxvcpus - 1),
+					Memory: uint(vmdef.Memory.Total),
+					Unit: "MiB",
+				},
+			},
+		}
+		domain_memorydevs = []libvirtxml.DomainMemorydev{
+			{
+				Model: "virtio-mem",
+				Target: &libvirtxml.DomainMemorydevTarget{
+					Size: &libvirtxml.DomainMemorydevTargetSize{ Value: uint(max_memory - vmdef.Memory.Total), Unit: "MiB" },
+					Node: &libvirtxml.DomainMemorydevTargetNode{ Value: cell_id },
+					Block: &libvirtxml.DomainMemorydevTargetBlock{ Value: MEMORY_BLOCK, Unit: "MiB" },
+					Requested: &libvirtxml.DomainMemorydevTargetRequested{ Value: 0, Unit: "MiB" },
+				},
+			},
+		}
+	}
 	domain_memory_backing := func() *libvirtxml.DomainMemoryBacking {
 		if (vmdef.Memory.Hp) {
 			return &libvirtxml.DomainMemoryBacking{
@@ -602,52 +901,7 @@ func To_xml(vmdef *openapi.Vmdef, uuid string) (string, error) {
 	/* *** NETWORKS *** */
 	for _, net := range vmdef.Nets {
 		iothread_count += 1
-		domain_interface := libvirtxml.DomainInterface{
-			/* XMLName:,*/
-			/* Managed:,*/
-			/* TrustGuestRXFilters:,*/
-			MAC: func() *libvirtxml.DomainInterfaceMAC {
-				if (net.Mac != "") {
-					return &libvirtxml.DomainInterfaceMAC{
-						Address: net.Mac,
-					}
-				}
-				return nil
-			}(),
-			Source: func() *libvirtxml.DomainInterfaceSource {
-				if (net.Nettype == openapi.NET_BRIDGE) {
-					return &libvirtxml.DomainInterfaceSource{
-						Bridge: &libvirtxml.DomainInterfaceSourceBridge{
-							Bridge: net.Name,
-						},
-					}
-				}
-				if (net.Nettype == openapi.NET_LIBVIRT) {
-					return &libvirtxml.DomainInterfaceSource{
-						Network: &libvirtxml.DomainInterfaceSourceNetwork{
-							Network: net.Name,
-						},
-					}
-				}
-				return nil
-			}(),
-			VLan: func() *libvirtxml.DomainInterfaceVLan {
-				if (vmdef.Vlanid > 0) {
-					return &libvirtxml.DomainInterfaceVLan{
-						Tags: []libvirtxml.DomainInterfaceVLanTag{
-							{ ID: uint(vmdef.Vlanid), },
-						},
-					}
-				}
-				return nil
-			}(),
-			Model: &libvirtxml.DomainInterfaceModel{
-				Type: net.Model.String(),
-			},
-			Driver: &libvirtxml.DomainInterfaceDriver{
-				TXMode: "iothread", /* XXX there is no way in libvirt to assign iothread ID to specific queue or interface XXX */
-			},
-		}
+		domain_interface := Net_to_xml(&net, vmdef.Vlanid)
 		domain_interfaces = append(domain_interfaces, domain_interface)
 	}
 	domain_devices := libvirtxml.DomainDeviceList{
@@ -657,6 +911,7 @@ func To_xml(vmdef *openapi.Vmdef, uuid string) (string, error) {
 		Leases: domain_leases,
 		/* Filesystems:, */
 		Interfaces: domain_interfaces,
+		Memorydevs: domain_memorydevs,
 		Serials: nil,
 		Parallels: nil,
 		Consoles: []libvirtxml.DomainConsole{
@@ -693,6 +948,7 @@ func To_xml(vmdef *openapi.Vmdef, uuid string) (string, error) {
 			{
 				VNC: &libvirtxml.DomainGraphicVNC{
 					AutoPort: "yes",
+					Passwd: vmdef.VncPassword,
 				},
 			},
 		},
@@ -727,6 +983,21 @@ func To_xml(vmdef *openapi.Vmdef, uuid string) (string, error) {
 		/* Panics:, */
 		/* VSock:, */
 	}
+	if (vmdef.GuestAgent) {
+		/* libvirt generates the socket path */
+		domain_devices.Channels = append(domain_devices.Channels, libvirtxml.DomainChannel{
+			Source: &libvirtxml.DomainChardevSource{
+				UNIX: &libvirtxml.DomainChardevSourceUNIX{
+					Mode: "bind",
+				},
+			},
+			Target: &libvirtxml.DomainChannelTarget{
+				VirtIO: &libvirtxml.DomainChannelTargetVirtIO{
+					Name: GUEST_AGENT_CHANNEL,
+				},
+			},
+		})
+	}
 	domain_genid := func() *libvirtxml.DomainGenID {
 		if (vmdef.Genid == "") {
 			return nil
@@ -754,6 +1025,18 @@ func To_xml(vmdef *openapi.Vmdef, uuid string) (string, error) {
 		Description: vmdef.Name,
 		Metadata: &libvirtxml.DomainMetadata{ XML: meta_xml },
 		Memory: &domain_memory,
+		MaximumMemory: func() *libvirtxml.DomainMaxMemory {
+			if (len(domain_memorydevs) > 0) {
+				return &libvirtxml.DomainMaxMemory{ Value: uint(max_memory), Unit: "MiB", Slots: 1 }
+			}
+			return nil
+		}(),
+		CurrentMemory: func() *libvirtxml.DomainCurrentMemory {
+			if (len(domain_memorydevs) > 0) {
+				return &libvirtxml.DomainCurrentMemory{ Value: uint(vmdef.Memory.Total), Unit: "MiB" }
+			}
+			return nil
+		}(),
 		MemoryBacking: domain_memory_backing,
 		VCPU: &domain_vcpu,
 		IOThreads: uint(iothread_count),
@@ -787,6 +1070,13 @@ func From_xml(vmdef *openapi.Vmdef, xmlstr string) error {
 	vmdef.Cpudef.Sockets = int16(domain.CPU.Topology.Sockets)
 	vmdef.Cpudef.Cores = int16(domain.CPU.Topology.Cores)
 	vmdef.Cpudef.Threads = int16(domain.CPU.Topology.Threads)
+	vmdef.Cpudef.Maxsockets = 0
+	if (domain.VCPU != nil && domain.VCPU.Current > 0 && domain.VCPU.Current < domain.VCPU.Value &&
+		vmdef.Cpudef.Cores > 0 && vmdef.Cpudef.Threads > 0) {
+		/* the topology has the maximum sockets, some of which are not online */
+		vmdef.Cpudef.Maxsockets = vmdef.Cpudef.Sockets
+		vmdef.Cpudef.Sockets = int16(domain.VCPU.Current) / (vmdef.Cpudef.Cores * vmdef.Cpudef.Threads)
+	}
 	if (domain.CPU.Mode != "" && domain.CPU.Mode != "custom") {
 		vmdef.Cpudef.Model = domain.CPU.Mode
 	} else if (domain.CPU.Model != nil) {
@@ -798,6 +1088,11 @@ func From_xml(vmdef *openapi.Vmdef, xmlstr string) error {
 		return errors.New("missing Memory");
 	}
 	vmdef.Memory.Total = int32(domain.Memory.Value / KiB) /* convert from KiB to MiB */
+	vmdef.Memory.Max = 0
+	err = vmdef_memory_from_xml(vmdef, &domain)
+	if (err != nil) {
+		return err
+	}
 	if (domain.MemoryBacking != nil) {
 		vmdef.Memory.Hp = true
 	}
@@ -880,6 +1175,19 @@ func From_xml(vmdef *openapi.Vmdef, xmlstr string) error {
 		}
 		vmdef.Nets = append(vmdef.Nets, net)
 	}
+	/* Channels */
+	for _, domain_channel := range domain.Devices.Channels {
+		if (domain_channel.Target != nil && domain_channel.Target.VirtIO != nil &&
+			domain_channel.Target.VirtIO.Name == GUEST_AGENT_CHANNEL) {
+			vmdef.GuestAgent = true
+		}
+	}
+	/* Graphics (the password is only present in the XML dumped with DOMAIN_XML_SECURE) */
+	for _, domain_graphic := range domain.Devices.Graphics {
+		if (domain_graphic.VNC != nil) {
+			vmdef.VncPassword = domain_graphic.VNC.Passwd
+		}
+	}
 	if (domain.GenID != nil) {
 		vmdef.Genid = domain.GenID.Value
 	}
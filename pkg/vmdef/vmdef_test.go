@@ -19,8 +19,14 @@ package vmdef
 
 import (
 	"testing"
+	"errors"
+	"fmt"
+	"strings"
 
 	"suse.com/virtx/pkg/model"
+	"suse.com/virtx/pkg/machine"
+	"libvirt.org/go/libvirtxml"
+	. "suse.com/virtx/pkg/constants"
 )
 
 func valid_vmdef() openapi.Vmdef {
@@ -105,6 +111,9 @@ func Test_has_path(t *testing.T) {
 	if (Has_path(&vm, "/vms/ds/testvm/nonexistent.qcow2")) {
 		t.Error("should not find nonexistent path")
 	}
+	if (Get_disk(&vm, "/vms/ds/testvm/data.qcow2") != &vm.Disks[0] || Get_disk(&vm, "/vms/ds/testvm/nonexistent.qcow2") != nil) {
+		t.Error("Get_disk: unexpected disk")
+	}
 }
 
 /* *** Disk_driver *** */
@@ -170,7 +179,7 @@ func Test_vmdef_validate_disk_valid(t *testing.T) {
 		Man:    openapi.DISK_MAN_MANAGED,
 		Size:   16384,
 	}
-	err := vmdef_validate_disk(&disk)
+	err := vmdef_validate_disk(&disk, "osdisk")
 	if (err != nil) {
 		t.Fatalf("valid disk rejected: %v", err)
 	}
@@ -182,7 +191,7 @@ func Test_vmdef_validate_disk_negative_size(t *testing.T) {
 		Bus: openapi.BUS_VIRTIO_BLK, Prov: openapi.DISK_PROV_THIN, Man: openapi.DISK_MAN_MANAGED,
 		Size: -1,
 	}
-	err := vmdef_validate_disk(&disk)
+	err := vmdef_validate_disk(&disk, "osdisk")
 	if (err == nil) {
 		t.Fatal("negative size: expected error")
 	}
@@ -194,7 +203,7 @@ func Test_vmdef_validate_disk_bad_path(t *testing.T) {
 		Bus: openapi.BUS_VIRTIO_BLK, Prov: openapi.DISK_PROV_THIN, Man: openapi.DISK_MAN_MANAGED,
 		Size: 1024,
 	}
-	err := vmdef_validate_disk(&disk)
+	err := vmdef_validate_disk(&disk, "osdisk")
 	if (err == nil) {
 		t.Fatal("bad path: expected error")
 	}
@@ -206,13 +215,13 @@ func Test_vmdef_validate_disk_lun_requires_virtio_scsi(t *testing.T) {
 		Bus: openapi.BUS_SATA, Prov: openapi.DISK_PROV_NONE, Man: openapi.DISK_MAN_UNMANAGED,
 		Size: 0,
 	}
-	err := vmdef_validate_disk(&disk)
+	err := vmdef_validate_disk(&disk, "osdisk")
 	if (err == nil) {
 		t.Fatal("LUN on SATA bus: expected error")
 	}
 
 	disk.Bus = openapi.BUS_VIRTIO_SCSI
-	err = vmdef_validate_disk(&disk)
+	err = vmdef_validate_disk(&disk, "osdisk")
 	if (err != nil) {
 		t.Fatalf("LUN on VIRTIO_SCSI: unexpected error: %v", err)
 	}
@@ -228,7 +237,7 @@ func Test_vmdef_validate_disk_cdrom_bus_restrictions(t *testing.T) {
 	for _, bus := range allowed {
 		disk := base
 		disk.Bus = bus
-		err := vmdef_validate_disk(&disk)
+		err := vmdef_validate_disk(&disk, "osdisk")
 		if (err != nil) {
 			t.Errorf("CDROM on bus %d: unexpected error: %v", bus, err)
 		}
@@ -236,7 +245,7 @@ func Test_vmdef_validate_disk_cdrom_bus_restrictions(t *testing.T) {
 
 	disk := base
 	disk.Bus = openapi.BUS_VIRTIO_BLK
-	err := vmdef_validate_disk(&disk)
+	err := vmdef_validate_disk(&disk, "osdisk")
 	if (err == nil) {
 		t.Error("CDROM on VIRTIO_BLK: expected error")
 	}
@@ -452,4 +461,620 @@ func Test_validate_custom_field(t *testing.T) {
 	if (err == nil) {
 		t.Error("non-alnum custom field name: expected error")
 	}
+
+	vm.Custom = []openapi.CustomField{
+		{Name: strings.Repeat("a", CUSTOM_NAME_MAX + 1), Value: "1217"},
+	}
+	err = Validate(&vm)
+	if (err == nil) {
+		t.Error("custom field name too long: expected error")
+	}
+
+	vm.Custom = []openapi.CustomField{
+		{Name: "CID", Value: strings.Repeat("1", CUSTOM_VALUE_MAX + 1)},
+	}
+	err = Validate(&vm)
+	if (err == nil) {
+		t.Error("custom field value too long: expected error")
+	}
+
+	vm.Custom = nil
+	for i := 0; i <= CUSTOM_FIELDS_MAX; i++ {
+		vm.Custom = append(vm.Custom, openapi.CustomField{Name: fmt.Sprintf("F%d", i), Value: "1"})
+	}
+	err = Validate(&vm)
+	if (err == nil) {
+		t.Error("too many custom fields: expected error")
+	}
+}
+
+func Test_validate_error_field(t *testing.T) {
+	vm := valid_vmdef()
+	vm.Memory.Total = 0
+	err := Validate(&vm)
+	if (Error_field(err) != "memory.total") {
+		t.Errorf("zero memory: field = %q, want %q", Error_field(err), "memory.total")
+	}
+
+	vm = valid_vmdef()
+	vm.Disks = []openapi.Disk{vm.Osdisk, vm.Osdisk}
+	vm.Disks[1].Path = "/tmp/bad.qcow2"
+	err = Validate(&vm)
+	if (Error_field(err) != "disks[1].path") {
+		t.Errorf("bad disk path: field = %q, want %q", Error_field(err), "disks[1].path")
+	}
+
+	vm = valid_vmdef()
+	vm.Nets = []openapi.Net{
+		{Name: "br0", Nettype: openapi.NET_BRIDGE, Model: openapi.NET_MODEL_VIRTIO, Mac: "bad"},
+	}
+	err = Validate(&vm)
+	if (Error_field(err) != "nets[0].mac") {
+		t.Errorf("bad MAC: field = %q, want %q", Error_field(err), "nets[0].mac")
+	}
+
+	if (Error_field(errors.New("other")) != "") {
+		t.Error("non-field error: expected empty field")
+	}
+}
+
+func Test_metadata_only(t *testing.T) {
+	old := valid_vmdef()
+	vm := valid_vmdef()
+	if (!Metadata_only(&vm, &old)) {
+		t.Error("same definition: expected true")
+	}
+	vm.Name = "renamed"
+	vm.Custom = []openapi.CustomField{
+		{Name: "owner", Value: "alice"},
+	}
+	if (!Metadata_only(&vm, &old)) {
+		t.Error("name and custom fields changed: expected true")
+	}
+	vm.Disks = []openapi.Disk{}
+	if (!Metadata_only(&vm, &old)) {
+		t.Error("empty disk list: expected true")
+	}
+	vm.Memory.Total *= 2
+	if (Metadata_only(&vm, &old)) {
+		t.Error("memory changed: expected false")
+	}
+	vm = valid_vmdef()
+	vm.Disks = []openapi.Disk{vm.Osdisk}
+	if (Metadata_only(&vm, &old)) {
+		t.Error("disk added: expected false")
+	}
+}
+
+func Test_validate_vnc_password(t *testing.T) {
+	vm := valid_vmdef()
+	vm.VncPassword = "12345678"
+	if (Validate(&vm) != nil) {
+		t.Error("8 characters: expected valid")
+	}
+	vm.VncPassword = "123456789"
+	if (Error_field(Validate(&vm)) != "vnc_password") {
+		t.Error("9 characters: expected vnc_password error")
+	}
+}
+
+func Test_secrets(t *testing.T) {
+	old := valid_vmdef()
+	old.VncPassword = "s3cr3t"
+	vm := old
+	Hide_secrets(&vm)
+	if (vm.VncPassword != VNC_PASSWORD_HIDDEN) {
+		t.Errorf("hidden password is %q", vm.VncPassword)
+	}
+	Keep_secrets(&vm, &old)
+	if (vm.VncPassword != "s3cr3t" || !Metadata_only(&vm, &old)) {
+		t.Errorf("kept password is %q", vm.VncPassword)
+	}
+	vm.VncPassword = "changed"
+	Keep_secrets(&vm, &old)
+	if (vm.VncPassword != "changed") {
+		t.Errorf("changed password is %q", vm.VncPassword)
+	}
+	vm = valid_vmdef()
+	Hide_secrets(&vm)
+	if (vm.VncPassword != "") {
+		t.Error("no password: expected none hidden")
+	}
+}
+
+/* *** Snapshots *** */
+
+func Test_snapshot_path_root(t *testing.T) {
+	cases := []struct {
+		path string
+		root string
+	}{
+		{"/vms/ds/testvm/testvm.qcow2", "/vms/ds/testvm/testvm.qcow2"},
+		{"/vms/ds/testvm/testvm.qcow2@snap1", "/vms/ds/testvm/testvm.qcow2"},
+		{"/vms/ds/test@vm/testvm.qcow2", "/vms/ds/test@vm/testvm.qcow2"},
+		{"/vms/ds/testvm/test@vm.qcow2", "/vms/ds/testvm/test@vm.qcow2"},
+	}
+	for _, tc := range cases {
+		if (Snapshot_root(tc.path) != tc.root) {
+			t.Errorf("Snapshot_root(%s): expected %s, got %s", tc.path, tc.root, Snapshot_root(tc.path))
+		}
+	}
+	path := Snapshot_path("/vms/ds/testvm/testvm.raw", "snap-1")
+	if (Snapshot_root(path) != "/vms/ds/testvm/testvm.raw") {
+		t.Errorf("Snapshot_root(%s) is not the root", path)
+	}
+	if (Snapshot_driver(path) != "qcow2" || Snapshot_driver("/vms/ds/testvm/testvm.raw") != "raw") {
+		t.Error("Snapshot_driver: unexpected driver")
+	}
+}
+
+func Test_validate_snapshot_name(t *testing.T) {
+	for _, name := range []string{"snap1", "before_upgrade", "A-1"} {
+		if (Validate_snapshot_name(name) != nil) {
+			t.Errorf("%q: expected valid", name)
+		}
+	}
+	for _, name := range []string{"", "a.b", "a/b", "a@b", "snap 1", "012345678901234567890123456789012"} {
+		if (Validate_snapshot_name(name) == nil) {
+			t.Errorf("%q: expected invalid", name)
+		}
+	}
+}
+
+const snapshot_test_xml = `<domain type="kvm">
+  <devices>
+    <disk type="file" device="disk">
+      <driver name="qemu" type="qcow2"></driver>
+      <source file="/vms/ds/testvm/testvm.qcow2@snap1"></source>
+      <target dev="vda" bus="virtio"></target>
+      <alias name="ua-M_t_virtio__0"></alias>
+    </disk>
+    <disk type="file" device="disk">
+      <driver name="qemu" type="raw"></driver>
+      <source file="/vms/ds/testvm/data.raw"></source>
+      <target dev="vdb" bus="virtio"></target>
+      <alias name="ua-U_t_virtio__1"></alias>
+    </disk>
+    <disk type="file" device="cdrom">
+      <driver name="qemu" type="raw"></driver>
+      <source file="/vms/ds/iso/install.iso"></source>
+      <target dev="sda" bus="sata"></target>
+      <alias name="ua-U_U_sata__0"></alias>
+    </disk>
+  </devices>
+</domain>`
+
+func Test_snapshot_disks(t *testing.T) {
+	disks, excluded, err := Snapshot_disks(snapshot_test_xml)
+	if (err != nil) {
+		t.Fatalf("Snapshot_disks: %v", err)
+	}
+	if (len(disks) != 1 || len(excluded) != 2) {
+		t.Fatalf("expected 1 disk and 2 excluded, got %d and %d", len(disks), len(excluded))
+	}
+	if (disks[0].Target != "vda" || disks[0].Root != "/vms/ds/testvm/testvm.qcow2" ||
+		disks[0].Active != "/vms/ds/testvm/testvm.qcow2@snap1") {
+		t.Errorf("unexpected disk %+v", disks[0])
+	}
+	if (!Has_snapshots(snapshot_test_xml)) {
+		t.Error("Has_snapshots: expected true")
+	}
+	xmlstr, err := Set_disk_source(snapshot_test_xml, "vda", []string{ "/vms/ds/testvm/testvm.qcow2" })
+	if (err != nil) {
+		t.Fatalf("Set_disk_source: %v", err)
+	}
+	if (Has_snapshots(xmlstr)) {
+		t.Error("Has_snapshots: expected false after setting the root as source")
+	}
+	_, err = Set_disk_source(snapshot_test_xml, "vdz", []string{ "/vms/ds/testvm/testvm.qcow2" })
+	if (err == nil) {
+		t.Error("Set_disk_source: expected error for unknown target")
+	}
+}
+
+/* *** Clone *** */
+
+func Test_clone(t *testing.T) {
+	src := valid_vmdef()
+	src.Genid = "4e1ea6a0-9a4e-4a4a-8a4e-4a4a8a4e4a4a"
+	src.Disks = []openapi.Disk{
+		{Path: "/vms/ds/other/testvm.qcow2", Device: openapi.DEVICE_DISK, Bus: openapi.BUS_VIRTIO_BLK,
+			Prov: openapi.DISK_PROV_NONE, Man: openapi.DISK_MAN_MANAGED},
+		{Path: "/vms/ds/iso/install.iso", Device: openapi.DEVICE_CDROM, Bus: openapi.BUS_SATA,
+			Prov: openapi.DISK_PROV_NONE, Man: openapi.DISK_MAN_UNMANAGED},
+	}
+	src.Nets = []openapi.Net{{Name: "br0", Mac: "52:54:00:12:34:56", Model: openapi.NET_MODEL_VIRTIO}}
+	vm, err := Clone(&src, "clone", "1234")
+	if (err != nil) {
+		t.Fatalf("Clone: %v", err)
+	}
+	if (vm.Name != "clone" || vm.Genid != "auto" || vm.Nets[0].Mac != "") {
+		t.Errorf("unexpected clone %+v", vm)
+	}
+	if (vm.Osdisk.Path != "/vms/ds/1234/testvm.qcow2" || vm.Disks[0].Path != "/vms/ds/1234/1-testvm.qcow2") {
+		t.Errorf("unexpected managed disk paths %s %s", vm.Osdisk.Path, vm.Disks[0].Path)
+	}
+	if (vm.Disks[0].Prov != openapi.DISK_PROV_THIN) {
+		t.Error("unprovisioned managed disk: expected thin copy")
+	}
+	if (vm.Disks[1].Path != "/vms/ds/iso/install.iso") {
+		t.Error("unmanaged disk: expected shared path")
+	}
+	if (src.Nets[0].Mac == "" || src.Disks[0].Path != "/vms/ds/other/testvm.qcow2") {
+		t.Error("source definition was changed")
+	}
+	if (Validate(&vm) != nil) {
+		t.Errorf("clone is invalid: %v", Validate(&vm))
+	}
+	src.Disks[0].Device = openapi.DEVICE_LUN
+	_, err = Clone(&src, "clone", "1234")
+	if (err == nil) {
+		t.Error("managed LUN: expected error")
+	}
+}
+
+/* *** Templates *** */
+
+func Test_validate_template_path(t *testing.T) {
+	if (Validate_template_path(Template_path("sles15")) != "qcow2") {
+		t.Error("expected valid qcow2 template")
+	}
+	if (Validate_template_path("/vms/ds/templates/base.raw") != "raw") {
+		t.Error("expected valid raw template")
+	}
+	for _, path := range []string{"", "/vms/ds/base.qcow2", "/vms/ds/templates/sub/base.qcow2",
+		"/vms/ds/templates/../base.qcow2", "/vms/ds/templates/base.iso", "templates/base.qcow2"} {
+		if (Validate_template_path(path) != "") {
+			t.Errorf("%q: expected invalid", path)
+		}
+	}
+	if (Validate_template_name("sles15-sp6_golden") != nil || Validate_template_name("a.b") == nil) {
+		t.Error("Validate_template_name: unexpected result")
+	}
+}
+
+func Test_validate_linked_disk(t *testing.T) {
+	disk := openapi.Disk{
+		Path: "/vms/ds/testvm/testvm.qcow2", Device: openapi.DEVICE_DISK, Bus: openapi.BUS_VIRTIO_BLK,
+		Prov: openapi.DISK_PROV_LINKED, Man: openapi.DISK_MAN_MANAGED, Base: "/vms/ds/templates/sles15.qcow2",
+	}
+	err := vmdef_validate_disk(&disk, "osdisk")
+	if (err != nil) {
+		t.Fatalf("expected valid linked disk, got %v", err)
+	}
+	raw := disk
+	raw.Path = "/vms/ds/testvm/testvm.raw"
+	if (Error_field(vmdef_validate_disk(&raw, "osdisk")) != "osdisk.prov") {
+		t.Error("raw linked disk: expected prov error")
+	}
+	nobase := disk
+	nobase.Base = "/vms/ds/other/sles15.qcow2"
+	if (Error_field(vmdef_validate_disk(&nobase, "osdisk")) != "osdisk.base") {
+		t.Error("base outside templates: expected base error")
+	}
+	thin := disk
+	thin.Prov = openapi.DISK_PROV_THIN
+	if (Error_field(vmdef_validate_disk(&thin, "osdisk")) != "osdisk.base") {
+		t.Error("base of thin disk: expected base error")
+	}
+	template := disk
+	template.Path = "/vms/ds/templates/sles15.qcow2"
+	template.Prov = openapi.DISK_PROV_NONE
+	template.Base = ""
+	if (Error_field(vmdef_validate_disk(&template, "osdisk")) != "osdisk.path") {
+		t.Error("template as disk: expected path error")
+	}
+}
+
+const linked_test_xml = `<domain type="kvm">
+  <devices>
+    <disk type="file" device="disk">
+      <driver name="qemu" type="qcow2"></driver>
+      <source file="/vms/ds/testvm/testvm.qcow2"></source>
+      <backingStore type="file">
+        <format type="qcow2"></format>
+        <source file="/vms/ds/templates/sles15.qcow2"></source>
+        <backingStore></backingStore>
+      </backingStore>
+      <target dev="vda" bus="virtio"></target>
+      <alias name="ua-M_L_virtio__0"></alias>
+    </disk>
+  </devices>
+</domain>`
+
+func Test_linked_disk_xml(t *testing.T) {
+	var domain libvirtxml.Domain
+	var disk openapi.Disk
+
+	xmlstr, err := Set_disk_source(linked_test_xml, "vda",
+		[]string{ "/vms/ds/testvm/testvm.qcow2@snap1", "/vms/ds/testvm/testvm.qcow2" })
+	if (err != nil) {
+		t.Fatalf("Set_disk_source: %v", err)
+	}
+	err = domain.Unmarshal(xmlstr)
+	if (err != nil) {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	err = vmdef_disk_from_xml(&disk, &domain.Devices.Disks[0])
+	if (err != nil) {
+		t.Fatalf("vmdef_disk_from_xml: %v", err)
+	}
+	if (disk.Prov != openapi.DISK_PROV_LINKED || disk.Path != "/vms/ds/testvm/testvm.qcow2" ||
+		disk.Base != "/vms/ds/templates/sles15.qcow2") {
+		t.Errorf("unexpected disk %+v", disk)
+	}
+	store := domain.Devices.Disks[0].BackingStore
+	if (store == nil || store.Source.File.File != "/vms/ds/testvm/testvm.qcow2" ||
+		store.BackingStore == nil || store.BackingStore.Format.Type != "qcow2") {
+		t.Error("unexpected backing chain")
+	}
+	src := valid_vmdef()
+	src.Osdisk = disk
+	vm, err := Clone(&src, "clone", "1234")
+	if (err != nil) {
+		t.Fatalf("Clone: %v", err)
+	}
+	if (vm.Osdisk.Prov != openapi.DISK_PROV_THIN || vm.Osdisk.Base != "") {
+		t.Error("linked disk: expected full thin copy")
+	}
+}
+
+/* *** Hot-plug *** */
+
+const hotplug_test_xml = `<domain type="kvm">
+  <name>testvm</name>
+  <iothreads>2</iothreads>
+  <devices>
+    <disk type="file" device="disk">
+      <driver name="qemu" type="qcow2" iothread="1"></driver>
+      <source file="/vms/ds/testvm/testvm.qcow2"></source>
+      <target dev="vda" bus="virtio"></target>
+      <alias name="ua-M_t_virtio__0"></alias>
+    </disk>
+    <disk type="file" device="disk">
+      <driver name="qemu" type="qcow2"></driver>
+      <source file="/vms/ds/testvm/data2.qcow2"></source>
+      <target dev="sdb" bus="scsi"></target>
+      <alias name="ua-M_t_scsi_virtio-scsi_1"></alias>
+      <address type="drive" controller="1" bus="0" target="0" unit="0"></address>
+    </disk>
+    <controller type="scsi" index="1" model="virtio-scsi">
+      <driver iothread="2"></driver>
+    </controller>
+    <interface type="bridge">
+      <mac address="52:54:00:aa:bb:cc"></mac>
+      <source bridge="br0"></source>
+      <model type="virtio"></model>
+    </interface>
+  </devices>
+</domain>`
+
+func Test_hotplug_disk_xml(t *testing.T) {
+	xmlstr := hotplug_test_xml
+	_, err := Attach_disk_xml([]string{ xmlstr }, &openapi.Disk{ Path: "/vms/ds/testvm/data2.qcow2", Device: openapi.DEVICE_DISK, Bus: openapi.BUS_VIRTIO_SCSI })
+	if (err == nil) {
+		t.Error("attached disk: expected error")
+	}
+	disk := openapi.Disk{ Path: "/vms/ds/testvm/data3.qcow2", Device: openapi.DEVICE_DISK, Bus: openapi.BUS_VIRTIO_BLK, Man: openapi.DISK_MAN_UNMANAGED, Prov: openapi.DISK_PROV_NONE }
+	hd, err := Attach_disk_xml([]string{ xmlstr }, &disk)
+	if (err != nil) {
+		t.Fatalf("Attach_disk_xml: %v", err)
+	}
+	if (hd.Target != "vdb" || hd.Lease != "" || hd.Controller != "" || hd.Disk == "") {
+		t.Errorf("unexpected virtio-blk devices %+v", hd)
+	}
+	disk = openapi.Disk{ Path: "/vms/ds/testvm/data4.qcow2", Device: openapi.DEVICE_DISK, Bus: openapi.BUS_VIRTIO_SCSI, Man: openapi.DISK_MAN_MANAGED, Prov: openapi.DISK_PROV_THIN, Size: 1024 }
+	hd, err = Attach_disk_xml([]string{ xmlstr }, &disk)
+	if (err != nil) {
+		t.Fatalf("Attach_disk_xml: %v", err)
+	}
+	if (hd.Target != "sdc" || hd.Lease == "" || hd.Controller == "" || hd.Iothread != 3) {
+		t.Errorf("unexpected virtio-scsi devices %+v", hd)
+	}
+	hd, err = Detach_disk_xml(xmlstr, "/vms/ds/testvm/data2.qcow2")
+	if (err != nil) {
+		t.Fatalf("Detach_disk_xml: %v", err)
+	}
+	if (hd.Target != "sdb" || hd.Lease == "" || hd.Controller == "" || hd.Disk == "") {
+		t.Errorf("unexpected detach devices %+v", hd)
+	}
+	_, err = Detach_disk_xml(xmlstr, "/vms/ds/testvm/data3.qcow2")
+	if (err == nil) {
+		t.Error("unknown disk: expected error")
+	}
+}
+
+func Test_hotplug_net_xml(t *testing.T) {
+	var domain_interface libvirtxml.DomainInterface
+
+	net := openapi.Net{ Name: "br1", Nettype: openapi.NET_BRIDGE, Model: openapi.NET_MODEL_VIRTIO, Mac: "52:54:00:AA:BB:CC" }
+	_, err := Attach_net_xml([]string{ hotplug_test_xml }, &net, 0)
+	if (err == nil) {
+		t.Error("mac in use: expected error")
+	}
+	net.Mac = ""
+	xmlstr, err := Attach_net_xml([]string{ hotplug_test_xml }, &net, 100)
+	if (err != nil) {
+		t.Fatalf("Attach_net_xml: %v", err)
+	}
+	if (len(net.Mac) != MAC_LEN || net.Mac[:len(MAC_PREFIX)] != MAC_PREFIX) {
+		t.Errorf("unexpected generated mac %s", net.Mac)
+	}
+	err = domain_interface.Unmarshal(xmlstr)
+	if (err != nil) {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if (domain_interface.MAC.Address != net.Mac || domain_interface.Source.Bridge.Bridge != "br1" ||
+		domain_interface.VLan == nil || domain_interface.VLan.Tags[0].ID != 100) {
+		t.Errorf("unexpected interface %s", xmlstr)
+	}
+	xmlstr, err = Detach_net_xml(hotplug_test_xml, "52:54:00:AA:BB:CC")
+	if (err != nil || xmlstr == "") {
+		t.Errorf("Detach_net_xml: %v", err)
+	}
+	_, err = Detach_net_xml(hotplug_test_xml, net.Mac)
+	if (err == nil) {
+		t.Error("unknown net: expected error")
+	}
+}
+
+func Test_validate_hotadd(t *testing.T) {
+	vm := valid_vmdef()
+	vm.Cpudef.Maxsockets = 1
+	if (Validate(&vm) == nil) {
+		t.Error("maxsockets < sockets: expected error")
+	}
+	vm = valid_vmdef()
+	vm.Memory.Max = 4096 + 100
+	if (Validate(&vm) == nil) {
+		t.Error("unaligned max memory: expected error")
+	}
+	vm.Memory.Max = 2048
+	if (Validate(&vm) == nil) {
+		t.Error("max memory < total: expected error")
+	}
+	vm.Memory.Max = 4096 + 4 * MEMORY_BLOCK
+	vm.Cpudef.Maxsockets = 4
+	err := Validate(&vm)
+	if (err != nil) {
+		t.Errorf("valid hot-add: %v", err)
+	}
+	vm.Memory.Hp = true
+	if (Validate(&vm) == nil) {
+		t.Error("memory hot-add with hugepages: expected error")
+	}
+}
+
+func Test_vmdef_mib(t *testing.T) {
+	cases := []struct {
+		value uint
+		unit string
+		mib int32
+	}{
+		{ 4194304, "", 4096 },
+		{ 4194304, "KiB", 4096 },
+		{ 4096, "MiB", 4096 },
+		{ 4, "G", 4096 },
+		{ 1, "T", 1024 * 1024 },
+		{ 2 * MiB, "bytes", 2 },
+		{ 1048576, "MB", 1000000 },
+	}
+	for _, c := range cases {
+		mib, err := vmdef_mib(c.value, c.unit)
+		if (err != nil || mib != c.mib) {
+			t.Errorf("%d %q: got %d, %v, expected %d", c.value, c.unit, mib, err, c.mib)
+		}
+	}
+	_, err := vmdef_mib(1, "XB")
+	if (err == nil) {
+		t.Error("unknown unit: expected error")
+	}
+}
+
+func Test_hotadd_xml(t *testing.T) {
+	var domain libvirtxml.Domain
+	var got openapi.Vmdef
+
+	machine.Set_arch("x86_64")
+	vm := valid_vmdef()
+	vm.Cpudef.Maxsockets = 4
+	vm.Memory.Max = 4096 + 4 * MEMORY_BLOCK
+	xmlstr, err := To_xml(&vm, "1234")
+	if (err != nil) {
+		t.Fatalf("To_xml: %v", err)
+	}
+	err = domain.Unmarshal(xmlstr)
+	if (err != nil) {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if (domain.VCPU.Value != 16 || domain.VCPU.Current != 8 || domain.CPU.Topology.Sockets != 4) {
+		t.Errorf("unexpected vcpus %+v, topology %+v", domain.VCPU, domain.CPU.Topology)
+	}
+	if (domain.MaximumMemory == nil || len(domain.Devices.Memorydevs) != 1 || domain.CPU.Numa == nil) {
+		t.Fatal("expected virtio-mem device")
+	}
+	err = From_xml(&got, xmlstr)
+	if (err != nil) {
+		t.Fatalf("From_xml: %v", err)
+	}
+	if (got.Cpudef.Sockets != 2 || got.Cpudef.Maxsockets != 4 || got.Memory.Total != 4096 || got.Memory.Max != vm.Memory.Max) {
+		t.Errorf("unexpected cpudef %+v, memory %+v", got.Cpudef, got.Memory)
+	}
+	_, err = Memory_resize_xml(xmlstr, 4096 + 100)
+	if (err == nil) {
+		t.Error("unaligned memory: expected error")
+	}
+	_, err = Memory_resize_xml(xmlstr, vm.Memory.Max + MEMORY_BLOCK)
+	if (err == nil) {
+		t.Error("memory above max: expected error")
+	}
+	devstr, err := Memory_resize_xml(xmlstr, 4096 + 2 * MEMORY_BLOCK)
+	if (err != nil) {
+		t.Fatalf("Memory_resize_xml: %v", err)
+	}
+	var dev libvirtxml.DomainMemorydev
+	err = dev.Unmarshal(devstr)
+	if (err != nil || dev.Target.Requested.Value != 2 * MEMORY_BLOCK) {
+		t.Errorf("unexpected device %s: %v", devstr, err)
+	}
+	vm.Memory.Max = 0
+	vm.Cpudef.Maxsockets = 0
+	xmlstr, err = To_xml(&vm, "1234")
+	if (err != nil) {
+		t.Fatalf("To_xml: %v", err)
+	}
+	_, err = Memory_resize_xml(xmlstr, 4096 + MEMORY_BLOCK)
+	if (err == nil) {
+		t.Error("no hot-add: expected error")
+	}
+}
+
+func Test_guest_agent_xml(t *testing.T) {
+	var domain libvirtxml.Domain
+	var got openapi.Vmdef
+
+	machine.Set_arch("x86_64")
+	vm := valid_vmdef()
+	vm.GuestAgent = true
+	xmlstr, err := To_xml(&vm, "1234")
+	if (err != nil) {
+		t.Fatalf("To_xml: %v", err)
+	}
+	err = domain.Unmarshal(xmlstr)
+	if (err != nil) {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	var found bool
+	for _, channel := range domain.Devices.Channels {
+		if (channel.Target != nil && channel.Target.VirtIO != nil && channel.Target.VirtIO.Name == GUEST_AGENT_CHANNEL) {
+			found = true
+			if (channel.Source == nil || channel.Source.UNIX == nil || channel.Source.UNIX.Mode != "bind") {
+				t.Errorf("unexpected guest agent channel source %+v", channel.Source)
+			}
+		}
+	}
+	if (!found) {
+		t.Fatal("expected guest agent channel")
+	}
+	err = From_xml(&got, xmlstr)
+	if (err != nil) {
+		t.Fatalf("From_xml: %v", err)
+	}
+	if (!got.GuestAgent) {
+		t.Error("guest agent lost in From_xml")
+	}
+
+	vm.GuestAgent = false
+	xmlstr, err = To_xml(&vm, "1234")
+	if (err != nil) {
+		t.Fatalf("To_xml: %v", err)
+	}
+	got = openapi.Vmdef{}
+	err = From_xml(&got, xmlstr)
+	if (err != nil) {
+		t.Fatalf("From_xml: %v", err)
+	}
+	if (got.GuestAgent) {
+		t.Error("unexpected guest agent in From_xml")
+	}
 }
@@ -23,6 +23,8 @@ import (
 	"os"
 	"errors"
 	"path/filepath"
+	"crypto/sha256"
+	"encoding/hex"
 	. "suse.com/virtx/pkg/constants"
 )
 
@@ -70,6 +72,15 @@ func Load(host_uuid string, vm_uuid string) (string, error) {
 	return string(data), nil
 }
 
+/*
+ * return the entity tag of a registered VM definition, as used in the ETag and If-Match headers.
+ * Any change to the VM definition (define, update, register) changes the registered XML and so the tag.
+ */
+func Etag(xml string) string {
+	var sum [sha256.Size]byte = sha256.Sum256([]byte(xml))
+	return "\"" + hex.EncodeToString(sum[:16]) + "\""
+}
+
 /*
  * we split into subdirs to avoid bottlenecks with a single directory
  * containing a large number of files in NFS.
@@ -0,0 +1,352 @@
+/*
+ * Copyright (c) 2024-2026 SUSE LLC
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, see
+ * <https://www.gnu.org/licenses/>
+ */
+/*
+ * websocket: minimal RFC 6455 WebSocket connections, to stream consoles
+ * through the REST API. Messages are not preserved: a Conn is used as a byte
+ * stream, written as binary frames and read from text, binary and continuation frames.
+ */
+package websocket
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	GUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+	VERSION = "13"
+
+	OP_CONTINUATION = 0x0
+	OP_TEXT = 0x1
+	OP_BINARY = 0x2
+	OP_CLOSE = 0x8
+	OP_PING = 0x9
+	OP_PONG = 0xa
+
+	PROTOCOL_BINARY = "binary" /* subprotocol offered by noVNC and websockify clients */
+
+	CLOSE_NORMAL = 1000
+	CONTROL_MAX = 125 /* max payload of control frames */
+)
+
+type Conn struct {
+	rwc io.ReadWriteCloser
+	br *bufio.Reader
+	client bool                 /* frames sent by the client are masked */
+
+	wm sync.Mutex               /* serializes frames written by Write, Close and the pong replies */
+	close_sent bool
+
+	/* current data frame being read */
+	remaining uint64
+	masked bool
+	mask [4]byte
+	pos int
+}
+
+/* wrap the connection rwc after a completed handshake */
+func New_conn(rwc io.ReadWriteCloser, client bool) *Conn {
+	return &Conn{
+		rwc: rwc,
+		br: bufio.NewReader(rwc),
+		client: client,
+	}
+}
+
+/* the Sec-WebSocket-Accept value for the client key */
+func Accept_key(key string) string {
+	var sum [20]byte = sha1.Sum([]byte(key + GUID))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+/* return a random Sec-WebSocket-Key and the headers of a client handshake using it */
+func Client_header() (string, http.Header, error) {
+	var (
+		err error
+		nonce [16]byte
+		key string
+		h http.Header = http.Header{}
+	)
+	_, err = rand.Read(nonce[:])
+	if (err != nil) {
+		return "", nil, err
+	}
+	key = base64.StdEncoding.EncodeToString(nonce[:])
+	h.Set("Connection", "Upgrade")
+	h.Set("Upgrade", "websocket")
+	h.Set("Sec-WebSocket-Version", VERSION)
+	h.Set("Sec-WebSocket-Key", key)
+	return key, h, nil
+}
+
+/* check the server reply to a client handshake with key */
+func Check_response(resp *http.Response, key string) error {
+	if (resp.StatusCode != http.StatusSwitchingProtocols) {
+		return errors.New("websocket: unexpected status " + resp.Status)
+	}
+	if (!strings.EqualFold(resp.Header.Get("Upgrade"), "websocket")) {
+		return errors.New("websocket: missing Upgrade header")
+	}
+	if (resp.Header.Get("Sec-WebSocket-Accept") != Accept_key(key)) {
+		return errors.New("websocket: invalid Sec-WebSocket-Accept")
+	}
+	return nil
+}
+
+/* check whether r asks to upgrade to a WebSocket */
+func Is_upgrade(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get("Upgrade"), "websocket") &&
+		websocket_has_token(r.Header.Get("Connection"), "upgrade")
+}
+
+func websocket_has_token(value string, token string) bool {
+	for _, s := range strings.Split(value, ",") {
+		if (strings.EqualFold(strings.TrimSpace(s), token)) {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+ * complete the server handshake for r, taking over the connection.
+ * The only subprotocol accepted is "binary", if offered by the client.
+ * On error nothing has been written to w, so the caller can still reply.
+ */
+func Upgrade(w http.ResponseWriter, r *http.Request) (*Conn, error) {
+	var (
+		err error
+		key string
+		rc *http.ResponseController = http.NewResponseController(w)
+		conn net.Conn
+		brw *bufio.ReadWriter
+		c *Conn
+		protocol string
+	)
+	if (r.Method != http.MethodGet || !Is_upgrade(r)) {
+		return nil, errors.New("websocket: not an upgrade request")
+	}
+	if (r.Header.Get("Sec-WebSocket-Version") != VERSION) {
+		return nil, errors.New("websocket: unsupported version")
+	}
+	key = r.Header.Get("Sec-WebSocket-Key")
+	if (key == "") {
+		return nil, errors.New("websocket: missing Sec-WebSocket-Key")
+	}
+	if (websocket_has_token(r.Header.Get("Sec-WebSocket-Protocol"), PROTOCOL_BINARY)) {
+		protocol = "Sec-WebSocket-Protocol: " + PROTOCOL_BINARY + "\r\n"
+	}
+	/* the server read and write timeouts do not apply to the stream */
+	_ = rc.SetReadDeadline(time.Time{})
+	_ = rc.SetWriteDeadline(time.Time{})
+	conn, brw, err = rc.Hijack()
+	if (err != nil) {
+		return nil, errors.New("websocket: " + err.Error())
+	}
+	_, err = brw.WriteString("HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\nConnection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + Accept_key(key) + "\r\n" + protocol + "\r\n")
+	if (err == nil) {
+		err = brw.Flush()
+	}
+	if (err != nil) {
+		conn.Close()
+		return nil, err
+	}
+	c = &Conn{
+		rwc: conn,
+		br: brw.Reader,
+	}
+	return c, nil
+}
+
+/* read a frame header, returning the opcode and the payload length */
+func (c *Conn) read_header() (byte, uint64, error) {
+	var (
+		err error
+		hdr [2]byte
+		ext [8]byte
+		opcode byte
+		length uint64
+	)
+	_, err = io.ReadFull(c.br, hdr[:])
+	if (err != nil) {
+		return 0, 0, err
+	}
+	opcode = hdr[0] & 0x0f
+	c.masked = hdr[1] & 0x80 != 0
+	length = uint64(hdr[1] & 0x7f)
+	switch (length) {
+	case 126:
+		_, err = io.ReadFull(c.br, ext[:2])
+		length = uint64(binary.BigEndian.Uint16(ext[:2]))
+	case 127:
+		_, err = io.ReadFull(c.br, ext[:8])
+		length = binary.BigEndian.Uint64(ext[:8])
+	}
+	if (err != nil) {
+		return 0, 0, err
+	}
+	if (!c.client && !c.masked) {
+		return 0, 0, errors.New("websocket: unmasked frame from client")
+	}
+	if (c.masked) {
+		_, err = io.ReadFull(c.br, c.mask[:])
+		if (err != nil) {
+			return 0, 0, err
+		}
+	}
+	c.pos = 0
+	return opcode, length, nil
+}
+
+func (c *Conn) unmask(p []byte) {
+	if (!c.masked) {
+		return
+	}
+	for i := range p {
+		p[i] ^= c.mask[c.pos & 3]
+		c.pos++
+	}
+}
+
+/*
+ * read the payload of the data frames into p.
+ * Ping frames are answered, and a close frame is answered and reported as io.EOF.
+ */
+func (c *Conn) Read(p []byte) (int, error) {
+	var (
+		err error
+		n int
+		opcode byte
+		length uint64
+	)
+	for (c.remaining == 0) {
+		opcode, length, err = c.read_header()
+		if (err != nil) {
+			return 0, err
+		}
+		switch (opcode) {
+		case OP_CONTINUATION, OP_TEXT, OP_BINARY:
+			c.remaining = length
+			continue
+		case OP_CLOSE, OP_PING, OP_PONG:
+		default:
+			return 0, errors.New("websocket: unknown opcode")
+		}
+		if (length > CONTROL_MAX) {
+			return 0, errors.New("websocket: control frame too long")
+		}
+		var payload []byte = make([]byte, length)
+		_, err = io.ReadFull(c.br, payload)
+		if (err != nil) {
+			return 0, err
+		}
+		c.unmask(payload)
+		switch (opcode) {
+		case OP_PING:
+			err = c.write_frame(OP_PONG, payload)
+			if (err != nil) {
+				return 0, err
+			}
+		case OP_CLOSE:
+			_ = c.write_close()
+			return 0, io.EOF
+		}
+	}
+	if (uint64(len(p)) > c.remaining) {
+		p = p[:c.remaining]
+	}
+	n, err = c.br.Read(p)
+	c.unmask(p[:n])
+	c.remaining -= uint64(n)
+	return n, err
+}
+
+func (c *Conn) write_frame(opcode byte, p []byte) error {
+	var (
+		err error
+		hdr []byte = make([]byte, 2, 14)
+		mask [4]byte
+		payload []byte = p
+	)
+	c.wm.Lock()
+	defer c.wm.Unlock()
+	if (c.close_sent) {
+		return errors.New("websocket: connection closed")
+	}
+	hdr[0] = 0x80 | opcode
+	switch {
+	case len(p) < 126:
+		hdr[1] = byte(len(p))
+	case len(p) <= 0xffff:
+		hdr[1] = 126
+		hdr = binary.BigEndian.AppendUint16(hdr, uint16(len(p)))
+	default:
+		hdr[1] = 127
+		hdr = binary.BigEndian.AppendUint64(hdr, uint64(len(p)))
+	}
+	if (c.client) {
+		hdr[1] |= 0x80
+		_, err = rand.Read(mask[:])
+		if (err != nil) {
+			return err
+		}
+		hdr = append(hdr, mask[:]...)
+		payload = make([]byte, len(p))
+		for i := range p {
+			payload[i] = p[i] ^ mask[i & 3]
+		}
+	}
+	if (opcode == OP_CLOSE) {
+		c.close_sent = true
+	}
+	_, err = c.rwc.Write(append(hdr, payload...))
+	return err
+}
+
+func (c *Conn) write_close() error {
+	var status [2]byte
+	binary.BigEndian.PutUint16(status[:], CLOSE_NORMAL)
+	return c.write_frame(OP_CLOSE, status[:])
+}
+
+/* write p as a single binary frame */
+func (c *Conn) Write(p []byte) (int, error) {
+	var err error = c.write_frame(OP_BINARY, p)
+	if (err != nil) {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+/* send a close frame, if not done already, and close the connection */
+func (c *Conn) Close() error {
+	_ = c.write_close()
+	return c.rwc.Close()
+}
@@ -0,0 +1,158 @@
+/*
+ * Copyright (c) 2024-2026 SUSE LLC
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, see
+ * <https://www.gnu.org/licenses/>
+ */
+package websocket
+
+import (
+	"testing"
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+)
+
+/* start an echo server and connect to it */
+func test_echo(t *testing.T) *Conn {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, err := Upgrade(w, r)
+		if (err != nil) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer c.Close()
+		io.Copy(c, c)
+	}))
+	t.Cleanup(s.Close)
+	key, h, err := Client_header()
+	if (err != nil) {
+		t.Fatal(err)
+	}
+	req, _ := http.NewRequest(http.MethodGet, s.URL, nil)
+	req.Header = h
+	resp, err := http.DefaultClient.Do(req)
+	if (err != nil) {
+		t.Fatal(err)
+	}
+	err = Check_response(resp, key)
+	if (err != nil) {
+		t.Fatal(err)
+	}
+	return New_conn(resp.Body.(io.ReadWriteCloser), true)
+}
+
+func Test_accept_key(t *testing.T) {
+	/* example from RFC 6455 section 1.3 */
+	if (Accept_key("dGhlIHNhbXBsZSBub25jZQ==") != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=") {
+		t.Error("wrong accept key")
+	}
+}
+
+func Test_echo(t *testing.T) {
+	c := test_echo(t)
+	defer c.Close()
+	for _, size := range []int{1, 125, 126, 300, 65535, 70000} {
+		data := bytes.Repeat([]byte{byte(size)}, size)
+		_, err := c.Write(data)
+		if (err != nil) {
+			t.Fatalf("write %d: %v", size, err)
+		}
+		got := make([]byte, size)
+		_, err = io.ReadFull(c, got)
+		if (err != nil || !bytes.Equal(got, data)) {
+			t.Fatalf("read %d: %v", size, err)
+		}
+	}
+}
+
+func Test_ping_close(t *testing.T) {
+	c := test_echo(t)
+	err := c.write_frame(OP_PING, []byte("ping"))
+	if (err != nil) {
+		t.Fatal(err)
+	}
+	c.Write([]byte("data"))
+	got := make([]byte, 4)
+	_, err = io.ReadFull(c, got)
+	if (err != nil || string(got) != "data") {
+		t.Fatalf("expected data after pong, got %q %v", got, err)
+	}
+	err = c.write_close()
+	if (err != nil) {
+		t.Fatal(err)
+	}
+	_, err = c.Read(got)
+	if (err != io.EOF) {
+		t.Errorf("expected EOF after close, got %v", err)
+	}
+	if (c.Close() != nil) {
+		t.Error("close failed")
+	}
+}
+
+func Test_upgrade_invalid(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+	_, err := Upgrade(w, r)
+	if (err == nil) {
+		t.Error("expected error without upgrade headers")
+	}
+	r.Header.Set("Upgrade", "websocket")
+	r.Header.Set("Connection", "keep-alive, Upgrade")
+	if (!Is_upgrade(r)) {
+		t.Error("expected upgrade request")
+	}
+	_, err = Upgrade(w, r)
+	if (err == nil) {
+		t.Error("expected error without version and key")
+	}
+}
+
+func Test_protocol(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, err := Upgrade(w, r)
+		if (err != nil) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		c.Close()
+	}))
+	t.Cleanup(s.Close)
+	for _, offered := range []string{"", "base64", "binary, base64"} {
+		key, h, _ := Client_header()
+		if (offered != "") {
+			h.Set("Sec-WebSocket-Protocol", offered)
+		}
+		req, _ := http.NewRequest(http.MethodGet, s.URL, nil)
+		req.Header = h
+		resp, err := http.DefaultClient.Do(req)
+		if (err != nil) {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		err = Check_response(resp, key)
+		if (err != nil) {
+			t.Fatal(err)
+		}
+		expected := ""
+		if (offered == "binary, base64") {
+			expected = PROTOCOL_BINARY
+		}
+		if (resp.Header.Get("Sec-WebSocket-Protocol") != expected) {
+			t.Errorf("offered %q: got protocol %q", offered, resp.Header.Get("Sec-WebSocket-Protocol"))
+		}
+	}
+}